@@ -1,35 +1,65 @@
 package main
 
 import (
-	"fmt"
+	"context"
 	"log"
+	"log/slog"
+	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
-	"go.mongodb.org/mongo-driver/mongo"
-
-	"github.com/hamid-nazari/tours-in-go/internal/routes"
-	"github.com/hamid-nazari/tours-in-go/internal/services"
-	"github.com/hamid-nazari/tours-in-go/internal/utils"
+	"github.com/hamid-nazari/tours-in-go/internal/app"
+	"github.com/hamid-nazari/tours-in-go/internal/config"
+	"github.com/hamid-nazari/tours-in-go/internal/logging"
+	"github.com/hamid-nazari/tours-in-go/internal/tracing"
 )
 
 func main() {
-	godotenv.Load("../.env")
+	cfg, err := config.Load([]string{".env", "../.env"}, "")
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	var databaseClient *mongo.Client = utils.GetMongoClient()
+	logger, err := logging.New(cfg.Logging, os.Stdout)
+	if err != nil {
+		log.Fatal(err)
+	}
+	slog.SetDefault(logger)
 
-	services.UserDatabaseClient = databaseClient
+	if cfg.Environment != "development" {
+		gin.SetMode(gin.ReleaseMode)
+	}
 
-	router := gin.Default()
+	logger.Info("loaded configuration", "config", cfg.Redacted())
 
-	usersRouter := router.Group("api/v1/users")
-	// toursRouter := router.Group("api/tours")
+	ctx := context.Background()
 
-	routes.SetupTourRoutes(usersRouter)
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing, os.Stderr)
+	if err != nil {
+		logger.Error("failed to set up tracing", "error", err)
+		os.Exit(1)
+	}
 
-	if err := router.Run(":8000"); err != nil {
-		log.Fatal(err)
+	application, err := app.NewFromConfig(ctx, cfg)
+	if err != nil {
+		logger.Error("failed to start application", "error", err)
+		flushTraces(logger, shutdownTracing)
+		os.Exit(1)
+	}
+
+	err = application.Run(ctx)
+	flushTraces(logger, shutdownTracing)
+	if err != nil {
+		logger.Error("application stopped with an error", "error", err)
+		os.Exit(1)
 	}
-	fmt.Println("Server started on port 8080")
+}
+
+func flushTraces(logger *slog.Logger, shutdown func(context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
+	if err := shutdown(ctx); err != nil {
+		logger.Error("failed to flush traces", "error", err)
+	}
 }
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"github.com/hamid-nazari/tours-in-go/internal/services"
+)
+
+func createAdminCommand(ctx context.Context, env *environment, args []string) error {
+	flags := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+	name := flags.String("name", "Admin", "display name of the admin")
+	email := flags.String("email", "", "email address of the admin (required)")
+	password := flags.String("password", "", "password of the admin, generated when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *email == "" {
+		return errors.New("-email is required")
+	}
+
+	users := env.app.Users
+	ctx = services.WithAuditSource(ctx, services.AuditSource{Channel: "cli"})
+
+	if existing := users.FindUserByEmail(ctx, *email); existing != nil {
+		before := services.AuditSnapshot(existing)
+		existing.Role = "admin"
+		existing.Active = true
+		if err := users.UpdateUser(ctx, existing); err != nil {
+			return err
+		}
+		env.app.Audit.Record(ctx, services.AuditRecord{Action: models.AuditUserRoleChange, TargetType: "user", TargetId: existing.Id, Before: before, After: existing})
+		log.Printf("Promoted %s to admin", existing.Email)
+		return nil
+	}
+
+	generated := *password == ""
+	if generated {
+		token := make([]byte, 12)
+		if _, err := rand.Read(token); err != nil {
+			return fmt.Errorf("failed to generate password: %v", err)
+		}
+		*password = hex.EncodeToString(token)
+	}
+
+	user := models.NewUser()
+	user.Name = *name
+	user.Email = *email
+	user.Role = "admin"
+	user.Password = *password
+	user.PasswordConfirm = *password
+
+	if err := services.ValidateUser(*user); err != nil {
+		return err
+	}
+
+	hashedPassword, err := services.HashPassword(user.Password)
+	if err != nil {
+		return err
+	}
+	user.Password = hashedPassword
+	user.PasswordConfirm = ""
+
+	if err := users.CreateUser(ctx, user); err != nil {
+		return err
+	}
+	env.app.Audit.Record(ctx, services.AuditRecord{Action: models.AuditUserCreate, TargetType: "user", TargetId: user.Id, After: user})
+
+	log.Printf("Created admin %s (%s)", user.Email, user.Id)
+	if generated {
+		log.Printf("Generated password: %s", *password)
+	}
+	return nil
+}
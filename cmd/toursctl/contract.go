@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hamid-nazari/tours-in-go/internal/openapi"
+	"github.com/hamid-nazari/tours-in-go/pkg/toursclient"
+)
+
+var clientSchemas = map[string]any{
+	"User":        toursclient.User{},
+	"Guide":       toursclient.Guide{},
+	"Tour":        toursclient.Tour{},
+	"Review":      toursclient.Review{},
+	"ReviewReply": toursclient.ReviewReply{},
+	"FieldError":  toursclient.FieldError{},
+}
+
+func contractCommand(ctx context.Context, env *environment, args []string) error {
+	spec := openapi.Spec()
+
+	var problems []string
+
+	for _, endpoint := range toursclient.Endpoints() {
+		if !spec.Documents(endpoint.Method, endpoint.Path) {
+			problems = append(problems, fmt.Sprintf("client calls %s %s, which the API does not serve", endpoint.Method, endpoint.Path))
+		}
+	}
+
+	names := make([]string, 0, len(clientSchemas))
+	for name := range clientSchemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		server, ok := spec.Components.Schemas[name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("toursclient.%s has no %s schema in the API", name, name))
+			continue
+		}
+
+		client := openapi.Describe(clientSchemas[name])
+		for field, property := range client.Properties {
+			expected, ok := server.Properties[field]
+			switch {
+			case !ok:
+				problems = append(problems, fmt.Sprintf("toursclient.%s has field %q, which the API does not return", name, field))
+			case describe(property) != describe(expected):
+				problems = append(problems, fmt.Sprintf("toursclient.%s field %q is %s, the API uses %s", name, field, describe(property), describe(expected)))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("client is out of sync with the API:\n  %s", strings.Join(problems, "\n  "))
+	}
+
+	log.Printf("Client matches the API: %d endpoints and %d schemas checked", len(toursclient.Endpoints()), len(clientSchemas))
+	return nil
+}
+
+func describe(schema *openapi.Schema) string {
+	if schema.Ref != "" {
+		return strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+	}
+	if schema.Type == "array" && schema.Items != nil {
+		return "[]" + describe(schema.Items)
+	}
+	if schema.Format == "date-time" || schema.Format == "binary" {
+		return schema.Type + "(" + schema.Format + ")"
+	}
+	return schema.Type
+}
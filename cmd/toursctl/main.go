@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/hamid-nazari/tours-in-go/internal/app"
+	"github.com/hamid-nazari/tours-in-go/internal/config"
+	"github.com/hamid-nazari/tours-in-go/internal/repositories"
+	"github.com/hamid-nazari/tours-in-go/internal/services"
+	"github.com/hamid-nazari/tours-in-go/internal/utils"
+)
+
+type command struct {
+	name        string
+	description string
+	offline     bool
+	run         func(ctx context.Context, env *environment, args []string) error
+}
+
+type environment struct {
+	config   *config.Config
+	client   *mongo.Client
+	database *mongo.Database
+	app      *app.App
+}
+
+var commands = []command{
+	{name: "seed", description: "insert fake users, tours, reviews and bookings", run: seedCommand},
+	{name: "import", description: "import a collection from JSON or NDJSON", run: importCommand},
+	{name: "export", description: "export a collection as JSON or NDJSON", run: exportCommand},
+	{name: "wipe", description: "delete all application data, keeping indexes and migrations", run: wipeCommand},
+	{name: "create-admin", description: "create an admin user or promote an existing one", run: createAdminCommand},
+	{name: "migrate", description: "apply, revert or list migrations (up | down [steps] | status)", run: migrateCommand},
+	{name: "contract", description: "check pkg/toursclient against the OpenAPI spec", offline: true, run: contractCommand},
+	{name: "webhook-listen", description: "run a local receiver that verifies and prints webhook deliveries", offline: true, run: webhookListenCommand},
+}
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var selected *command
+	for i := range commands {
+		if commands[i].name == os.Args[1] {
+			selected = &commands[i]
+		}
+	}
+	if selected == nil {
+		usage()
+		os.Exit(2)
+	}
+
+	if selected.offline {
+		if err := selected.run(context.Background(), nil, os.Args[2:]); err != nil {
+			log.Fatalf("%s: %v", selected.name, err)
+		}
+		return
+	}
+
+	cfg, err := config.Load([]string{".env", "../.env"}, "")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx := services.WithTourScope(context.Background(), services.TourScope{IncludeSecret: true})
+
+	env, err := connect(ctx, cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = selected.run(ctx, env, os.Args[2:])
+	env.client.Disconnect(ctx)
+	if err != nil {
+		log.Fatalf("%s: %v", selected.name, err)
+	}
+}
+
+func connect(ctx context.Context, cfg *config.Config) (*environment, error) {
+	client, err := utils.ConnectMongo(ctx, cfg.Database.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	database := client.Database(cfg.Database.Name)
+
+	gin.SetMode(gin.ReleaseMode)
+	application, err := app.New(cfg, app.MongoRepositories(database, repositories.NewMongoTransactor(client, cfg.Outbox.AllowNonTransactional)))
+	if err != nil {
+		client.Disconnect(ctx)
+		return nil, err
+	}
+
+	return &environment{
+		config:   cfg,
+		client:   client,
+		database: database,
+		app:      application,
+	}, nil
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: toursctl <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, command := range commands {
+		fmt.Fprintf(os.Stderr, "  %-14s %s\n", command.name, command.description)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hamid-nazari/tours-in-go/internal/migrations"
+)
+
+func migrateCommand(ctx context.Context, env *environment, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: toursctl migrate up | down [steps] | status")
+	}
+
+	migrator := migrations.NewMigrator(env.database, migrations.All())
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		log.Printf("Applied %d migrations", applied)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			parsed, err := strconv.Atoi(args[1])
+			if err != nil || parsed < 1 {
+				return errors.New("steps must be a positive number")
+			}
+			steps = parsed
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		log.Printf("Reverted %d migrations", reverted)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%3d  %-40s %s\n", status.Version, status.Name, state)
+		}
+	default:
+		return fmt.Errorf("unknown migrate action %q", args[0])
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"github.com/hamid-nazari/tours-in-go/internal/services"
+)
+
+var (
+	firstNames   = []string{"Lisa", "Jonas", "Sophie", "Aarav", "Mina", "Leo", "Kate", "Omar", "Elena", "Cristian", "Laura", "Max"}
+	lastNames    = []string{"Brown", "Schmidt", "Moreau", "Patel", "Karimi", "Rossi", "Morales", "Hansen", "Tanaka", "Silva", "Nowak", "Fischer"}
+	tourPrefixes = []string{"The Forest", "The Sea", "The Snow", "The City", "The Desert", "The Mountain", "The Lake", "The Northern", "The Wine", "The Star"}
+	tourSuffixes = []string{"Hiker", "Explorer", "Adventurer", "Wanderer", "Camper", "Paddler", "Gazer", "Lights", "Taster", "Trail"}
+	locations    = []string{"Banff, CAN", "Miami, USA", "Aspen, USA", "New York, USA", "Sedona, USA", "Lake Tahoe, USA", "Anchorage, USA", "Napa, USA", "Joshua Tree, USA"}
+	difficulties = []string{"easy", "medium", "difficult"}
+	reviewTexts  = []string{
+		"An unforgettable trip, the guides knew every trail.",
+		"Great value for the money and a well planned route.",
+		"Beautiful scenery, although the pace was a bit fast.",
+		"Loved every minute of it, already planning the next one.",
+		"Well organized from start to finish, highly recommended.",
+		"The group was small and the atmosphere was relaxed.",
+	}
+)
+
+func seedCommand(ctx context.Context, env *environment, args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	userCount := flags.Int("users", 20, "number of regular users")
+	guideCount := flags.Int("guides", 5, "number of guides")
+	tourCount := flags.Int("tours", 10, "number of tours")
+	reviewCount := flags.Int("reviews", 40, "number of reviews")
+	bookingCount := flags.Int("bookings", 20, "number of bookings")
+	password := flags.String("password", "test1234", "password of every seeded user")
+	seed := flags.Uint64("seed", uint64(time.Now().UnixNano()), "random seed")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *tourCount > 0 && *guideCount == 0 {
+		return fmt.Errorf("at least one guide is required to seed tours")
+	}
+	if (*reviewCount > 0 || *bookingCount > 0) && (*tourCount == 0 || *userCount == 0) {
+		return fmt.Errorf("reviews and bookings need at least one tour and one user")
+	}
+
+	random := rand.New(rand.NewPCG(*seed, *seed))
+
+	hashedPassword, err := services.HashPassword(*password)
+	if err != nil {
+		return err
+	}
+
+	var guides, users []models.User
+	for i := 0; i < *guideCount+*userCount; i++ {
+		role := "user"
+		if i < *guideCount {
+			role = "guide"
+			if i == 0 {
+				role = "lead-guide"
+			}
+		}
+
+		user, err := seedUser(ctx, env, random, role, *password, hashedPassword)
+		if err != nil {
+			return err
+		}
+		if role == "user" {
+			users = append(users, *user)
+		} else {
+			guides = append(guides, *user)
+		}
+	}
+
+	var tours []models.Tour
+	for i := 0; i < *tourCount; i++ {
+		tour, err := seedTour(ctx, env, random, guides)
+		if err != nil {
+			return err
+		}
+		tours = append(tours, *tour)
+	}
+
+	for i := 0; i < *reviewCount; i++ {
+		review := models.NewReview()
+		review.Review = reviewTexts[random.IntN(len(reviewTexts))]
+		review.Rating = 3 + random.IntN(3)
+		review.Tour = tours[random.IntN(len(tours))]
+		review.User = users[random.IntN(len(users))]
+		review.Status = models.ReviewStatusPublished
+		review.CreatedAt = time.Now().Add(-time.Duration(random.IntN(365*24)) * time.Hour)
+
+		if err := env.app.Reviews.ImportReview(ctx, review, false); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < *bookingCount; i++ {
+		tour := tours[random.IntN(len(tours))]
+
+		booking := models.NewBooking()
+		booking.Tour = tour
+		booking.User = users[random.IntN(len(users))]
+		booking.Price = tour.Price
+		booking.Paid = true
+		booking.CreatedAt = time.Now().Add(-time.Duration(random.IntN(90*24)) * time.Hour)
+
+		if err := services.ValidateBooking(*booking); err != nil {
+			return err
+		}
+		if _, err := env.app.Bookings.CreateBooking(ctx, booking); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("Seeded %d guides, %d users, %d tours, %d reviews and %d bookings (seed %d)",
+		len(guides), len(users), len(tours), *reviewCount, *bookingCount, *seed)
+	return nil
+}
+
+func seedUser(ctx context.Context, env *environment, random *rand.Rand, role string, password string, hashedPassword string) (*models.User, error) {
+	first := firstNames[random.IntN(len(firstNames))]
+	last := lastNames[random.IntN(len(lastNames))]
+
+	user := models.NewUser()
+	user.Name = first + " " + last
+	user.Email = fmt.Sprintf("%s.%s.%s@example.com", strings.ToLower(first), strings.ToLower(last), user.Id[:8])
+	user.Role = role
+	user.Password = password
+	user.PasswordConfirm = password
+
+	if err := services.ValidateUser(*user); err != nil {
+		return nil, err
+	}
+
+	user.Password = hashedPassword
+	user.PasswordConfirm = ""
+
+	if err := env.app.Users.CreateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	user.Password = ""
+	return user, nil
+}
+
+func seedTour(ctx context.Context, env *environment, random *rand.Rand, guides []models.User) (*models.Tour, error) {
+	prefix := tourPrefixes[random.IntN(len(tourPrefixes))]
+	suffix := tourSuffixes[random.IntN(len(tourSuffixes))]
+	start := locations[random.IntN(len(locations))]
+
+	tour := models.NewTour()
+	tour.Name = prefix + " " + suffix
+	tour.Duration = strconv.Itoa(3 + random.IntN(12))
+	tour.Difficulty = difficulties[random.IntN(len(difficulties))]
+	tour.Price = float64(297 + random.IntN(20)*100)
+	tour.MaxGroupSize = 5 + random.IntN(20)
+	tour.RatingsAvg = 4.5
+	tour.ImageCover = fmt.Sprintf("tour-%s-cover.jpg", tour.Id[:8])
+	tour.CreatedAt = time.Now()
+	tour.SecretTour = random.IntN(10) == 0
+	tour.Summary = fmt.Sprintf("Discover %s with an experienced local guide", start)
+	tour.Description = fmt.Sprintf("%s is a %s-day %s tour starting in %s.", tour.Name, tour.Duration, tour.Difficulty, start)
+	tour.StartLocation = start
+
+	for i := 0; i < 3; i++ {
+		tour.StartDates = append(tour.StartDates, time.Now().AddDate(0, 1+random.IntN(12), 0).Truncate(24*time.Hour))
+		tour.Locations = append(tour.Locations, locations[random.IntN(len(locations))])
+	}
+	for i := 0; i < 1+random.IntN(min(3, len(guides))); i++ {
+		tour.Guides = append(tour.Guides, guides[random.IntN(len(guides))].Id)
+	}
+
+	if err := services.ValidateTour(*tour); err != nil {
+		return nil, err
+	}
+	if err := env.app.Tours.ValidateTourGuides(ctx, tour); err != nil {
+		return nil, err
+	}
+	if err := env.app.Tours.GenerateTourSlug(ctx, tour); err != nil {
+		return nil, err
+	}
+	if err := env.app.Tours.CreateTour(ctx, tour); err != nil {
+		return nil, err
+	}
+	return tour, nil
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"github.com/hamid-nazari/tours-in-go/internal/repositories"
+	"github.com/hamid-nazari/tours-in-go/internal/services"
+)
+
+const (
+	formatJSON   = "json"
+	formatNDJSON = "ndjson"
+)
+
+func exportCommand(ctx context.Context, env *environment, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	collection := flags.String("collection", "", "collection to export: users, tours, reviews or bookings")
+	format := flags.String("format", "", "json or ndjson, inferred from -out when empty")
+	out := flags.String("out", "", "file to write, stdout when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	outputFormat, err := detectFormat(*format, *out)
+	if err != nil {
+		return err
+	}
+
+	var records []any
+	switch *collection {
+	case "users":
+		users, err := env.app.Users.GetAllUsers(ctx)
+		if err != nil {
+			return err
+		}
+		records = toRecords(users)
+	case "tours":
+		tours, err := env.app.Repositories.Tours.Find(ctx, repositories.TourFilter{IncludeSecret: true})
+		if err != nil {
+			return err
+		}
+		records = toRecords(tours)
+	case "reviews":
+		reviews, err := env.app.Repositories.Reviews.Find(ctx, repositories.ReviewFilter{Sort: "oldest"})
+		if err != nil {
+			return err
+		}
+		records = toRecords(reviews)
+	case "bookings":
+		bookings, err := env.app.Bookings.GetAllBookings(ctx)
+		if err != nil {
+			return err
+		}
+		records = toRecords(bookings)
+	default:
+		return fmt.Errorf("unknown collection %q", *collection)
+	}
+
+	writer := io.Writer(os.Stdout)
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	if err := writeRecords(writer, outputFormat, records); err != nil {
+		return err
+	}
+
+	log.Printf("Exported %d %s", len(records), *collection)
+	return nil
+}
+
+func importCommand(ctx context.Context, env *environment, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	collection := flags.String("collection", "", "collection to import: users, tours, reviews or bookings")
+	format := flags.String("format", "", "json or ndjson, inferred from -file when empty")
+	file := flags.String("file", "", "file to read, stdin when empty")
+	trustStatus := flags.Bool("trust-status", false, "keep the status of imported reviews instead of moderating published ones again")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	inputFormat, err := detectFormat(*format, *file)
+	if err != nil {
+		return err
+	}
+
+	reader := io.Reader(os.Stdin)
+	if *file != "" {
+		input, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer input.Close()
+		reader = input
+	}
+
+	records, err := readRecords(reader, inputFormat)
+	if err != nil {
+		return err
+	}
+
+	var importRecord func(ctx context.Context, env *environment, record json.RawMessage) (bool, error)
+	switch *collection {
+	case "users":
+		importRecord = importUser
+	case "tours":
+		importRecord = importTour
+	case "reviews":
+		importRecord = func(ctx context.Context, env *environment, record json.RawMessage) (bool, error) {
+			return importReview(ctx, env, record, *trustStatus)
+		}
+	case "bookings":
+		importRecord = importBooking
+	default:
+		return fmt.Errorf("unknown collection %q", *collection)
+	}
+
+	imported, skipped := 0, 0
+	for i, record := range records {
+		created, err := importRecord(ctx, env, record)
+		if err != nil {
+			return fmt.Errorf("record %d: %v (imported %d, skipped %d)", i+1, err, imported, skipped)
+		}
+		if created {
+			imported++
+		} else {
+			skipped++
+		}
+	}
+
+	log.Printf("Imported %d %s, skipped %d existing", imported, *collection, skipped)
+	return nil
+}
+
+func importUser(ctx context.Context, env *environment, record json.RawMessage) (bool, error) {
+	user := models.NewUser()
+	if err := json.Unmarshal(record, user); err != nil {
+		return false, err
+	}
+
+	if env.app.Users.FindUserById(ctx, user.Id) != nil || env.app.Users.FindUserByEmail(ctx, user.Email) != nil {
+		return false, nil
+	}
+
+	if user.PasswordConfirm == "" {
+		user.PasswordConfirm = user.Password
+	}
+	if err := services.ValidateUser(*user); err != nil {
+		return false, err
+	}
+
+	if !isPasswordHash(user.Password) {
+		hashedPassword, err := services.HashPassword(user.Password)
+		if err != nil {
+			return false, err
+		}
+		user.Password = hashedPassword
+	}
+	user.PasswordConfirm = ""
+
+	return true, env.app.Users.CreateUser(ctx, user)
+}
+
+func importTour(ctx context.Context, env *environment, record json.RawMessage) (bool, error) {
+	tour := models.NewTour()
+	if err := json.Unmarshal(record, tour); err != nil {
+		return false, err
+	}
+
+	if env.app.Tours.FindTourById(ctx, tour.Id) != nil {
+		return false, nil
+	}
+
+	if err := services.ValidateTour(*tour); err != nil {
+		return false, err
+	}
+	if err := env.app.Tours.ValidateTourGuides(ctx, tour); err != nil {
+		return false, err
+	}
+	if err := env.app.Tours.GenerateTourSlug(ctx, tour); err != nil {
+		return false, err
+	}
+
+	return true, env.app.Tours.CreateTour(ctx, tour)
+}
+
+func importReview(ctx context.Context, env *environment, record json.RawMessage, trustStatus bool) (bool, error) {
+	review := models.NewReview()
+	if err := json.Unmarshal(record, review); err != nil {
+		return false, err
+	}
+
+	if env.app.Reviews.GetReviewById(ctx, review.Id) != nil {
+		return false, nil
+	}
+
+	if env.app.Users.FindUserById(ctx, review.User.Id) == nil {
+		return false, fmt.Errorf("user %s does not exist", review.User.Id)
+	}
+	review.User.Password = ""
+	review.User.PasswordConfirm = ""
+
+	return true, env.app.Reviews.ImportReview(ctx, review, trustStatus)
+}
+
+func importBooking(ctx context.Context, env *environment, record json.RawMessage) (bool, error) {
+	booking := models.NewBooking()
+	if err := json.Unmarshal(record, booking); err != nil {
+		return false, err
+	}
+
+	if _, err := env.app.Bookings.GetBooking(ctx, booking); err == nil {
+		return false, nil
+	} else if !errors.Is(err, repositories.ErrNotFound) {
+		return false, err
+	}
+
+	if err := services.ValidateBooking(*booking); err != nil {
+		return false, err
+	}
+	if env.app.Tours.FindTourById(ctx, booking.Tour.Id) == nil {
+		return false, fmt.Errorf("tour %s does not exist", booking.Tour.Id)
+	}
+	if env.app.Users.FindUserById(ctx, booking.User.Id) == nil {
+		return false, fmt.Errorf("user %s does not exist", booking.User.Id)
+	}
+	booking.User.Password = ""
+	booking.User.PasswordConfirm = ""
+
+	_, err := env.app.Bookings.CreateBooking(ctx, booking)
+	return err == nil, err
+}
+
+func detectFormat(format string, path string) (string, error) {
+	switch format {
+	case formatJSON, formatNDJSON:
+		return format, nil
+	case "":
+		if strings.HasSuffix(path, ".ndjson") || strings.HasSuffix(path, ".jsonl") {
+			return formatNDJSON, nil
+		}
+		return formatJSON, nil
+	default:
+		return "", fmt.Errorf("unknown format %q", format)
+	}
+}
+
+func toRecords[T any](items []T) []any {
+	records := make([]any, len(items))
+	for i := range items {
+		records[i] = items[i]
+	}
+	return records
+}
+
+func writeRecords(writer io.Writer, format string, records []any) error {
+	encoder := json.NewEncoder(writer)
+
+	if format == formatJSON {
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	}
+
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readRecords(reader io.Reader, format string) ([]json.RawMessage, error) {
+	decoder := json.NewDecoder(reader)
+
+	var records []json.RawMessage
+	if format == formatJSON {
+		if err := decoder.Decode(&records); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %v", err)
+		}
+		return records, nil
+	}
+
+	for {
+		var record json.RawMessage
+		err := decoder.Decode(&record)
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse NDJSON record %d: %v", len(records)+1, err)
+		}
+		records = append(records, record)
+	}
+}
+
+func isPasswordHash(password string) bool {
+	return len(password) == 60 && (strings.HasPrefix(password, "$2a$") || strings.HasPrefix(password, "$2b$") || strings.HasPrefix(password, "$2y$"))
+}
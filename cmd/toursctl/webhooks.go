@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os/signal"
+	"syscall"
+
+	"github.com/hamid-nazari/tours-in-go/pkg/toursclient"
+)
+
+func webhookListenCommand(ctx context.Context, env *environment, args []string) error {
+	flags := flag.NewFlagSet("webhook-listen", flag.ContinueOnError)
+	addr := flags.String("addr", ":9999", "address to listen on")
+	secret := flags.String("secret", "", "secret of the webhook subscription (required)")
+	status := flags.Int("status", http.StatusOK, "status code to answer with, use 500 to exercise retries")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *secret == "" {
+		return errors.New("-secret is required")
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	server := &http.Server{
+		Addr: *addr,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			event, err := toursclient.ParseWebhook(r, *secret)
+			if err != nil {
+				log.Printf("rejected delivery %s: %v", r.Header.Get("X-Webhook-Id"), err)
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+			log.Printf("%s %s (delivery %s) %s", event.Type, event.Id, r.Header.Get("X-Webhook-Id"), event.Data)
+			w.WriteHeader(*status)
+		}),
+	}
+
+	go func() {
+		<-ctx.Done()
+		server.Shutdown(context.Background())
+	}()
+
+	log.Printf("Listening for webhooks on %s, answering %d", *addr, *status)
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
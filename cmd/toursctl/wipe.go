@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var dataCollections = []string{
+	"users", "tours", "reviews", "bookings",
+	"outbox", "jobs", "job_schedules", "webhook_subscriptions", "webhook_deliveries", "realtime_events", "audit_log",
+}
+
+func wipeCommand(ctx context.Context, env *environment, args []string) error {
+	flags := flag.NewFlagSet("wipe", flag.ContinueOnError)
+	yes := flags.Bool("yes", false, "skip the confirmation prompt")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if !*yes {
+		fmt.Printf("This deletes every document in %s of database %q.\n", strings.Join(dataCollections, ", "), env.database.Name())
+		fmt.Println("Indexes and applied migrations are kept.")
+		fmt.Print("Type the database name to confirm: ")
+
+		answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read confirmation: %v", err)
+		}
+		if strings.TrimSpace(answer) != env.database.Name() {
+			return errors.New("confirmation did not match, nothing was deleted")
+		}
+	}
+
+	for _, name := range dataCollections {
+		result, err := env.database.Collection(name).DeleteMany(ctx, bson.M{})
+		if err != nil {
+			return fmt.Errorf("failed to wipe %s: %v", name, err)
+		}
+		log.Printf("Deleted %d documents from %s", result.DeletedCount, name)
+	}
+	return nil
+}
@@ -0,0 +1,393 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stripe/stripe-go/v79"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
+
+	"github.com/hamid-nazari/tours-in-go/internal/config"
+	"github.com/hamid-nazari/tours-in-go/internal/controllers"
+	"github.com/hamid-nazari/tours-in-go/internal/events"
+	"github.com/hamid-nazari/tours-in-go/internal/graph"
+	"github.com/hamid-nazari/tours-in-go/internal/health"
+	"github.com/hamid-nazari/tours-in-go/internal/jobs"
+	"github.com/hamid-nazari/tours-in-go/internal/logging"
+	"github.com/hamid-nazari/tours-in-go/internal/metrics"
+	"github.com/hamid-nazari/tours-in-go/internal/middleware"
+	"github.com/hamid-nazari/tours-in-go/internal/migrations"
+	"github.com/hamid-nazari/tours-in-go/internal/openapi"
+	"github.com/hamid-nazari/tours-in-go/internal/realtime"
+	"github.com/hamid-nazari/tours-in-go/internal/repositories"
+	"github.com/hamid-nazari/tours-in-go/internal/routes"
+	"github.com/hamid-nazari/tours-in-go/internal/services"
+	"github.com/hamid-nazari/tours-in-go/internal/tracing"
+	"github.com/hamid-nazari/tours-in-go/internal/utils"
+)
+
+type Repositories struct {
+	Users      repositories.UserRepository
+	Tours      repositories.TourRepository
+	Reviews    repositories.ReviewRepository
+	Bookings   repositories.BookingRepository
+	Webhooks   repositories.WebhookRepository
+	Outbox     repositories.OutboxRepository
+	Jobs       repositories.JobRepository
+	Audit      repositories.AuditRepository
+	Transactor repositories.Transactor
+}
+
+func MongoRepositories(database *mongo.Database, transactor repositories.Transactor) Repositories {
+	return Repositories{
+		Users:      repositories.NewMongoUserRepository(database),
+		Tours:      repositories.NewMongoTourRepository(database),
+		Reviews:    repositories.NewMongoReviewRepository(database),
+		Bookings:   repositories.NewMongoBookingRepository(database),
+		Webhooks:   repositories.NewMongoWebhookRepository(database),
+		Outbox:     repositories.NewMongoOutboxRepository(database),
+		Jobs:       repositories.NewMongoJobRepository(database),
+		Audit:      repositories.NewMongoAuditRepository(database),
+		Transactor: transactor,
+	}
+}
+
+func MemoryRepositories() Repositories {
+	users := repositories.NewMemoryUserRepository()
+	return Repositories{
+		Users:      users,
+		Tours:      repositories.NewMemoryTourRepository(users),
+		Reviews:    repositories.NewMemoryReviewRepository(),
+		Bookings:   repositories.NewMemoryBookingRepository(),
+		Webhooks:   repositories.NewMemoryWebhookRepository(),
+		Outbox:     repositories.NewMemoryOutboxRepository(),
+		Jobs:       repositories.NewMemoryJobRepository(),
+		Audit:      repositories.NewMemoryAuditRepository(),
+		Transactor: repositories.MemoryTransactor{},
+	}
+}
+
+func (r Repositories) validate() error {
+	var missing []string
+	if r.Users == nil {
+		missing = append(missing, "users")
+	}
+	if r.Tours == nil {
+		missing = append(missing, "tours")
+	}
+	if r.Reviews == nil {
+		missing = append(missing, "reviews")
+	}
+	if r.Bookings == nil {
+		missing = append(missing, "bookings")
+	}
+	if r.Webhooks == nil {
+		missing = append(missing, "webhooks")
+	}
+	if r.Outbox == nil {
+		missing = append(missing, "outbox")
+	}
+	if r.Jobs == nil {
+		missing = append(missing, "jobs")
+	}
+	if r.Audit == nil {
+		missing = append(missing, "audit")
+	}
+	if r.Transactor == nil {
+		missing = append(missing, "transactor")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing repositories: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+type App struct {
+	Config       *config.Config
+	Router       *gin.Engine
+	MongoClient  *mongo.Client
+	Repositories Repositories
+
+	Users    *services.UserService
+	Auth     *services.AuthService
+	Tours    *services.TourService
+	Reviews  *services.ReviewService
+	Bookings *services.BookingService
+	Live     *services.LiveUpdates
+	Webhooks *services.WebhookService
+	Audit    *services.AuditService
+
+	Events   *events.Outbox
+	Jobs     *jobs.Queue
+	Realtime *realtime.Hub
+	Health   *health.Registry
+	Metrics  *metrics.Metrics
+	Logger   *slog.Logger
+	Spec     *openapi.Document
+	GraphQL  *graph.Server
+
+	workers      []Worker
+	shuttingDown atomic.Bool
+}
+
+func New(cfg *config.Config, repos Repositories) (*App, error) {
+	return newApp(cfg, repos, metrics.New(), nil)
+}
+
+func newApp(cfg *config.Config, repos Repositories, appMetrics *metrics.Metrics, transport realtime.Transport) (*App, error) {
+	if cfg == nil {
+		return nil, errors.New("missing configuration")
+	}
+	if cfg.Auth.JWTSecret == "" {
+		return nil, errors.New("missing JWT secret")
+	}
+	if err := repos.validate(); err != nil {
+		return nil, err
+	}
+
+	if cfg.Stripe.SecretKey != "" {
+		stripe.Key = cfg.Stripe.SecretKey
+		stripe.SetBackend(stripe.APIBackend, stripe.GetBackendWithConfig(stripe.APIBackend, &stripe.BackendConfig{
+			HTTPClient: tracing.HTTPClient("stripe", cfg.Stripe.Timeout),
+		}))
+	}
+
+	moderationRules := services.ModerationRules{
+		BannedWords:      cfg.Reviews.BannedWords,
+		MaxLinks:         cfg.Reviews.MaxLinks,
+		MaxRepeatedChars: cfg.Reviews.MaxRepeatedChars,
+		MaxUppercaseRate: cfg.Reviews.MaxUppercaseRate,
+		RequireApproval:  cfg.Reviews.RequireApproval,
+		ReportThreshold:  cfg.Reviews.ReportThreshold,
+	}
+
+	bus := events.NewBus()
+	outbox := events.NewOutbox(repos.Outbox, repos.Transactor, bus, events.Policy{
+		PollInterval:   cfg.Outbox.PollInterval,
+		BatchSize:      cfg.Outbox.BatchSize,
+		MaxAttempts:    cfg.Outbox.MaxAttempts,
+		RetryBaseDelay: cfg.Outbox.RetryBaseDelay,
+		MaxRetryDelay:  cfg.Outbox.MaxRetryDelay,
+	}, logging.Package(slog.Default(), "events"))
+
+	queue := jobs.NewQueue(repos.Jobs, jobs.Policy{
+		Workers:        cfg.Jobs.Workers,
+		PollInterval:   cfg.Jobs.PollInterval,
+		Timeout:        cfg.Jobs.Timeout,
+		MaxAttempts:    cfg.Jobs.MaxAttempts,
+		RetryBaseDelay: cfg.Jobs.RetryBaseDelay,
+		MaxRetryDelay:  cfg.Jobs.MaxRetryDelay,
+	}, logging.Package(slog.Default(), "jobs"))
+
+	hub := realtime.NewHub(cfg.Realtime.BufferSize, transport)
+	live := services.NewLiveUpdates(hub, repos.Tours, repos.Bookings)
+	webhooks := services.NewWebhookService(repos.Webhooks, tracing.HTTPClient("webhooks", cfg.Webhooks.Timeout), services.WebhookPolicy{
+		Timeout:        cfg.Webhooks.Timeout,
+		MaxAttempts:    cfg.Webhooks.MaxAttempts,
+		RetryBaseDelay: cfg.Webhooks.RetryBaseDelay,
+		MaxRetryDelay:  cfg.Webhooks.MaxRetryDelay,
+		DisableAfter:   cfg.Webhooks.DisableAfter,
+		PollInterval:   cfg.Webhooks.PollInterval,
+		BatchSize:      cfg.Webhooks.BatchSize,
+	})
+
+	app := &App{
+		Config:       cfg,
+		Repositories: repos,
+		Users:        services.NewUserService(repos.Users, outbox),
+		Tours:        services.NewTourService(repos.Tours, repos.Users, outbox),
+		Reviews:      services.NewReviewService(repos.Reviews, repos.Tours, moderationRules, outbox),
+		Bookings:     services.NewBookingService(repos.Bookings, appMetrics, outbox, cfg.Jobs.CheckoutTTL),
+		Live:         live,
+		Webhooks:     webhooks,
+		Audit:        services.NewAuditService(repos.Audit),
+		Events:       outbox,
+		Jobs:         queue,
+		Realtime:     hub,
+		Health:       health.NewRegistry(),
+		Metrics:      appMetrics,
+		Logger:       slog.Default(),
+		Spec:         openapi.Spec(),
+	}
+
+	if cfg.Stripe.SecretKey != "" {
+		app.Health.Register(health.Check{
+			Name:    "payments",
+			Timeout: cfg.Health.CheckTimeout,
+			Run:     health.TCPCheck(cfg.Health.StripeAddress),
+		})
+	}
+	if cfg.Mail.Host != "" {
+		app.Health.Register(health.Check{
+			Name:    "mail",
+			Timeout: cfg.Health.CheckTimeout,
+			Run:     health.TCPCheck(net.JoinHostPort(cfg.Mail.Host, strconv.Itoa(cfg.Mail.Port))),
+		})
+	}
+
+	app.Auth = services.NewAuthService(app.Users, cfg.Auth.JWTSecret)
+
+	graphServer, err := graph.New(graph.Services{
+		Users:    app.Users,
+		Tours:    app.Tours,
+		Reviews:  app.Reviews,
+		Bookings: app.Bookings,
+	}, graph.Limits{
+		MaxDepth:      cfg.GraphQL.MaxDepth,
+		MaxComplexity: cfg.GraphQL.MaxComplexity,
+	})
+	if err != nil {
+		return nil, err
+	}
+	app.GraphQL = graphServer
+
+	bus.Subscribe("ratings", app.Reviews.HandleEvent, events.ReviewCreated, events.ReviewUpdated, events.ReviewDeleted)
+	bus.Subscribe("live", live.HandleEvent, events.BookingCreated, events.BookingUpdated, events.BookingCancelled, events.TourUpdated, events.ReviewCreated, events.ReviewUpdated)
+	bus.Subscribe("webhooks", webhooks.HandleEvent, events.BookingCreated, events.BookingCancelled, events.TourCreated, events.TourUpdated, events.TourDeleted)
+
+	queue.Register(services.JobExpireResetTokens, app.Users.ExpirePasswordResetTokens)
+	queue.Register(services.JobCancelAbandonedCheckouts, app.Bookings.CancelAbandonedCheckouts)
+	if err := queue.Schedule("expire-reset-tokens", cfg.Jobs.ResetTokenSchedule, services.JobExpireResetTokens, nil); err != nil {
+		return nil, err
+	}
+	if err := queue.Schedule("cancel-abandoned-checkouts", cfg.Jobs.CheckoutSchedule, services.JobCancelAbandonedCheckouts, nil); err != nil {
+		return nil, err
+	}
+
+	app.AddWorker(hub)
+	app.AddWorker(webhooks)
+	app.AddWorker(outbox)
+	app.AddWorker(queue)
+	app.Router = app.newRouter()
+
+	return app, nil
+}
+
+func NewFromConfig(ctx context.Context, cfg *config.Config) (*App, error) {
+	if cfg == nil {
+		return nil, errors.New("missing configuration")
+	}
+
+	appMetrics := metrics.New()
+
+	client, err := utils.ConnectMongo(ctx, cfg.Database.URL, appMetrics.MongoMonitor(), otelmongo.NewMonitor())
+	if err != nil {
+		return nil, err
+	}
+
+	database := client.Database(cfg.Database.Name)
+
+	if cfg.Database.MigrateOnStartup {
+		logger := logging.Package(slog.Default(), "migrations")
+
+		applied, err := migrations.NewMigrator(database, migrations.All()).UpWhenUnlocked(ctx, cfg.Database.MigrationLockTimeout)
+		if err != nil {
+			client.Disconnect(ctx)
+			return nil, err
+		}
+		logger.Info("applied migrations", "count", applied)
+	}
+
+	transactor := repositories.NewMongoTransactor(client, cfg.Outbox.AllowNonTransactional)
+	if supported, err := transactor.Supported(ctx); err != nil {
+		client.Disconnect(ctx)
+		return nil, err
+	} else if !supported {
+		if !cfg.Outbox.AllowNonTransactional {
+			client.Disconnect(ctx)
+			return nil, fmt.Errorf("%v; set outbox.allowNonTransactional to write events to the outbox without transactions", repositories.ErrTransactionsUnsupported)
+		}
+		logging.Package(slog.Default(), "events").Warn("MongoDB is not a replica set, events are written to the outbox without transactions")
+	}
+
+	transport, err := realtime.NewMongoTransport(ctx, database, logging.Package(slog.Default(), "realtime"))
+	if err != nil {
+		client.Disconnect(ctx)
+		return nil, err
+	}
+
+	repos := MongoRepositories(database, transactor)
+
+	app, err := newApp(cfg, repos, appMetrics, transport)
+	if err != nil {
+		client.Disconnect(ctx)
+		return nil, err
+	}
+	app.MongoClient = client
+	app.Health.Register(health.Check{
+		Name:    "mongo",
+		Timeout: cfg.Health.CheckTimeout,
+		Run: func(ctx context.Context) error {
+			return client.Ping(ctx, nil)
+		},
+	})
+
+	return app, nil
+}
+
+func (a *App) newRouter() *gin.Engine {
+	authController := controllers.NewAuthController(a.Users, a.Auth, a.Audit, a.Config.Auth, a.Metrics)
+	userController := controllers.NewUserController(a.Users, a.Audit)
+	tourController := controllers.NewTourController(a.Tours, a.Users, a.Audit)
+	reviewController := controllers.NewReviewController(a.Reviews, a.Tours, a.Audit)
+	bookingController := controllers.NewBookingController(a.Bookings, a.Tours, a.Config.Stripe)
+	healthController := controllers.NewHealthController(a.Health, a.ShuttingDown)
+	docsController := controllers.NewDocsController(a.Spec)
+	graphqlController := controllers.NewGraphQLController(a.GraphQL)
+	realtimeController := controllers.NewRealtimeController(a.Live, a.Tours, a.Config.Realtime)
+	webhookController := controllers.NewWebhookController(a.Webhooks, a.Audit)
+	jobController := controllers.NewJobController(a.Jobs, a.Audit)
+	auditController := controllers.NewAuditController(a.Audit)
+
+	router := gin.New()
+	router.ContextWithFallback = true
+	router.Use(
+		middleware.Tracing(a.Config.Tracing.ServiceName),
+		middleware.RequestID,
+		middleware.RequestLogger(a.Logger),
+		middleware.Metrics(a.Metrics),
+		gin.CustomRecovery(middleware.Recover),
+		middleware.HandleErrors,
+	)
+	router.NoRoute(middleware.NoRoute)
+
+	routes.SetupHealthRoutes(router.Group("/"), healthController)
+	if a.Config.Metrics.Enabled {
+		routes.SetupMetricsRoutes(router.Group("/"), a.Metrics.Handler())
+	}
+	routes.SetupDocsRoutes(router.Group("/"), docsController)
+
+	routes.SetupUserRoutes(router.Group("api/v1/users"), authController, userController, tourController)
+	routes.SetupTourRoutes(router.Group("api/v1/tours"), authController, tourController)
+	routes.SetupRealtimeRoutes(router.Group("api/v1/tours"), authController, realtimeController)
+	routes.SetupReviewRoutes(router.Group("api/v1/reviews"), authController, reviewController)
+	routes.SetupBookingRoutes(router.Group("api/v1/bookings"), authController, bookingController)
+	routes.SetupWebhookRoutes(router.Group("api/v1/webhooks"), authController, webhookController)
+	routes.SetupJobRoutes(router.Group("api/v1/jobs"), authController, jobController)
+	routes.SetupAuditRoutes(router.Group("api/v1/audit"), authController, auditController)
+	routes.SetupGraphQLRoutes(router.Group("/"), authController, graphqlController)
+
+	return router
+}
+
+func (a *App) ShuttingDown() bool {
+	return a.shuttingDown.Load()
+}
+
+func (a *App) Close(ctx context.Context) error {
+	if a.MongoClient == nil {
+		return nil
+	}
+	if err := a.MongoClient.Disconnect(ctx); err != nil {
+		return errors.Join(errors.New("failed to disconnect from MongoDB"), err)
+	}
+	return nil
+}
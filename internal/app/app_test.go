@@ -0,0 +1,25 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamid-nazari/tours-in-go/internal/config"
+	"github.com/hamid-nazari/tours-in-go/internal/openapi"
+)
+
+func TestEveryRouteIsDocumented(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := config.Defaults()
+	cfg.Auth.JWTSecret = "secret"
+
+	application, err := New(cfg, MemoryRepositories())
+	if err != nil {
+		t.Fatalf("failed to build app: %v", err)
+	}
+
+	for _, route := range openapi.Undocumented(application.Spec, application.Router.Routes()) {
+		t.Errorf("route %s is missing from the OpenAPI spec", route)
+	}
+}
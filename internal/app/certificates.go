@@ -0,0 +1,100 @@
+package app
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+type certificateReloader struct {
+	certFile string
+	keyFile  string
+	interval time.Duration
+	logger   *slog.Logger
+
+	mutex       sync.RWMutex
+	certificate *tls.Certificate
+	modTime     time.Time
+	checkedAt   time.Time
+}
+
+func newCertificateReloader(certFile string, keyFile string, interval time.Duration, logger *slog.Logger) (*certificateReloader, error) {
+	reloader := &certificateReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		interval: interval,
+		logger:   logger,
+	}
+
+	if err := reloader.reload(); err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+func (r *certificateReloader) reload() error {
+	modTime, err := r.latestModTime()
+	if err != nil {
+		return err
+	}
+
+	certificate, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %v", err)
+	}
+
+	r.mutex.Lock()
+	r.certificate = &certificate
+	r.modTime = modTime
+	r.checkedAt = time.Now()
+	r.mutex.Unlock()
+
+	return nil
+}
+
+func (r *certificateReloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, file := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to stat TLS file: %v", err)
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+func (r *certificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mutex.RLock()
+	certificate, modTime, checkedAt := r.certificate, r.modTime, r.checkedAt
+	r.mutex.RUnlock()
+
+	if r.interval <= 0 || time.Since(checkedAt) < r.interval {
+		return certificate, nil
+	}
+
+	r.mutex.Lock()
+	r.checkedAt = time.Now()
+	r.mutex.Unlock()
+
+	latest, err := r.latestModTime()
+	if err != nil || !latest.After(modTime) {
+		return certificate, nil
+	}
+
+	if err := r.reload(); err != nil {
+		r.logger.Warn("keeping previous TLS certificate", "error", err)
+		return certificate, nil
+	}
+
+	r.logger.Info("reloaded TLS certificate", "certFile", r.certFile)
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.certificate, nil
+}
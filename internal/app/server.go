@@ -0,0 +1,170 @@
+package app
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/hamid-nazari/tours-in-go/internal/grpcapi"
+	"github.com/hamid-nazari/tours-in-go/internal/logging"
+)
+
+type Worker interface {
+	Name() string
+	Run(ctx context.Context) error
+}
+
+func (a *App) AddWorker(worker Worker) {
+	a.workers = append(a.workers, worker)
+}
+
+func (a *App) newTLSConfig() (*tls.Config, error) {
+	if a.Config.Server.TLSCertFile == "" {
+		return nil, nil
+	}
+
+	reloader, err := newCertificateReloader(a.Config.Server.TLSCertFile, a.Config.Server.TLSKeyFile, a.Config.Server.TLSReloadInterval, logging.Package(a.Logger, "app"))
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+	}, nil
+}
+
+func (a *App) newHTTPServer(tlsConfig *tls.Config) *http.Server {
+	return &http.Server{
+		Addr:              ":" + a.Config.Server.Port,
+		Handler:           a.Router,
+		ReadTimeout:       a.Config.Server.ReadTimeout,
+		ReadHeaderTimeout: a.Config.Server.ReadHeaderTimeout,
+		WriteTimeout:      a.Config.Server.WriteTimeout,
+		IdleTimeout:       a.Config.Server.IdleTimeout,
+		TLSConfig:         tlsConfig,
+	}
+}
+
+func (a *App) newGRPCServer(tlsConfig *tls.Config) *grpcapi.Server {
+	return grpcapi.New(a.Config.GRPC, grpcapi.Services{
+		Auth:     a.Auth,
+		Users:    a.Users,
+		Tours:    a.Tours,
+		Reviews:  a.Reviews,
+		Bookings: a.Bookings,
+	}, a.Logger, tlsConfig)
+}
+
+func (a *App) Run(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger := logging.Package(a.Logger, "app")
+
+	tlsConfig, err := a.newTLSConfig()
+	if err != nil {
+		return errors.Join(err, a.Close(ctx))
+	}
+	server := a.newHTTPServer(tlsConfig)
+	server.RegisterOnShutdown(a.Realtime.Close)
+
+	var grpcServer *grpcapi.Server
+	var grpcListener net.Listener
+	if a.Config.GRPC.Enabled {
+		grpcListener, err = net.Listen("tcp", ":"+a.Config.GRPC.Port)
+		if err != nil {
+			return errors.Join(fmt.Errorf("failed to listen for gRPC: %v", err), a.Close(ctx))
+		}
+		grpcServer = a.newGRPCServer(tlsConfig)
+	}
+
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	var workers sync.WaitGroup
+	for _, worker := range a.workers {
+		workers.Add(1)
+		go func(worker Worker) {
+			defer workers.Done()
+			if err := worker.Run(workersCtx); err != nil && !errors.Is(err, context.Canceled) {
+				logger.Error("worker stopped", "worker", worker.Name(), "error", err)
+			}
+		}(worker)
+	}
+
+	serverErrors := make(chan error, 2)
+	go func() {
+		logger.Info("server started", "port", a.Config.Server.Port, "tls", server.TLSConfig != nil)
+		if server.TLSConfig != nil {
+			serverErrors <- server.ListenAndServeTLS("", "")
+		} else {
+			serverErrors <- server.ListenAndServe()
+		}
+	}()
+	if grpcServer != nil {
+		go func() {
+			logger.Info("gRPC server started", "port", a.Config.GRPC.Port, "tls", tlsConfig != nil)
+			if err := grpcServer.Serve(grpcListener); err != nil {
+				serverErrors <- fmt.Errorf("gRPC %v", err)
+			}
+		}()
+	}
+
+	var serveErr error
+	select {
+	case err := <-serverErrors:
+		if !errors.Is(err, http.ErrServerClosed) {
+			serveErr = fmt.Errorf("server failed: %v", err)
+		}
+	case <-ctx.Done():
+		logger.Info("shutdown signal received, draining in-flight requests")
+	}
+
+	a.shuttingDown.Store(true)
+	if serveErr == nil && a.Config.Server.ShutdownDelay > 0 {
+		logger.Info("reporting not ready before draining", "delay", a.Config.Server.ShutdownDelay.String())
+		time.Sleep(a.Config.Server.ShutdownDelay)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), a.Config.Server.ShutdownTimeout)
+	defer cancel()
+
+	var shutdownErrors []error
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		shutdownErrors = append(shutdownErrors, fmt.Errorf("failed to drain server: %v", err))
+	}
+	if grpcServer != nil {
+		if err := grpcServer.Shutdown(shutdownCtx); err != nil {
+			shutdownErrors = append(shutdownErrors, fmt.Errorf("failed to drain gRPC server: %v", err))
+		}
+	}
+
+	stopWorkers()
+	workersDone := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(workersDone)
+	}()
+	select {
+	case <-workersDone:
+	case <-shutdownCtx.Done():
+		shutdownErrors = append(shutdownErrors, errors.New("timed out waiting for background workers"))
+	}
+
+	if err := a.Close(shutdownCtx); err != nil {
+		shutdownErrors = append(shutdownErrors, err)
+	}
+
+	logger.Info("server stopped")
+
+	return errors.Join(append([]error{serveErr}, shutdownErrors...)...)
+}
@@ -0,0 +1,94 @@
+package apperrors
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	CodeBadRequest   = "bad_request"
+	CodeValidation   = "validation_failed"
+	CodeUnauthorized = "unauthorized"
+	CodeForbidden    = "forbidden"
+	CodeNotFound     = "not_found"
+	CodeConflict     = "conflict"
+	CodeInternal     = "internal_error"
+)
+
+type Error struct {
+	Code    string
+	Status  int
+	Message string
+	Details any
+	Err     error
+}
+
+func New(code string, status int, message string) *Error {
+	return &Error{
+		Code:    code,
+		Status:  status,
+		Message: message,
+	}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) WithDetails(details any) *Error {
+	copied := *e
+	copied.Details = details
+	return &copied
+}
+
+func (e *Error) Wrap(err error) *Error {
+	copied := *e
+	copied.Err = err
+	return &copied
+}
+
+func BadRequest(message string) *Error {
+	return New(CodeBadRequest, http.StatusBadRequest, message)
+}
+
+func Unauthorized(message string) *Error {
+	return New(CodeUnauthorized, http.StatusUnauthorized, message)
+}
+
+func Forbidden(message string) *Error {
+	return New(CodeForbidden, http.StatusForbidden, message)
+}
+
+func NotFound(message string) *Error {
+	return New(CodeNotFound, http.StatusNotFound, message)
+}
+
+func Conflict(message string) *Error {
+	return New(CodeConflict, http.StatusConflict, message)
+}
+
+func Internal(err error) *Error {
+	return New(CodeInternal, http.StatusInternalServerError, "Something went wrong").Wrap(err)
+}
+
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return Internal(err)
+}
+
+func Abort(c *gin.Context, err error) {
+	c.Error(err)
+	c.Abort()
+}
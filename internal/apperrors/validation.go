@@ -0,0 +1,98 @@
+package apperrors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func Validation(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	var validationErrors validator.ValidationErrors
+	var typeError *json.UnmarshalTypeError
+	var syntaxError *json.SyntaxError
+
+	switch {
+	case errors.As(err, &validationErrors):
+		details := make([]FieldError, len(validationErrors))
+		for i, fieldError := range validationErrors {
+			details[i] = translate(fieldError)
+		}
+		return New(CodeValidation, http.StatusBadRequest, "Validation failed").WithDetails(details).Wrap(err)
+	case errors.As(err, &typeError):
+		return New(CodeValidation, http.StatusBadRequest, "Validation failed").WithDetails([]FieldError{{
+			Field:   typeError.Field,
+			Rule:    "type",
+			Message: fmt.Sprintf("must be of type %s", typeError.Type.Kind()),
+		}}).Wrap(err)
+	case errors.As(err, &syntaxError), errors.Is(err, io.ErrUnexpectedEOF):
+		return BadRequest("Request body is not valid JSON").Wrap(err)
+	case errors.Is(err, io.EOF):
+		return BadRequest("Request body is required").Wrap(err)
+	default:
+		return BadRequest("Invalid request").Wrap(err)
+	}
+}
+
+func InvalidField(field string, rule string, message string) *Error {
+	return New(CodeValidation, http.StatusBadRequest, "Validation failed").WithDetails([]FieldError{{
+		Field:   field,
+		Rule:    rule,
+		Message: message,
+	}})
+}
+
+func translate(fieldError validator.FieldError) FieldError {
+	field := fieldError.Namespace()
+	if _, rest, ok := strings.Cut(field, "."); ok {
+		field = rest
+	}
+
+	unit := ""
+	switch fieldError.Kind() {
+	case reflect.String:
+		unit = " characters"
+	case reflect.Slice, reflect.Map, reflect.Array:
+		unit = " items"
+	}
+
+	var message string
+	switch fieldError.Tag() {
+	case "required":
+		message = "is required"
+	case "email":
+		message = "must be a valid email address"
+	case "min", "gte":
+		message = fmt.Sprintf("must be at least %s%s", fieldError.Param(), unit)
+	case "max", "lte":
+		message = fmt.Sprintf("must be at most %s%s", fieldError.Param(), unit)
+	case "oneof":
+		message = "must be one of: " + strings.ReplaceAll(fieldError.Param(), " ", ", ")
+	case "eqfield":
+		message = "must match " + strings.ToLower(fieldError.Param()[:1]) + fieldError.Param()[1:]
+	default:
+		message = fmt.Sprintf("failed the %q rule", fieldError.Tag())
+	}
+
+	return FieldError{
+		Field:   field,
+		Rule:    fieldError.Tag(),
+		Message: message,
+	}
+}
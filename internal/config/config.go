@@ -0,0 +1,328 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "[REDACTED]"
+
+type Config struct {
+	Environment string         `yaml:"environment" env:"APP_ENV" default:"development"`
+	Server      ServerConfig   `yaml:"server"`
+	Database    DatabaseConfig `yaml:"database"`
+	Auth        AuthConfig     `yaml:"auth"`
+	Stripe      StripeConfig   `yaml:"stripe"`
+	Mail        MailConfig     `yaml:"mail"`
+	Health      HealthConfig   `yaml:"health"`
+	Reviews     ReviewsConfig  `yaml:"reviews"`
+	Logging     LoggingConfig  `yaml:"logging"`
+	Metrics     MetricsConfig  `yaml:"metrics"`
+	Tracing     TracingConfig  `yaml:"tracing"`
+	GraphQL     GraphQLConfig  `yaml:"graphql"`
+	GRPC        GRPCConfig     `yaml:"grpc"`
+	Realtime    RealtimeConfig `yaml:"realtime"`
+	Webhooks    WebhooksConfig `yaml:"webhooks"`
+	Outbox      OutboxConfig   `yaml:"outbox"`
+	Jobs        JobsConfig     `yaml:"jobs"`
+}
+
+type ServerConfig struct {
+	Port              string        `yaml:"port" env:"PORT" default:"8000"`
+	ReadTimeout       time.Duration `yaml:"readTimeout" env:"SERVER_READ_TIMEOUT" default:"15s"`
+	ReadHeaderTimeout time.Duration `yaml:"readHeaderTimeout" env:"SERVER_READ_HEADER_TIMEOUT" default:"5s"`
+	WriteTimeout      time.Duration `yaml:"writeTimeout" env:"SERVER_WRITE_TIMEOUT" default:"30s"`
+	IdleTimeout       time.Duration `yaml:"idleTimeout" env:"SERVER_IDLE_TIMEOUT" default:"120s"`
+	ShutdownTimeout   time.Duration `yaml:"shutdownTimeout" env:"SERVER_SHUTDOWN_TIMEOUT" default:"30s"`
+	ShutdownDelay     time.Duration `yaml:"shutdownDelay" env:"SERVER_SHUTDOWN_DELAY" default:"5s"`
+	TLSCertFile       string        `yaml:"tlsCertFile" env:"TLS_CERT_FILE"`
+	TLSKeyFile        string        `yaml:"tlsKeyFile" env:"TLS_KEY_FILE"`
+	TLSReloadInterval time.Duration `yaml:"tlsReloadInterval" env:"TLS_RELOAD_INTERVAL" default:"1m"`
+}
+
+type DatabaseConfig struct {
+	URL                  string        `yaml:"url" env:"DB_URL" required:"true" secret:"true"`
+	Name                 string        `yaml:"name" env:"DB_NAME" default:"Tours"`
+	MigrateOnStartup     bool          `yaml:"migrateOnStartup" env:"DB_MIGRATE_ON_STARTUP" default:"false"`
+	MigrationLockTimeout time.Duration `yaml:"migrationLockTimeout" env:"DB_MIGRATION_LOCK_TIMEOUT" default:"2m"`
+}
+
+type AuthConfig struct {
+	JWTSecret    string        `yaml:"jwtSecret" env:"JWT_SECRET" required:"true" secret:"true"`
+	JWTExpiresIn time.Duration `yaml:"jwtExpiresIn" env:"JWT_EXPIRES_IN" default:"24h"`
+}
+
+type StripeConfig struct {
+	SecretKey  string        `yaml:"secretKey" env:"STRIPE_SECRET_KEY" secret:"true"`
+	SuccessURL string        `yaml:"successUrl" env:"STRIPE_SUCCESS_URL" default:"https://example.com/success"`
+	CancelURL  string        `yaml:"cancelUrl" env:"STRIPE_CANCEL_URL" default:"https://example.com/canceled"`
+	Currency   string        `yaml:"currency" env:"STRIPE_CURRENCY" default:"usd"`
+	Timeout    time.Duration `yaml:"timeout" env:"STRIPE_TIMEOUT" default:"80s"`
+}
+
+type MailConfig struct {
+	Host     string `yaml:"host" env:"SMTP_HOST"`
+	Port     int    `yaml:"port" env:"SMTP_PORT" default:"587"`
+	Username string `yaml:"username" env:"SMTP_USERNAME"`
+	Password string `yaml:"password" env:"SMTP_PASSWORD" secret:"true"`
+	From     string `yaml:"from" env:"MAIL_FROM" default:"Natours <hello@natours.io>"`
+}
+
+type HealthConfig struct {
+	CheckTimeout  time.Duration `yaml:"checkTimeout" env:"HEALTH_CHECK_TIMEOUT" default:"2s"`
+	StripeAddress string        `yaml:"stripeAddress" env:"HEALTH_STRIPE_ADDRESS" default:"api.stripe.com:443"`
+}
+
+type ReviewsConfig struct {
+	BannedWords      []string `yaml:"bannedWords" env:"REVIEW_BANNED_WORDS" default:"fuck,shit,bitch,asshole,viagra,casino"`
+	MaxLinks         int      `yaml:"maxLinks" env:"REVIEW_MAX_LINKS" default:"0"`
+	MaxRepeatedChars int      `yaml:"maxRepeatedChars" env:"REVIEW_MAX_REPEATED_CHARS" default:"5"`
+	MaxUppercaseRate float64  `yaml:"maxUppercaseRate" env:"REVIEW_MAX_UPPERCASE_RATE" default:"0.7"`
+	RequireApproval  bool     `yaml:"requireApproval" env:"REVIEW_REQUIRE_APPROVAL" default:"true"`
+	ReportThreshold  int      `yaml:"reportThreshold" env:"REVIEW_REPORT_THRESHOLD" default:"3"`
+}
+
+type LoggingConfig struct {
+	Level  string   `yaml:"level" env:"LOG_LEVEL" default:"info"`
+	Format string   `yaml:"format" env:"LOG_FORMAT" default:"json"`
+	Levels []string `yaml:"levels" env:"LOG_LEVELS"`
+}
+
+type MetricsConfig struct {
+	Enabled bool `yaml:"enabled" env:"METRICS_ENABLED" default:"true"`
+}
+
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter" env:"TRACING_EXPORTER" default:"none"`
+	Endpoint    string  `yaml:"endpoint" env:"TRACING_OTLP_ENDPOINT"`
+	ServiceName string  `yaml:"serviceName" env:"TRACING_SERVICE_NAME" default:"tours-api"`
+	SampleRatio float64 `yaml:"sampleRatio" env:"TRACING_SAMPLE_RATIO" default:"1"`
+}
+
+type GraphQLConfig struct {
+	MaxDepth      int `yaml:"maxDepth" env:"GRAPHQL_MAX_DEPTH" default:"8"`
+	MaxComplexity int `yaml:"maxComplexity" env:"GRAPHQL_MAX_COMPLEXITY" default:"1000"`
+}
+
+type GRPCConfig struct {
+	Enabled    bool     `yaml:"enabled" env:"GRPC_ENABLED" default:"true"`
+	Port       string   `yaml:"port" env:"GRPC_PORT" default:"9090"`
+	APIKeys    []string `yaml:"apiKeys" env:"GRPC_API_KEYS" secret:"true"`
+	Reflection bool     `yaml:"reflection" env:"GRPC_REFLECTION" default:"true"`
+}
+
+type RealtimeConfig struct {
+	BufferSize        int           `yaml:"bufferSize" env:"REALTIME_BUFFER_SIZE" default:"16"`
+	HeartbeatInterval time.Duration `yaml:"heartbeatInterval" env:"REALTIME_HEARTBEAT_INTERVAL" default:"25s"`
+	MaxSubscriptions  int           `yaml:"maxSubscriptions" env:"REALTIME_MAX_SUBSCRIPTIONS" default:"20"`
+	AllowedOrigins    []string      `yaml:"allowedOrigins" env:"REALTIME_ALLOWED_ORIGINS"`
+}
+
+type WebhooksConfig struct {
+	Timeout        time.Duration `yaml:"timeout" env:"WEBHOOK_TIMEOUT" default:"10s"`
+	MaxAttempts    int           `yaml:"maxAttempts" env:"WEBHOOK_MAX_ATTEMPTS" default:"8"`
+	RetryBaseDelay time.Duration `yaml:"retryBaseDelay" env:"WEBHOOK_RETRY_BASE_DELAY" default:"30s"`
+	MaxRetryDelay  time.Duration `yaml:"maxRetryDelay" env:"WEBHOOK_MAX_RETRY_DELAY" default:"1h"`
+	DisableAfter   int           `yaml:"disableAfter" env:"WEBHOOK_DISABLE_AFTER" default:"20"`
+	PollInterval   time.Duration `yaml:"pollInterval" env:"WEBHOOK_POLL_INTERVAL" default:"5s"`
+	BatchSize      int           `yaml:"batchSize" env:"WEBHOOK_BATCH_SIZE" default:"20"`
+}
+
+type OutboxConfig struct {
+	PollInterval   time.Duration `yaml:"pollInterval" env:"OUTBOX_POLL_INTERVAL" default:"1s"`
+	BatchSize      int           `yaml:"batchSize" env:"OUTBOX_BATCH_SIZE" default:"50"`
+	MaxAttempts    int           `yaml:"maxAttempts" env:"OUTBOX_MAX_ATTEMPTS" default:"10"`
+	RetryBaseDelay time.Duration `yaml:"retryBaseDelay" env:"OUTBOX_RETRY_BASE_DELAY" default:"1s"`
+	MaxRetryDelay  time.Duration `yaml:"maxRetryDelay" env:"OUTBOX_MAX_RETRY_DELAY" default:"5m"`
+
+	AllowNonTransactional bool `yaml:"allowNonTransactional" env:"OUTBOX_ALLOW_NON_TRANSACTIONAL" default:"false"`
+}
+
+type JobsConfig struct {
+	Workers            int           `yaml:"workers" env:"JOBS_WORKERS" default:"4"`
+	PollInterval       time.Duration `yaml:"pollInterval" env:"JOBS_POLL_INTERVAL" default:"5s"`
+	Timeout            time.Duration `yaml:"timeout" env:"JOBS_TIMEOUT" default:"5m"`
+	MaxAttempts        int           `yaml:"maxAttempts" env:"JOBS_MAX_ATTEMPTS" default:"5"`
+	RetryBaseDelay     time.Duration `yaml:"retryBaseDelay" env:"JOBS_RETRY_BASE_DELAY" default:"10s"`
+	MaxRetryDelay      time.Duration `yaml:"maxRetryDelay" env:"JOBS_MAX_RETRY_DELAY" default:"30m"`
+	ResetTokenSchedule string        `yaml:"resetTokenSchedule" env:"JOBS_RESET_TOKEN_SCHEDULE" default:"*/15 * * * *"`
+	CheckoutSchedule   string        `yaml:"checkoutSchedule" env:"JOBS_CHECKOUT_SCHEDULE" default:"*/15 * * * *"`
+	CheckoutTTL        time.Duration `yaml:"checkoutTTL" env:"JOBS_CHECKOUT_TTL" default:"1h"`
+}
+
+type ValidationError struct {
+	Missing []string
+	Invalid []string
+}
+
+func (e *ValidationError) Error() string {
+	var parts []string
+	if len(e.Missing) > 0 {
+		parts = append(parts, "missing required configuration: "+strings.Join(e.Missing, ", "))
+	}
+	if len(e.Invalid) > 0 {
+		parts = append(parts, "invalid configuration: "+strings.Join(e.Invalid, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func Defaults() *Config {
+	cfg := &Config{}
+	walk(reflect.ValueOf(cfg).Elem(), func(field reflect.Value, tag reflect.StructTag) {
+		if value, ok := tag.Lookup("default"); ok {
+			setField(field, value)
+		}
+	})
+	return cfg
+}
+
+func Load(envFiles []string, yamlFile string) (*Config, error) {
+	for _, envFile := range envFiles {
+		if err := godotenv.Load(envFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to load %s: %v", envFile, err)
+		}
+	}
+
+	cfg := Defaults()
+
+	if yamlFile == "" {
+		yamlFile = os.Getenv("CONFIG_FILE")
+	}
+	if yamlFile != "" {
+		content, err := os.ReadFile(yamlFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %v", err)
+		}
+		if err := yaml.Unmarshal(content, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config file: %v", err)
+		}
+	}
+
+	validationError := &ValidationError{}
+
+	walk(reflect.ValueOf(cfg).Elem(), func(field reflect.Value, tag reflect.StructTag) {
+		key := tag.Get("env")
+		if value, ok := os.LookupEnv(key); ok && key != "" {
+			if err := setField(field, value); err != nil {
+				validationError.Invalid = append(validationError.Invalid, fmt.Sprintf("%s (%v)", key, err))
+			}
+		}
+		if tag.Get("required") == "true" && field.IsZero() {
+			validationError.Missing = append(validationError.Missing, key)
+		}
+	})
+
+	if (cfg.Server.TLSCertFile == "") != (cfg.Server.TLSKeyFile == "") {
+		validationError.Invalid = append(validationError.Invalid, "TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	if cfg.Realtime.HeartbeatInterval <= 0 {
+		validationError.Invalid = append(validationError.Invalid, "REALTIME_HEARTBEAT_INTERVAL must be positive")
+	}
+	if cfg.Webhooks.PollInterval <= 0 || cfg.Webhooks.BatchSize <= 0 {
+		validationError.Invalid = append(validationError.Invalid, "WEBHOOK_POLL_INTERVAL and WEBHOOK_BATCH_SIZE must be positive")
+	}
+	if cfg.Outbox.PollInterval <= 0 || cfg.Outbox.BatchSize <= 0 {
+		validationError.Invalid = append(validationError.Invalid, "OUTBOX_POLL_INTERVAL and OUTBOX_BATCH_SIZE must be positive")
+	}
+	if cfg.Jobs.Workers <= 0 || cfg.Jobs.PollInterval <= 0 || cfg.Jobs.Timeout <= 0 {
+		validationError.Invalid = append(validationError.Invalid, "JOBS_WORKERS, JOBS_POLL_INTERVAL and JOBS_TIMEOUT must be positive")
+	}
+
+	if len(validationError.Missing) > 0 || len(validationError.Invalid) > 0 {
+		return nil, validationError
+	}
+
+	return cfg, nil
+}
+
+func (c Config) Redacted() Config {
+	walk(reflect.ValueOf(&c).Elem(), func(field reflect.Value, tag reflect.StructTag) {
+		if tag.Get("secret") != "true" || field.IsZero() {
+			return
+		}
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(redacted)
+		case reflect.Slice:
+			field.Set(reflect.ValueOf([]string{redacted}))
+		}
+	})
+	return c
+}
+
+func (c Config) String() string {
+	content, err := json.Marshal(c.Redacted())
+	if err != nil {
+		return "config: " + err.Error()
+	}
+	return string(content)
+}
+
+func walk(value reflect.Value, visit func(field reflect.Value, tag reflect.StructTag)) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		structField := value.Type().Field(i)
+
+		if field.Kind() == reflect.Struct && structField.Type != reflect.TypeOf(time.Time{}) {
+			walk(field, visit)
+			continue
+		}
+		visit(field, structField.Tag)
+	}
+}
+
+func setField(field reflect.Value, value string) error {
+	switch field.Interface().(type) {
+	case time.Duration:
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(duration))
+		return nil
+	case []string:
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(parsed)
+	case reflect.Int, reflect.Int64:
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(parsed)
+	case reflect.Float64:
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(parsed)
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamid-nazari/tours-in-go/internal/apperrors"
+	"github.com/hamid-nazari/tours-in-go/internal/logging"
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"github.com/hamid-nazari/tours-in-go/internal/repositories"
+	"github.com/hamid-nazari/tours-in-go/internal/services"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+type AuditController struct {
+	audit *services.AuditService
+}
+
+func NewAuditController(audit *services.AuditService) *AuditController {
+	return &AuditController{
+		audit: audit,
+	}
+}
+
+func parseAuditFilter(c *gin.Context) (repositories.AuditFilter, error) {
+	filter := repositories.AuditFilter{
+		ActorId:    c.Query("actorId"),
+		Action:     c.Query("action"),
+		TargetType: c.Query("targetType"),
+		TargetId:   c.Query("targetId"),
+		Outcome:    c.Query("outcome"),
+	}
+
+	switch filter.Outcome {
+	case "", models.AuditSuccess, models.AuditFailure:
+	default:
+		return filter, apperrors.InvalidField("outcome", "oneof", fmt.Sprintf("must be one of: %s, %s", models.AuditSuccess, models.AuditFailure))
+	}
+
+	for _, bound := range []struct {
+		name   string
+		target *time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		value := c.Query(bound.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, apperrors.InvalidField(bound.name, "datetime", "must be an RFC 3339 timestamp")
+		}
+		*bound.target = parsed
+	}
+
+	return filter, nil
+}
+
+func (ac *AuditController) GetAuditEntriesHandler(c *gin.Context) {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+
+	filter.Limit = defaultAuditLimit
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxAuditLimit {
+			apperrors.Abort(c, apperrors.InvalidField("limit", "range", fmt.Sprintf("must be between 1 and %d", maxAuditLimit)))
+			return
+		}
+		filter.Limit = limit
+	}
+
+	entries, err := ac.audit.GetEntries(c, filter)
+	if err != nil {
+		apperrors.Abort(c, apperrors.Internal(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CustomResponse{
+		Status:  "Success",
+		Message: fmt.Sprint(len(entries)) + " audit entries found",
+		Data:    entries,
+	})
+}
+
+func (ac *AuditController) ExportAuditEntriesHandler(c *gin.Context) {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="audit-%s.ndjson"`, time.Now().UTC().Format("20060102T150405Z")))
+	c.Status(http.StatusOK)
+
+	encoder := json.NewEncoder(c.Writer)
+	written := 0
+	err = ac.audit.ExportEntries(c, filter, func(entry models.AuditEntry) error {
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+		if written++; written%100 == 0 {
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	c.Writer.Flush()
+
+	if err != nil {
+		logging.FromContext(c).Error("audit export interrupted", "entries", written, "error", err)
+	}
+}
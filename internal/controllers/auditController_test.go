@@ -0,0 +1,53 @@
+package controllers_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+)
+
+func TestAuditHandlers(t *testing.T) {
+	a := newTestApp(t)
+	admin := a.createUser("admin", "admin@example.com")
+	user := a.createUser("user", "user@example.com")
+
+	expect(t, a.do(http.MethodPost, "/api/v1/users/login", "", map[string]string{"email": user.Email, "password": testPassword}), http.StatusOK)
+	expectError(t, a.do(http.MethodPost, "/api/v1/users/login", "", map[string]string{"email": user.Email, "password": "wrong-password"}), http.StatusUnauthorized, "unauthorized")
+
+	expectError(t, a.do(http.MethodGet, "/api/v1/audit/", a.token(user), nil), http.StatusForbidden, "forbidden")
+
+	var entries []models.AuditEntry
+	expect(t, a.do(http.MethodGet, "/api/v1/audit/?action="+models.AuditLogin, a.token(admin), nil), http.StatusOK).decode(t, &entries)
+	if len(entries) != 2 {
+		t.Fatalf("got %d login entries, want 2", len(entries))
+	}
+
+	expect(t, a.do(http.MethodGet, "/api/v1/audit/?action="+models.AuditLogin+"&outcome="+models.AuditFailure, a.token(admin), nil), http.StatusOK).decode(t, &entries)
+	if len(entries) != 1 || entries[0].ActorEmail != user.Email || entries[0].Outcome != models.AuditFailure {
+		t.Errorf("unexpected failed login entries: %+v", entries)
+	}
+
+	expectError(t, a.do(http.MethodGet, "/api/v1/audit/?outcome=maybe", a.token(admin), nil), http.StatusBadRequest, "validation_failed")
+	expectError(t, a.do(http.MethodGet, "/api/v1/audit/?from=yesterday", a.token(admin), nil), http.StatusBadRequest, "validation_failed")
+	expectError(t, a.do(http.MethodGet, "/api/v1/audit/?limit=0", a.token(admin), nil), http.StatusBadRequest, "validation_failed")
+
+	recorder := a.do(http.MethodGet, "/api/v1/audit/export?action="+models.AuditLogin, a.token(admin), nil)
+	if recorder.Code != http.StatusOK || recorder.Header().Get("Content-Type") != "application/x-ndjson" {
+		t.Fatalf("export answered %d with %q", recorder.Code, recorder.Header().Get("Content-Type"))
+	}
+	exported := 0
+	scanner := bufio.NewScanner(recorder.Body)
+	for scanner.Scan() {
+		var entry models.AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.Action != models.AuditLogin {
+			t.Errorf("unexpected export line %q: %v", scanner.Text(), err)
+		}
+		exported++
+	}
+	if exported != 2 {
+		t.Errorf("exported %d entries, want 2", exported)
+	}
+}
@@ -4,35 +4,51 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
-	"errors"
 	"fmt"
 	"net/http"
-	"os"
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/hamid-nazari/tours-in-go/internal/apperrors"
+	"github.com/hamid-nazari/tours-in-go/internal/config"
+	"github.com/hamid-nazari/tours-in-go/internal/logging"
+	"github.com/hamid-nazari/tours-in-go/internal/metrics"
 	"github.com/hamid-nazari/tours-in-go/internal/models"
 	"github.com/hamid-nazari/tours-in-go/internal/services"
 )
 
-func CreateJwtTokenAndSend(c *gin.Context, user *models.User, message string) {
+type AuthController struct {
+	users   *services.UserService
+	auth    *services.AuthService
+	audit   *services.AuditService
+	config  config.AuthConfig
+	metrics *metrics.Metrics
+}
+
+func NewAuthController(users *services.UserService, auth *services.AuthService, audit *services.AuditService, config config.AuthConfig, metrics *metrics.Metrics) *AuthController {
+	return &AuthController{
+		users:   users,
+		auth:    auth,
+		audit:   audit,
+		config:  config,
+		metrics: metrics,
+	}
+}
+
+func (ac *AuthController) CreateJwtTokenAndSend(c *gin.Context, user *models.User, message string) {
 
 	claims := models.CustomClaims{
 		UserId: user.Id,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ac.config.JWTExpiresIn)),
 		},
 	}
 
-	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(os.Getenv("JWT_SECRET")))
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(ac.config.JWTSecret))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.CustomResponse{
-			Status:  "Failed",
-			Message: "Failed to create JWT token",
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.Internal(err))
 		return
 
 	}
@@ -40,7 +56,7 @@ func CreateJwtTokenAndSend(c *gin.Context, user *models.User, message string) {
 	cookieOptions := &http.Cookie{
 		Name:    "jwt",
 		Value:   token,
-		Expires: time.Now().Add(time.Hour * 24),
+		Expires: time.Now().Add(ac.config.JWTExpiresIn),
 	}
 
 	http.SetCookie(c.Writer, cookieOptions)
@@ -55,104 +71,75 @@ func CreateJwtTokenAndSend(c *gin.Context, user *models.User, message string) {
 	})
 
 }
-func SignupHandler(c *gin.Context) {
+func (ac *AuthController) SignupHandler(c *gin.Context) {
 	user := models.NewUser()
 
 	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: err.Error(),
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.Validation(err))
 		return
 	}
-	if existingUser := services.FindUserByEmail(c, user.Email); existingUser != nil {
-		c.JSON(http.StatusConflict, models.CustomResponse{
-			Status:  "Failed",
-			Message: "A user with this email already exists",
-			Data:    nil,
-		})
+	if existingUser := ac.users.FindUserByEmail(c, user.Email); existingUser != nil {
+		apperrors.Abort(c, services.ErrEmailTaken)
 		return
 	}
 
 	if err := services.ValidateUser(*user); err != nil {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: err.Error(),
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.Validation(err))
 		return
 	}
 
 	hashedPassword, err := services.HashPassword(user.Password)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.CustomResponse{
-			Status:  "Failed",
-			Message: err.Error(),
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.Internal(err))
 		return
 	}
 	user.Password = hashedPassword
 	user.PasswordConfirm = ""
 
-	if err := services.CreateUser(c, user); err != nil {
-		c.JSON(http.StatusInternalServerError, models.CustomResponse{
-			Status:  "Failed",
-			Message: err.Error(),
-			Data:    nil,
-		})
+	if err := ac.users.SignUp(c, user); err != nil {
+		apperrors.Abort(c, err)
 		return
 	}
+	ac.metrics.RecordSignup()
 
-	CreateJwtTokenAndSend(c, user, "User created successfully")
+	ac.CreateJwtTokenAndSend(c, user, "User created successfully")
 }
-func LoginHandler(c *gin.Context) {
+func (ac *AuthController) LoginHandler(c *gin.Context) {
 	var jsonData map[string]string
 
 	if err := c.ShouldBindJSON(&jsonData); err != nil {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: err.Error(),
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.Validation(err))
 		return
 	}
 
 	email, password := jsonData["email"], jsonData["password"]
 
 	if email == "" || password == "" {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: "Email and password are required",
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.BadRequest("Email and password are required"))
 		return
 	}
 
-	user := services.FindUserByEmail(c, email)
+	user := ac.users.FindUserByEmail(c, email)
 	if user == nil {
-		c.JSON(http.StatusNotFound, models.CustomResponse{
-			Status:  "Failed",
-			Message: "User not found",
-			Data:    nil,
-		})
+		ac.metrics.RecordLogin(false)
+		ac.audit.Record(c, services.AuditRecord{Action: models.AuditLogin, TargetType: "user", ActorEmail: email, Failure: "unknown email"})
+		apperrors.Abort(c, apperrors.NotFound("User not found"))
 		return
 	}
 	if !services.VerifyPassword(password, user.Password) {
-		c.JSON(http.StatusUnauthorized, models.CustomResponse{
-			Status:  "Failed",
-			Message: "Password is incorrect",
-			Data:    nil,
-		})
+		ac.metrics.RecordLogin(false)
+		ac.audit.Record(c, services.AuditRecord{Action: models.AuditLogin, TargetType: "user", TargetId: user.Id, Actor: user, Failure: "incorrect password"})
+		apperrors.Abort(c, apperrors.Unauthorized("Password is incorrect"))
 		return
 	}
+	ac.metrics.RecordLogin(true)
+	ac.audit.Record(c, services.AuditRecord{Action: models.AuditLogin, TargetType: "user", TargetId: user.Id, Actor: user})
 
-	CreateJwtTokenAndSend(c, user, "User logged in successfully")
+	ac.CreateJwtTokenAndSend(c, user, "User logged in successfully")
 
 }
 
-func LogoutHandler(c *gin.Context) {
+func (ac *AuthController) LogoutHandler(c *gin.Context) {
 	cookie := &http.Cookie{
 		Name:     "jwt",
 		Value:    "",
@@ -169,50 +156,30 @@ func LogoutHandler(c *gin.Context) {
 	})
 }
 
-func ProtectHandler(c *gin.Context) {
-	token := c.GetHeader("Authorization")
-
-	if token == "" {
-		c.JSON(http.StatusUnauthorized, models.CustomResponse{
-			Status:  "Failed",
-			Message: "Unauthorized",
-			Data:    nil,
-		})
+func (ac *AuthController) ProtectHandler(c *gin.Context) {
+	currentUser, err := ac.auth.Authenticate(c, c.GetHeader("Authorization"))
+	if err != nil {
+		apperrors.Abort(c, err)
 		return
 	}
 
-	claims, err := extractAndvalidateToken(c)
+	c.Set("user", currentUser)
+	logging.Attach(c, "userId", currentUser.Id)
 
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, models.CustomResponse{
-			Status:  "Failed",
-			Message: err.Error(),
-			Data:    nil,
-		})
-		return
-	}
+	c.Next()
+}
 
-	currentUser := services.FindUserById(c, claims.UserId)
-	if currentUser == nil {
-		c.JSON(http.StatusUnauthorized, models.CustomResponse{
-			Status:  "Failed",
-			Message: "User assigned to token not found",
-			Data:    nil,
-		})
+func (ac *AuthController) OptionalProtectHandler(c *gin.Context) {
+	if c.GetHeader("Authorization") == "" {
+		c.Next()
 		return
 	}
 
-	if currentUser.PasswordChangedAt.UTC().After(claims.IssuedAt.Time) {
-		c.JSON(http.StatusUnauthorized, models.CustomResponse{
-			Status:  "Failed",
-			Message: "User recently changed password. Please login again",
-			Data:    nil,
-		})
-		return
+	if currentUser, err := ac.auth.Authenticate(c, c.GetHeader("Authorization")); err == nil {
+		c.Set("user", currentUser)
+		logging.Attach(c, "userId", currentUser.Id)
 	}
 
-	c.Set("user", currentUser)
-
 	c.Next()
 }
 
@@ -222,11 +189,7 @@ func RestrictTo(roles ...string) gin.HandlerFunc {
 		currentUser, ok := c.Get("user")
 
 		if !ok {
-			c.JSON(http.StatusUnauthorized, models.CustomResponse{
-				Status:  "Failed",
-				Message: "Unauthorized",
-				Data:    nil,
-			})
+			apperrors.Abort(c, apperrors.Unauthorized("Unauthorized"))
 			return
 		}
 
@@ -237,42 +200,32 @@ func RestrictTo(roles ...string) gin.HandlerFunc {
 			}
 		}
 
-		c.JSON(http.StatusUnauthorized, models.CustomResponse{
-			Status:  "Failed",
-			Message: "You are not authorized to access this resource",
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.Forbidden("You are not authorized to access this resource"))
 	}
 }
 
-func ForgotPasswordHandler(c *gin.Context) {
+func (ac *AuthController) ForgotPasswordHandler(c *gin.Context) {
 	var jsonData map[string]string
 
 	if err := c.ShouldBindJSON(&jsonData); err != nil {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: err.Error(),
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.Validation(err))
 		return
 	}
 
 	email := jsonData["email"]
 
-	user := services.FindUserByEmail(c, email)
+	user := ac.users.FindUserByEmail(c, email)
 	if user == nil {
-		c.JSON(http.StatusNotFound, models.CustomResponse{
-			Status:  "Failed",
-			Message: "User associated with email not found",
-			Data:    nil,
-		})
+		ac.audit.Record(c, services.AuditRecord{Action: models.AuditPasswordResetRequest, TargetType: "user", ActorEmail: email, Failure: "unknown email"})
+		apperrors.Abort(c, apperrors.NotFound("User associated with email not found"))
 		return
 	}
 
 	user.PasswordResetToken = generatePasswordResetToken()
 	user.PasswordResetTokenExpiry = time.Now().Add(10 * time.Minute)
 
-	services.UpdateUser(c, user)
+	ac.users.UpdateUser(c, user)
+	ac.audit.Record(c, services.AuditRecord{Action: models.AuditPasswordResetRequest, TargetType: "user", TargetId: user.Id, Actor: user})
 
 	resetURL := fmt.Sprintf("%s://%s/api/users/reset-password/%s", c.Request.Proto, c.Request.Host, user.PasswordResetToken)
 
@@ -284,48 +237,34 @@ func ForgotPasswordHandler(c *gin.Context) {
 
 }
 
-func ResetPasswordHandler(c *gin.Context) {
+func (ac *AuthController) ResetPasswordHandler(c *gin.Context) {
 
 	hashedResetToken := c.Param("token")
 
-	user := services.FindUserByPasswordResetToken(c, hashedResetToken)
+	user := ac.users.FindUserByPasswordResetToken(c, hashedResetToken)
 
 	if user == nil {
-		c.JSON(http.StatusNotFound, models.CustomResponse{
-			Status:  "Failed",
-			Message: "User associated with token not found",
-			Data:    nil,
-		})
+		ac.audit.Record(c, services.AuditRecord{Action: models.AuditPasswordReset, TargetType: "user", Failure: "unknown token"})
+		apperrors.Abort(c, apperrors.NotFound("User associated with token not found"))
 		return
 	}
 
 	if time.Now().After(user.PasswordResetTokenExpiry) {
-		c.JSON(http.StatusNotFound, models.CustomResponse{
-			Status:  "Failed",
-			Message: "Password reset token expired",
-			Data:    nil,
-		})
+		ac.audit.Record(c, services.AuditRecord{Action: models.AuditPasswordReset, TargetType: "user", TargetId: user.Id, Actor: user, Failure: "expired token"})
+		apperrors.Abort(c, apperrors.NotFound("Password reset token expired"))
 		return
 	}
 
 	var jsonData map[string]string
 
 	if err := c.ShouldBindJSON(&jsonData); err != nil {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: err.Error(),
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.Validation(err))
 		return
 	}
 
 	password := jsonData["password"]
 	if password == "" {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: "Password is required",
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.InvalidField("password", "required", "is required"))
 		return
 	}
 
@@ -334,105 +273,59 @@ func ResetPasswordHandler(c *gin.Context) {
 	user.PasswordResetToken = ""
 	user.PasswordResetTokenExpiry = time.Time{}
 
-	services.UpdateUser(c, user)
+	ac.users.UpdateUser(c, user)
+	ac.audit.Record(c, services.AuditRecord{Action: models.AuditPasswordReset, TargetType: "user", TargetId: user.Id, Actor: user})
 
-	CreateJwtTokenAndSend(c, user, "Password reset successful")
+	ac.CreateJwtTokenAndSend(c, user, "Password reset successful")
 }
-func UpdatePasswordHandler(c *gin.Context) {
+func (ac *AuthController) UpdatePasswordHandler(c *gin.Context) {
 	var jsonData map[string]string
 
 	if err := c.ShouldBindJSON(&jsonData); err != nil {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: err.Error(),
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.Validation(err))
 		return
 	}
 
 	currentPassword, newPassword, newPasswordConfirm := jsonData["currentPassword"], jsonData["newPassword"], jsonData["newPasswordConfirm"]
 
 	if currentPassword == "" || newPassword == "" || newPasswordConfirm == "" {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: "All fields are required",
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.BadRequest("All fields are required"))
 		return
 	}
 
 	currentUser, ok := c.Get("user")
 
 	if !ok {
-		c.JSON(http.StatusUnauthorized, models.CustomResponse{
-			Status:  "Failed",
-			Message: "Unauthorized",
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.Unauthorized("Unauthorized"))
 		return
 	}
 
-	isPasswordCorrect := services.VerifyPassword(currentUser.(*models.User).Password, currentPassword)
+	isPasswordCorrect := services.VerifyPassword(currentPassword, currentUser.(*models.User).Password)
 
 	if !isPasswordCorrect {
-		c.JSON(http.StatusUnauthorized, models.CustomResponse{
-			Status:  "Failed",
-			Message: "Incorrect password",
-			Data:    nil,
-		})
+		ac.audit.Record(c, services.AuditRecord{Action: models.AuditPasswordChange, TargetType: "user", TargetId: currentUser.(*models.User).Id, Failure: "incorrect current password"})
+		apperrors.Abort(c, apperrors.Unauthorized("Incorrect password"))
 		return
 	}
 
 	if newPassword != newPasswordConfirm {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: "New password and confirm password do not match",
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.BadRequest("New password and confirm password do not match"))
 		return
 	}
 
 	hashedPassword, err := services.HashPassword(newPassword)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.CustomResponse{
-			Status:  "Failed",
-			Message: err.Error(),
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.Internal(err))
 		return
 	}
 
 	currentUser.(*models.User).Password = hashedPassword
 	currentUser.(*models.User).PasswordChangedAt = time.Now()
 
-	services.UpdateUser(c, currentUser.(*models.User))
-
-	CreateJwtTokenAndSend(c, currentUser.(*models.User), "Password updated successfully")
-}
-
-func extractAndvalidateToken(c *gin.Context) (*models.CustomClaims, error) {
-	authHeader := c.GetHeader("Authorization")
-
-	if len(strings.Split(authHeader, " ")) != 2 || strings.Split(authHeader, " ")[0] != "Bearer" {
-		return nil, errors.New("invalid token")
-	}
-
-	bearerToken := strings.Split(authHeader, " ")[1]
-
-	token, err := jwt.ParseWithClaims(bearerToken, &models.CustomClaims{}, func(t *jwt.Token) (interface{}, error) {
-		return []byte(os.Getenv("JWT_SECRET")), nil
-	})
-
-	if err != nil {
-		return nil, errors.New("invalid token")
-	}
-
-	claims, ok := token.Claims.(*models.CustomClaims)
-	if !ok || !token.Valid {
-		return nil, errors.New("invalid token")
-	}
+	ac.users.UpdateUser(c, currentUser.(*models.User))
+	ac.audit.Record(c, services.AuditRecord{Action: models.AuditPasswordChange, TargetType: "user", TargetId: currentUser.(*models.User).Id})
 
-	return claims, nil
+	ac.CreateJwtTokenAndSend(c, currentUser.(*models.User), "Password updated successfully")
 }
 
 func generatePasswordResetToken() string {
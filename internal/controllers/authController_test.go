@@ -0,0 +1,122 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSignupHandler(t *testing.T) {
+	a := newTestApp(t)
+
+	body := map[string]string{"name": "Jonas", "email": "jonas@example.com", "password": testPassword, "passwordConfirm": testPassword}
+	response := expect(t, a.do(http.MethodPost, "/api/v1/users/signup", "", body), http.StatusOK)
+
+	var payload struct {
+		Token string `json:"token"`
+		User  struct {
+			Email    string `json:"email"`
+			Role     string `json:"role"`
+			Password string `json:"password"`
+		} `json:"user"`
+	}
+	response.decode(t, &payload)
+	if payload.Token == "" || payload.User.Email != "jonas@example.com" || payload.User.Role != "user" {
+		t.Errorf("unexpected signup payload: %+v", payload)
+	}
+	if payload.User.Password == testPassword {
+		t.Error("signup stored the plain text password")
+	}
+
+	expectError(t, a.do(http.MethodPost, "/api/v1/users/signup", "", body), http.StatusConflict, "conflict")
+
+	invalid := map[string]string{"name": "Jonas", "email": "not-an-email", "password": "short", "passwordConfirm": "short"}
+	response = expectError(t, a.do(http.MethodPost, "/api/v1/users/signup", "", invalid), http.StatusBadRequest, "validation_failed")
+	if len(response.Details) == 0 {
+		t.Error("validation error has no field details")
+	}
+}
+
+func TestLoginHandler(t *testing.T) {
+	a := newTestApp(t)
+	user := a.createUser("user", "user@example.com")
+
+	response := expect(t, a.do(http.MethodPost, "/api/v1/users/login", "", map[string]string{"email": user.Email, "password": testPassword}), http.StatusOK)
+	var payload struct {
+		Token string `json:"token"`
+	}
+	response.decode(t, &payload)
+	if payload.Token == "" {
+		t.Fatal("login did not return a token")
+	}
+	expect(t, a.do(http.MethodPatch, "/api/v1/users/update-password", payload.Token, map[string]string{}), http.StatusBadRequest)
+
+	expectError(t, a.do(http.MethodPost, "/api/v1/users/login", "", map[string]string{"email": user.Email, "password": "wrong-password"}), http.StatusUnauthorized, "unauthorized")
+	expectError(t, a.do(http.MethodPost, "/api/v1/users/login", "", map[string]string{"email": "nobody@example.com", "password": testPassword}), http.StatusNotFound, "not_found")
+	expectError(t, a.do(http.MethodPost, "/api/v1/users/login", "", map[string]string{"email": user.Email}), http.StatusBadRequest, "bad_request")
+}
+
+func TestProtectHandler(t *testing.T) {
+	a := newTestApp(t)
+	user := a.createUser("user", "user@example.com")
+
+	expectError(t, a.do(http.MethodGet, "/api/v1/users/", "", nil), http.StatusUnauthorized, "unauthorized")
+	expectError(t, a.do(http.MethodGet, "/api/v1/users/", "not-a-jwt", nil), http.StatusUnauthorized, "unauthorized")
+	expectError(t, a.do(http.MethodGet, "/api/v1/users/", a.token(user), nil), http.StatusForbidden, "forbidden")
+}
+
+func TestUpdatePasswordHandler(t *testing.T) {
+	a := newTestApp(t)
+	user := a.createUser("user", "user@example.com")
+	token := a.token(user)
+
+	wrong := map[string]string{"currentPassword": "wrong-password", "newPassword": "newpass123", "newPasswordConfirm": "newpass123"}
+	expectError(t, a.do(http.MethodPatch, "/api/v1/users/update-password", token, wrong), http.StatusUnauthorized, "unauthorized")
+
+	mismatch := map[string]string{"currentPassword": testPassword, "newPassword": "newpass123", "newPasswordConfirm": "other123"}
+	expectError(t, a.do(http.MethodPatch, "/api/v1/users/update-password", token, mismatch), http.StatusBadRequest, "bad_request")
+
+	update := map[string]string{"currentPassword": testPassword, "newPassword": "newpass123", "newPasswordConfirm": "newpass123"}
+	expect(t, a.do(http.MethodPatch, "/api/v1/users/update-password", token, update), http.StatusOK)
+
+	expect(t, a.do(http.MethodPost, "/api/v1/users/login", "", map[string]string{"email": user.Email, "password": "newpass123"}), http.StatusOK)
+	expectError(t, a.do(http.MethodPost, "/api/v1/users/login", "", map[string]string{"email": user.Email, "password": testPassword}), http.StatusUnauthorized, "unauthorized")
+}
+
+func TestPasswordVerificationAfterSignup(t *testing.T) {
+	a := newTestApp(t)
+
+	signup := map[string]string{"name": "Jonas", "email": "jonas@example.com", "password": testPassword, "passwordConfirm": testPassword}
+	expect(t, a.do(http.MethodPost, "/api/v1/users/signup", "", signup), http.StatusOK)
+
+	login := func(password string) *httptest.ResponseRecorder {
+		return a.do(http.MethodPost, "/api/v1/users/login", "", map[string]string{"email": "jonas@example.com", "password": password})
+	}
+	var payload struct {
+		Token string `json:"token"`
+	}
+	expect(t, login(testPassword), http.StatusOK).decode(t, &payload)
+	expectError(t, login("wrong-password"), http.StatusUnauthorized, "unauthorized")
+
+	update := map[string]string{"currentPassword": testPassword, "newPassword": "newpass123", "newPasswordConfirm": "newpass123"}
+	expect(t, a.do(http.MethodPatch, "/api/v1/users/update-password", payload.Token, update), http.StatusOK)
+
+	expect(t, login("newpass123"), http.StatusOK)
+	expectError(t, login(testPassword), http.StatusUnauthorized, "unauthorized")
+}
+
+func TestForgotPasswordHandler(t *testing.T) {
+	a := newTestApp(t)
+	user := a.createUser("user", "user@example.com")
+
+	response := expect(t, a.do(http.MethodPost, "/api/v1/users/forgot-password", "", map[string]string{"email": user.Email}), http.StatusOK)
+	var link struct {
+		ResetURL string `json:"reset_url"`
+	}
+	response.decode(t, &link)
+	if link.ResetURL == "" {
+		t.Error("forgot password did not return a reset link")
+	}
+
+	expectError(t, a.do(http.MethodPost, "/api/v1/users/forgot-password", "", map[string]string{"email": "nobody@example.com"}), http.StatusNotFound, "not_found")
+}
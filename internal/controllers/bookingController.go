@@ -1,104 +1,136 @@
 package controllers
 
 import (
+	"math"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hamid-nazari/tours-in-go/internal/apperrors"
+	"github.com/hamid-nazari/tours-in-go/internal/config"
 	"github.com/hamid-nazari/tours-in-go/internal/models"
 	"github.com/hamid-nazari/tours-in-go/internal/services"
+	"github.com/hamid-nazari/tours-in-go/internal/tracing"
 	"github.com/stripe/stripe-go/v79"
+	checkoutsession "github.com/stripe/stripe-go/v79/checkout/session"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
-func GetCheckoutSessionHandler(c *gin.Context) {
+type BookingController struct {
+	bookings *services.BookingService
+	tours    *services.TourService
+	config   config.StripeConfig
+}
+
+func NewBookingController(bookings *services.BookingService, tours *services.TourService, config config.StripeConfig) *BookingController {
+	return &BookingController{
+		bookings: bookings,
+		tours:    tours,
+		config:   config,
+	}
+}
+
+func (bc *BookingController) GetCheckoutSessionHandler(c *gin.Context) {
 	tourId := c.Param("id")
 	if tourId == "" {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: "Tour ID is required",
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.BadRequest("Tour ID is required"))
 		return
 	}
 
-	tour := services.FindTourById(c, tourId)
+	tour := bc.tours.FindSharedTourById(c, tourId, c.Query("accessToken"))
 	if tour == nil {
-		c.JSON(http.StatusNotFound, models.CustomResponse{
-			Status:  "Failed",
-			Message: "Tour not found",
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.NotFound("Tour not found"))
 		return
 	}
 
 	user, ok := c.Get("user")
 	if !ok {
-		c.JSON(http.StatusInternalServerError, models.CustomResponse{
-			Status:  "Failed",
-			Message: "You need to be logged in to proceed",
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.Unauthorized("You need to be logged in to proceed"))
+		return
+	}
+
+	booking, err := bc.bookings.StartCheckout(c, tour, user.(*models.User))
+	if err != nil {
+		apperrors.Abort(c, err)
 		return
 	}
 
 	session := stripe.CheckoutSessionParams{
+		Params: stripe.Params{
+			Metadata: map[string]string{
+				"booking_id": booking.Id,
+			},
+		},
 		PaymentMethodTypes: stripe.StringSlice([]string{"card"}),
 		Mode:               stripe.String("payment"),
-		SuccessURL:         stripe.String("https://example.com/success"),
-		CancelURL:          stripe.String("https://example.com/canceled"),
+		SuccessURL:         stripe.String(bc.config.SuccessURL),
+		CancelURL:          stripe.String(bc.config.CancelURL),
 		ClientReferenceID:  stripe.String(tourId),
 		CustomerEmail:      stripe.String(user.(*models.User).Email),
 		LineItems: []*stripe.CheckoutSessionLineItemParams{
 			{
-				Price: stripe.String("price_1L3Zb2HlB6kSf1sR4t6H4Hqo"),
 				PriceData: &stripe.CheckoutSessionLineItemPriceDataParams{
-					Currency:   stripe.String("usd"),
-					UnitAmount: stripe.Int64(int64(tour.Price)),
-					Product:    stripe.String(tourId),
+					Currency:   stripe.String(bc.config.Currency),
+					UnitAmount: stripe.Int64(int64(math.Round(tour.Price * 100))),
+					ProductData: &stripe.CheckoutSessionLineItemPriceDataProductDataParams{
+						Name:        stripe.String(tour.Name),
+						Description: stripe.String(tour.Summary),
+					},
 				},
 				Quantity: stripe.Int64(1),
 			},
 		},
 	}
 
-	// sessionParams := &stripe.CheckoutSessionParams{
-	// 	Params: stripe.Params{
-	// 		Metadata: map[string]string{
-	// 			"tour_id": tourId,
-	// 		},
-	// 	},
-	// }
+	if bc.config.SecretKey == "" {
+		c.JSON(http.StatusOK, models.CustomResponse{
+			Status:  "Success",
+			Message: "Checkout session created successfully",
+			Data:    session,
+		})
+		return
+	}
+
+	ctx, span := tracing.Tracer().Start(c.Request.Context(), "BookingController.CreateCheckoutSession")
+	defer span.End()
+	span.SetAttributes(attribute.String("booking.id", booking.Id))
 
-	// sessionParams.AddExpand("line_items")
-	// sessionParams.AddExpand("customer")
+	session.Context = ctx
+	created, err := checkoutsession.New(&session)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to create checkout session")
+		apperrors.Abort(c, apperrors.Internal(err))
+		return
+	}
 
 	c.JSON(http.StatusOK, models.CustomResponse{
 		Status:  "Success",
 		Message: "Checkout session created successfully",
-		Data:    session,
+		Data:    created,
 	})
-
 }
 
-func WebhookHandler(c *gin.Context) {
+func (bc *BookingController) WebhookHandler(c *gin.Context) {
 
 }
 
-func CreateBookingHandler(c *gin.Context) {
+func (bc *BookingController) CreateBookingHandler(c *gin.Context) {
 
 }
 
-func GetAllBookingsHandler(c *gin.Context) {
+func (bc *BookingController) GetAllBookingsHandler(c *gin.Context) {
 
 }
 
-func GetBookingHandler(c *gin.Context) {
+func (bc *BookingController) GetBookingHandler(c *gin.Context) {
 
 }
 
-func UpdateBookingHandler(c *gin.Context) {
+func (bc *BookingController) UpdateBookingHandler(c *gin.Context) {
 
 }
 
-func DeleteBookingHandler(c *gin.Context) {
+func (bc *BookingController) DeleteBookingHandler(c *gin.Context) {
 
 }
@@ -0,0 +1,214 @@
+package controllers_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hamid-nazari/tours-in-go/internal/config"
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"github.com/hamid-nazari/tours-in-go/internal/tracing"
+	"github.com/stripe/stripe-go/v79"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestGetCheckoutSessionHandler(t *testing.T) {
+	a := newTestApp(t)
+	tour := a.createTour("The Forest Hiker")
+	user := a.createUser("user", "user@example.com")
+
+	expectError(t, a.do(http.MethodGet, "/api/v1/bookings/checkout-session/"+tour.Id, "", nil), http.StatusUnauthorized, "unauthorized")
+	expectError(t, a.do(http.MethodGet, "/api/v1/bookings/checkout-session/missing", a.token(user), nil), http.StatusNotFound, "not_found")
+
+	var session struct {
+		ClientReferenceID *string `json:"ClientReferenceID"`
+		CustomerEmail     *string `json:"CustomerEmail"`
+	}
+	expect(t, a.do(http.MethodGet, "/api/v1/bookings/checkout-session/"+tour.Id, a.token(user), nil), http.StatusOK).decode(t, &session)
+	if session.ClientReferenceID == nil || *session.ClientReferenceID != tour.Id || session.CustomerEmail == nil || *session.CustomerEmail != user.Email {
+		t.Errorf("unexpected checkout session: %+v", session)
+	}
+}
+
+func TestCheckoutSessionForSharedTour(t *testing.T) {
+	a := newTestApp(t)
+	tour := a.createTour("The Hidden Valley", func(tour *models.Tour) { tour.SecretTour = true })
+	user := a.createUser("user", "user@example.com")
+	accessToken, err := a.Tours.CreateTourAccessToken(context.Background(), tour)
+	if err != nil {
+		t.Fatalf("failed to create access token: %v", err)
+	}
+	path := "/api/v1/bookings/checkout-session/" + tour.Id
+
+	expectError(t, a.do(http.MethodGet, path, a.token(user), nil), http.StatusNotFound, "not_found")
+	expectError(t, a.do(http.MethodGet, path+"?accessToken=wrong", a.token(user), nil), http.StatusNotFound, "not_found")
+	expect(t, a.do(http.MethodGet, path+"?accessToken="+accessToken, a.token(user), nil), http.StatusOK)
+
+	other := a.createTour("The Other Valley", func(tour *models.Tour) { tour.SecretTour = true })
+	expectError(t, a.do(http.MethodGet, "/api/v1/bookings/checkout-session/"+other.Id+"?accessToken="+accessToken, a.token(user), nil), http.StatusNotFound, "not_found")
+}
+
+func TestCheckoutSessionCallsStripe(t *testing.T) {
+	var form url.Values
+	stripeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/checkout/sessions" {
+			http.NotFound(w, r)
+			return
+		}
+		r.ParseForm()
+		form = r.PostForm
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"id": "cs_test_123", "object": "checkout.session", "url": "https://checkout.stripe.com/c/pay/cs_test_123"}`)
+	}))
+	defer stripeServer.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	previousProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previousProvider) })
+
+	a := newTestApp(t, func(cfg *config.Config) { cfg.Stripe.SecretKey = "sk_test_123" })
+	stripe.SetBackend(stripe.APIBackend, stripe.GetBackendWithConfig(stripe.APIBackend, &stripe.BackendConfig{
+		URL:               stripe.String(stripeServer.URL),
+		HTTPClient:        tracing.HTTPClient("stripe", time.Second),
+		LeveledLogger:     &stripe.LeveledLogger{Level: stripe.LevelNull},
+		MaxNetworkRetries: stripe.Int64(0),
+	}))
+	t.Cleanup(func() {
+		stripe.Key = ""
+		stripe.SetBackend(stripe.APIBackend, nil)
+	})
+
+	tour := a.createTour("The Forest Hiker")
+	user := a.createUser("user", "user@example.com")
+
+	var session struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+	}
+	expect(t, a.do(http.MethodGet, "/api/v1/bookings/checkout-session/"+tour.Id, a.token(user), nil), http.StatusOK).decode(t, &session)
+	if session.ID != "cs_test_123" || session.URL == "" {
+		t.Errorf("expected the session created by Stripe, got %+v", session)
+	}
+
+	bookings, err := a.Bookings.GetBookingsForUser(context.Background(), user.Id)
+	if err != nil || len(bookings) != 1 {
+		t.Fatalf("expected one pending booking, got %+v (%v)", bookings, err)
+	}
+	if form.Get("metadata[booking_id]") != bookings[0].Id || form.Get("client_reference_id") != tour.Id || form.Get("customer_email") != user.Email {
+		t.Errorf("unexpected checkout session request: %v", form)
+	}
+
+	var parent, client sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		switch {
+		case span.Name() == "BookingController.CreateCheckoutSession":
+			parent = span
+		case span.Name() == "stripe POST" && span.SpanKind() == trace.SpanKindClient:
+			client = span
+		}
+	}
+	if parent == nil || client == nil {
+		t.Fatalf("expected a checkout span with a Stripe client span, got %d spans", len(recorder.Ended()))
+	}
+	if client.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("the Stripe client span is not a child of the checkout span")
+	}
+}
+
+func TestCancelAbandonedCheckoutsOnlyCancelsExpiredCheckouts(t *testing.T) {
+	a := newTestApp(t)
+	tour := a.createTour("The Forest Hiker")
+	user := a.createUser("user", "user@example.com")
+	ctx := context.Background()
+
+	expect(t, a.do(http.MethodGet, "/api/v1/bookings/checkout-session/"+tour.Id, a.token(user), nil), http.StatusOK)
+	pending, err := a.Bookings.GetBookingsForUser(ctx, user.Id)
+	if err != nil || len(pending) != 1 || pending[0].Paid || pending[0].CheckoutExpiresAt.IsZero() {
+		t.Fatalf("expected a pending checkout booking, got %+v (%v)", pending, err)
+	}
+
+	unpaid := models.NewBooking()
+	unpaid.Tour = *tour
+	unpaid.User = *user
+	unpaid.Price = 100
+	unpaid.CreatedAt = time.Now().Add(-30 * 24 * time.Hour)
+	if _, err := a.Bookings.CreateBooking(ctx, unpaid); err != nil {
+		t.Fatalf("failed to create booking: %v", err)
+	}
+
+	expired := models.NewBooking()
+	expired.Tour = *tour
+	expired.User = *user
+	expired.Price = 100
+	expired.CreatedAt = time.Now().Add(-2 * time.Hour)
+	expired.CheckoutExpiresAt = time.Now().Add(-time.Hour)
+	if _, err := a.Bookings.CreateBooking(ctx, expired); err != nil {
+		t.Fatalf("failed to create booking: %v", err)
+	}
+
+	if err := a.Bookings.CancelAbandonedCheckouts(ctx, &models.Job{}); err != nil {
+		t.Fatalf("failed to cancel abandoned checkouts: %v", err)
+	}
+
+	bookings, err := a.Bookings.GetBookingsForUser(ctx, user.Id)
+	if err != nil {
+		t.Fatalf("failed to list bookings: %v", err)
+	}
+	remaining := map[string]bool{}
+	for _, booking := range bookings {
+		remaining[booking.Id] = true
+	}
+	if !remaining[pending[0].Id] || !remaining[unpaid.Id] || remaining[expired.Id] || len(remaining) != 2 {
+		t.Errorf("unexpected bookings after cancelling abandoned checkouts: %+v", bookings)
+	}
+}
+
+func TestBookingRevenueCountsPaidBookings(t *testing.T) {
+	a := newTestApp(t)
+	tour := a.createTour("The Forest Hiker")
+	user := a.createUser("user", "user@example.com")
+	ctx := context.Background()
+
+	for _, paid := range []bool{false, true} {
+		booking := models.NewBooking()
+		booking.Tour = *tour
+		booking.User = *user
+		booking.Price = 100
+		booking.Paid = paid
+		if _, err := a.Bookings.CreateBooking(ctx, booking); err != nil {
+			t.Fatalf("failed to create booking: %v", err)
+		}
+		if !paid {
+			expectMetric(t, a, "tours_booking_revenue_total", "0")
+			booking.Paid = true
+			if _, err := a.Bookings.UpdateBooking(ctx, booking); err != nil {
+				t.Fatalf("failed to pay booking: %v", err)
+			}
+			expectMetric(t, a, "tours_booking_revenue_total", "100")
+			if _, err := a.Bookings.UpdateBooking(ctx, booking); err != nil {
+				t.Fatalf("failed to update booking: %v", err)
+			}
+		}
+	}
+
+	expectMetric(t, a, "tours_bookings_created_total", "2")
+	expectMetric(t, a, "tours_booking_revenue_total", "200")
+}
+
+func expectMetric(t *testing.T, a *testApp, name, value string) {
+	t.Helper()
+
+	body := a.do(http.MethodGet, "/metrics", "", nil).Body.String()
+	if !strings.Contains(body, "\n"+name+" "+value+"\n") {
+		t.Errorf("metrics do not report %s %s", name, value)
+	}
+}
@@ -0,0 +1,26 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamid-nazari/tours-in-go/internal/openapi"
+)
+
+type DocsController struct {
+	spec *openapi.Document
+}
+
+func NewDocsController(spec *openapi.Document) *DocsController {
+	return &DocsController{
+		spec: spec,
+	}
+}
+
+func (dc *DocsController) SpecHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, dc.spec)
+}
+
+func (dc *DocsController) UIHandler(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(openapi.SwaggerUI("/openapi.json")))
+}
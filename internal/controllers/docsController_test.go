@@ -0,0 +1,32 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDocsHandlers(t *testing.T) {
+	a := newTestApp(t)
+
+	recorder := a.do(http.MethodGet, "/openapi.json", "", nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("got status %d for the spec", recorder.Code)
+	}
+	var spec struct {
+		OpenAPI string         `json:"openapi"`
+		Paths   map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("spec is not JSON: %v", err)
+	}
+	if spec.OpenAPI == "" || spec.Paths["/api/v1/tours/"] == nil {
+		t.Errorf("spec is missing its version or the tours path")
+	}
+
+	recorder = a.do(http.MethodGet, "/docs", "", nil)
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Header().Get("Content-Type"), "text/html") || !strings.Contains(recorder.Body.String(), "/openapi.json") {
+		t.Errorf("docs page answered %d with %q", recorder.Code, recorder.Header().Get("Content-Type"))
+	}
+}
@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamid-nazari/tours-in-go/internal/apperrors"
+	"github.com/hamid-nazari/tours-in-go/internal/graph"
+)
+
+type GraphQLController struct {
+	server *graph.Server
+}
+
+func NewGraphQLController(server *graph.Server) *GraphQLController {
+	return &GraphQLController{
+		server: server,
+	}
+}
+
+func (gc *GraphQLController) QueryHandler(c *gin.Context) {
+	var request graph.Request
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		apperrors.Abort(c, apperrors.Validation(err))
+		return
+	}
+
+	if rejected := gc.server.CheckLimits(request); rejected != nil {
+		c.JSON(http.StatusOK, rejected)
+		return
+	}
+
+	c.JSON(http.StatusOK, gc.server.Execute(c, request))
+}
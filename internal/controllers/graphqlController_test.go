@@ -0,0 +1,137 @@
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+)
+
+func TestGraphQLQueryHandler(t *testing.T) {
+	a := newTestApp(t)
+	a.createTour("The Forest Hiker")
+	a.createTour("The Sea Explorer")
+	a.createTour("The Hidden Valley", func(tour *models.Tour) { tour.SecretTour = true })
+
+	query := func(path, token string, body map[string]any) (result struct {
+		Data struct {
+			Tours []struct {
+				Name string `json:"name"`
+			} `json:"tours"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}) {
+		t.Helper()
+
+		recorder := a.do(http.MethodPost, path, token, body)
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("got status %d: %s", recorder.Code, recorder.Body.String())
+		}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+			t.Fatalf("failed to decode result: %v", err)
+		}
+		return result
+	}
+
+	result := query("/graphql", "", map[string]any{"query": "{ tours { name } }"})
+	if len(result.Errors) != 0 || len(result.Data.Tours) != 2 || result.Data.Tours[0].Name != "The Forest Hiker" {
+		t.Errorf("anonymous tours query returned %+v", result)
+	}
+
+	admin := a.createUser("admin", "admin@example.com")
+	if result := query("/graphql?includeSecret=true", a.token(admin), map[string]any{"query": "{ tours { name } }"}); len(result.Data.Tours) != 3 {
+		t.Errorf("admin sees %d tours, want 3 including the secret one", len(result.Data.Tours))
+	}
+
+	result = query("/graphql", "", map[string]any{"query": "query($limit: Int) { tours(limit: $limit, offset: 1) { name } }", "variables": map[string]any{"limit": 1}})
+	if len(result.Data.Tours) != 1 || result.Data.Tours[0].Name != "The Sea Explorer" {
+		t.Errorf("paged tours query returned %+v", result.Data.Tours)
+	}
+
+	if result := query("/graphql", "", map[string]any{"query": "{ tours { unknown } }"}); len(result.Errors) == 0 {
+		t.Error("querying an unknown field returned no errors")
+	}
+
+	expectError(t, a.do(http.MethodPost, "/graphql", "", map[string]any{}), http.StatusBadRequest, "validation_failed")
+}
+
+func TestGraphQLReviewsArePaged(t *testing.T) {
+	a := newTestApp(t)
+	tour := a.createTour("The Forest Hiker")
+	user := a.createUser("user", "user@example.com")
+	ctx := context.Background()
+
+	created := time.Now().Add(-time.Hour)
+	for i, text := range []string{"First review text", "Second review text", "Third review text"} {
+		review := models.NewReview()
+		review.Review = text
+		review.Rating = 5
+		review.Tour = *tour
+		review.User = *user
+		review.Status = models.ReviewStatusPublished
+		review.CreatedAt = created.Add(time.Duration(i) * time.Minute)
+		if err := a.Reviews.ImportReview(ctx, review, true); err != nil {
+			t.Fatalf("failed to create review: %v", err)
+		}
+	}
+
+	var result struct {
+		Data struct {
+			Reviews []struct {
+				Review string `json:"review"`
+			} `json:"reviews"`
+		} `json:"data"`
+	}
+	recorder := a.do(http.MethodPost, "/graphql", "", map[string]any{"query": `{ reviews(sort: "oldest", limit: 1, offset: 1) { review } }`})
+	if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+		t.Fatalf("failed to decode result: %v", err)
+	}
+	if len(result.Data.Reviews) != 1 || result.Data.Reviews[0].Review != "Second review text" {
+		t.Errorf("paged reviews query returned %s", recorder.Body.String())
+	}
+}
+
+func TestGraphQLGuideToursArePagedPerGuide(t *testing.T) {
+	a := newTestApp(t)
+	first := a.createUser("guide", "first@example.com")
+	second := a.createUser("lead-guide", "second@example.com")
+	a.createTour("The Forest Hiker", func(tour *models.Tour) { tour.Guides = []string{first.Id, second.Id} })
+	a.createTour("The Sea Explorer", func(tour *models.Tour) { tour.Guides = []string{first.Id} })
+	a.createTour("The Snow Adventurer", func(tour *models.Tour) { tour.Guides = []string{first.Id, second.Id} })
+
+	body := map[string]any{
+		"query":     "query($first: ID!, $second: ID!) { first: user(id: $first) { tours(limit: 2, offset: 1) { name } } second: user(id: $second) { tours(limit: 2, offset: 1) { name } } }",
+		"variables": map[string]any{"first": first.Id, "second": second.Id},
+	}
+	recorder := a.do(http.MethodPost, "/graphql", "", body)
+
+	var result struct {
+		Data map[string]struct {
+			Tours []struct {
+				Name string `json:"name"`
+			} `json:"tours"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+		t.Fatalf("failed to decode result: %v", err)
+	}
+
+	names := func(alias string) []string {
+		var names []string
+		for _, tour := range result.Data[alias].Tours {
+			names = append(names, tour.Name)
+		}
+		return names
+	}
+	if got := names("first"); len(got) != 2 || got[0] != "The Sea Explorer" || got[1] != "The Snow Adventurer" {
+		t.Errorf("first guide's page = %v, want The Sea Explorer and The Snow Adventurer", got)
+	}
+	if got := names("second"); len(got) != 1 || got[0] != "The Snow Adventurer" {
+		t.Errorf("second guide's page = %v, want The Snow Adventurer", got)
+	}
+}
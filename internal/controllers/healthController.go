@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamid-nazari/tours-in-go/internal/health"
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+)
+
+type HealthController struct {
+	checks       *health.Registry
+	shuttingDown func() bool
+}
+
+func NewHealthController(checks *health.Registry, shuttingDown func() bool) *HealthController {
+	return &HealthController{
+		checks:       checks,
+		shuttingDown: shuttingDown,
+	}
+}
+
+func (hc *HealthController) LivenessHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, models.CustomResponse{
+		Status:  "Success",
+		Message: "Service is alive",
+		Data:    nil,
+	})
+}
+
+func (hc *HealthController) ReadinessHandler(c *gin.Context) {
+	if hc.shuttingDown() {
+		c.JSON(http.StatusServiceUnavailable, models.CustomResponse{
+			Status:  "Failed",
+			Message: "Service is shutting down",
+			Data:    health.Report{Ready: false, Checks: map[string]health.CheckResult{}},
+		})
+		return
+	}
+
+	report := hc.checks.Run(c.Request.Context())
+	if !report.Ready {
+		c.JSON(http.StatusServiceUnavailable, models.CustomResponse{
+			Status:  "Failed",
+			Message: "Service is not ready",
+			Data:    report,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CustomResponse{
+		Status:  "Success",
+		Message: "Service is ready",
+		Data:    report,
+	})
+}
@@ -0,0 +1,29 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/hamid-nazari/tours-in-go/internal/health"
+)
+
+func TestHealthHandlers(t *testing.T) {
+	a := newTestApp(t)
+
+	expect(t, a.do(http.MethodGet, "/healthz", "", nil), http.StatusOK)
+	expect(t, a.do(http.MethodGet, "/readyz", "", nil), http.StatusOK)
+
+	a.Health.Register(health.Check{
+		Name: "broken",
+		Run:  func(ctx context.Context) error { return errors.New("unreachable") },
+	})
+	response := expect(t, a.do(http.MethodGet, "/readyz", "", nil), http.StatusServiceUnavailable)
+	var report health.Report
+	response.decode(t, &report)
+	if report.Ready || report.Checks["broken"].Error == "" {
+		t.Errorf("unexpected readiness report: %+v", report)
+	}
+	expect(t, a.do(http.MethodGet, "/healthz", "", nil), http.StatusOK)
+}
@@ -0,0 +1,177 @@
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hamid-nazari/tours-in-go/internal/app"
+	"github.com/hamid-nazari/tours-in-go/internal/apperrors"
+	"github.com/hamid-nazari/tours-in-go/internal/config"
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"github.com/hamid-nazari/tours-in-go/internal/services"
+)
+
+const (
+	testSecret   = "test-secret"
+	testPassword = "pass1234"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	os.Exit(m.Run())
+}
+
+type testApp struct {
+	*app.App
+	t *testing.T
+}
+
+func newTestApp(t *testing.T, configure ...func(*config.Config)) *testApp {
+	t.Helper()
+
+	cfg := config.Defaults()
+	cfg.Auth.JWTSecret = testSecret
+	for _, fn := range configure {
+		fn(cfg)
+	}
+
+	application, err := app.New(cfg, app.MemoryRepositories())
+	if err != nil {
+		t.Fatalf("failed to build app: %v", err)
+	}
+	return &testApp{App: application, t: t}
+}
+
+func (a *testApp) createUser(role string, email string) *models.User {
+	a.t.Helper()
+
+	hashedPassword, err := services.HashPassword(testPassword)
+	if err != nil {
+		a.t.Fatalf("failed to hash password: %v", err)
+	}
+
+	user := models.NewUser()
+	user.Name = "Test " + role
+	user.Email = email
+	user.Role = role
+	user.Password = hashedPassword
+	user.PasswordChangedAt = time.Time{}
+	if err := a.Users.CreateUser(context.Background(), user); err != nil {
+		a.t.Fatalf("failed to create user: %v", err)
+	}
+	return user
+}
+
+func (a *testApp) token(user *models.User) string {
+	a.t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, models.CustomClaims{
+		UserId: user.Id,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}).SignedString([]byte(testSecret))
+	if err != nil {
+		a.t.Fatalf("failed to sign token: %v", err)
+	}
+	return token
+}
+
+func (a *testApp) createTour(name string, configure ...func(*models.Tour)) *models.Tour {
+	a.t.Helper()
+
+	tour := models.NewTour()
+	tour.Name = name
+	tour.Duration = "5"
+	tour.Price = 497
+	tour.MaxGroupSize = 25
+	tour.Summary = "Breathtaking hike through the Canadian Banff National Park"
+	for _, fn := range configure {
+		fn(tour)
+	}
+
+	ctx := context.Background()
+	if err := a.Tours.GenerateTourSlug(ctx, tour); err != nil {
+		a.t.Fatalf("failed to generate slug: %v", err)
+	}
+	if err := a.Tours.CreateTour(ctx, tour); err != nil {
+		a.t.Fatalf("failed to create tour: %v", err)
+	}
+	return tour
+}
+
+func (a *testApp) do(method, path, token string, body any) *httptest.ResponseRecorder {
+	a.t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			a.t.Fatalf("failed to encode body: %v", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	request := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	recorder := httptest.NewRecorder()
+	a.Router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+type envelope struct {
+	Status  string                 `json:"status"`
+	Code    string                 `json:"code"`
+	Message string                 `json:"message"`
+	Details []apperrors.FieldError `json:"details"`
+	Data    json.RawMessage        `json:"data"`
+}
+
+func expect(t *testing.T, recorder *httptest.ResponseRecorder, status int) envelope {
+	t.Helper()
+
+	if recorder.Code != status {
+		t.Fatalf("got status %d, want %d: %s", recorder.Code, status, recorder.Body.String())
+	}
+
+	var response envelope
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response %q: %v", recorder.Body.String(), err)
+	}
+	return response
+}
+
+func (e envelope) decode(t *testing.T, out any) {
+	t.Helper()
+
+	if err := json.Unmarshal(e.Data, out); err != nil {
+		t.Fatalf("failed to decode data %s: %v", e.Data, err)
+	}
+}
+
+func expectError(t *testing.T, recorder *httptest.ResponseRecorder, status int, code string) envelope {
+	t.Helper()
+
+	response := expect(t, recorder, status)
+	if response.Status != "Failed" || response.Code != code {
+		t.Fatalf("got status %q and code %q, want Failed and %q", response.Status, response.Code, code)
+	}
+	return response
+}
@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamid-nazari/tours-in-go/internal/apperrors"
+	"github.com/hamid-nazari/tours-in-go/internal/jobs"
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"github.com/hamid-nazari/tours-in-go/internal/services"
+)
+
+const maxJobsListed = 100
+
+type JobController struct {
+	jobs  *jobs.Queue
+	audit *services.AuditService
+}
+
+func NewJobController(jobs *jobs.Queue, audit *services.AuditService) *JobController {
+	return &JobController{
+		jobs:  jobs,
+		audit: audit,
+	}
+}
+
+func (jc *JobController) GetAllJobsHandler(c *gin.Context) {
+	status := c.Query("status")
+	switch status {
+	case "", models.JobQueued, models.JobRunning, models.JobSucceeded, models.JobDead:
+	default:
+		apperrors.Abort(c, apperrors.InvalidField("status", "oneof", fmt.Sprintf("must be one of: %s, %s, %s, %s", models.JobQueued, models.JobRunning, models.JobSucceeded, models.JobDead)))
+		return
+	}
+
+	found, err := jc.jobs.GetJobs(c, c.Query("type"), status, maxJobsListed)
+	if err != nil {
+		apperrors.Abort(c, apperrors.Internal(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CustomResponse{
+		Status:  "Success",
+		Message: fmt.Sprint(len(found)) + " jobs found",
+		Data:    found,
+	})
+}
+
+func (jc *JobController) GetJobHandler(c *gin.Context) {
+	job := jc.jobs.FindJobById(c, c.Param("id"))
+	if job == nil {
+		apperrors.Abort(c, apperrors.NotFound("Job not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CustomResponse{
+		Status:  "Success",
+		Message: "Job retrieved successfully",
+		Data:    job,
+	})
+}
+
+func (jc *JobController) RetryJobHandler(c *gin.Context) {
+	job := jc.jobs.FindJobById(c, c.Param("id"))
+	if job == nil {
+		apperrors.Abort(c, apperrors.NotFound("Job not found"))
+		return
+	}
+
+	before := services.AuditSnapshot(job)
+
+	if err := jc.jobs.Retry(c, job); err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+	jc.audit.Record(c, services.AuditRecord{Action: models.AuditJobRetry, TargetType: "job", TargetId: job.Id, Before: before, After: job})
+
+	c.JSON(http.StatusOK, models.CustomResponse{
+		Status:  "Success",
+		Message: "Job queued for retry",
+		Data:    job,
+	})
+}
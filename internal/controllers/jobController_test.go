@@ -0,0 +1,50 @@
+package controllers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hamid-nazari/tours-in-go/internal/jobs"
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"github.com/hamid-nazari/tours-in-go/internal/services"
+)
+
+func TestJobHandlers(t *testing.T) {
+	a := newTestApp(t)
+	admin := a.createUser("admin", "admin@example.com")
+	token := a.token(admin)
+
+	job, err := a.Jobs.Enqueue(context.Background(), services.JobExpireResetTokens, nil, jobs.Options{})
+	if err != nil {
+		t.Fatalf("failed to enqueue job: %v", err)
+	}
+
+	var listed []models.Job
+	expect(t, a.do(http.MethodGet, "/api/v1/jobs/?type="+services.JobExpireResetTokens+"", token, nil), http.StatusOK).decode(t, &listed)
+	if len(listed) != 1 || listed[0].Id != job.Id {
+		t.Errorf("listed jobs = %+v, want the queued job", listed)
+	}
+	expectError(t, a.do(http.MethodGet, "/api/v1/jobs/?status=bogus", token, nil), http.StatusBadRequest, "validation_failed")
+
+	expect(t, a.do(http.MethodGet, "/api/v1/jobs/"+job.Id, token, nil), http.StatusOK)
+	expectError(t, a.do(http.MethodGet, "/api/v1/jobs/missing", token, nil), http.StatusNotFound, "not_found")
+
+	job.Status = models.JobDead
+	job.Attempts = job.MaxAttempts
+	if err := a.Repositories.Jobs.Update(context.Background(), job); err != nil {
+		t.Fatalf("failed to dead-letter job: %v", err)
+	}
+
+	var retried models.Job
+	expect(t, a.do(http.MethodPost, "/api/v1/jobs/"+job.Id+"/retry", token, nil), http.StatusOK).decode(t, &retried)
+	if retried.Status != models.JobQueued || retried.Attempts != 0 {
+		t.Errorf("retried job is %s with %d attempts, want queued with 0", retried.Status, retried.Attempts)
+	}
+
+	job.Status = models.JobSucceeded
+	if err := a.Repositories.Jobs.Update(context.Background(), job); err != nil {
+		t.Fatalf("failed to complete job: %v", err)
+	}
+	expectError(t, a.do(http.MethodPost, "/api/v1/jobs/"+job.Id+"/retry", token, nil), http.StatusConflict, "conflict")
+}
@@ -0,0 +1,227 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/hamid-nazari/tours-in-go/internal/apperrors"
+	"github.com/hamid-nazari/tours-in-go/internal/config"
+	"github.com/hamid-nazari/tours-in-go/internal/logging"
+	"github.com/hamid-nazari/tours-in-go/internal/realtime"
+	"github.com/hamid-nazari/tours-in-go/internal/services"
+)
+
+const writeWait = 10 * time.Second
+
+type liveRequest struct {
+	Type        string `json:"type"`
+	TourId      string `json:"tourId"`
+	AccessToken string `json:"accessToken"`
+}
+
+type liveReply struct {
+	Type    string `json:"type"`
+	TourId  string `json:"tourId,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type RealtimeController struct {
+	live     *services.LiveUpdates
+	tours    *services.TourService
+	config   config.RealtimeConfig
+	upgrader websocket.Upgrader
+}
+
+func NewRealtimeController(live *services.LiveUpdates, tours *services.TourService, config config.RealtimeConfig) *RealtimeController {
+	rc := &RealtimeController{
+		live:   live,
+		tours:  tours,
+		config: config,
+	}
+	if len(config.AllowedOrigins) > 0 {
+		rc.upgrader.CheckOrigin = func(r *http.Request) bool {
+			return slices.Contains(config.AllowedOrigins, "*") || slices.Contains(config.AllowedOrigins, r.Header.Get("Origin"))
+		}
+	}
+	return rc
+}
+
+func (rc *RealtimeController) EventStreamHandler(c *gin.Context) {
+	tour := rc.tours.FindSharedTourById(c, c.Param("id"), c.Query("accessToken"))
+	if tour == nil {
+		apperrors.Abort(c, apperrors.NotFound("Tour not found"))
+		return
+	}
+
+	events, unsubscribe := rc.live.Subscribe(tour.Id)
+	defer unsubscribe()
+
+	snapshot, err := rc.live.Snapshot(c, tour)
+	if err != nil {
+		apperrors.Abort(c, apperrors.Internal(err))
+		return
+	}
+
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		logging.Package(logging.FromContext(c), "controllers").WarnContext(c, "failed to clear write deadline for event stream", "error", err)
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	if !writeServerSentEvent(c, snapshot) {
+		return
+	}
+
+	heartbeat := time.NewTicker(rc.config.HeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok || !writeServerSentEvent(c, event) {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+func writeServerSentEvent(c *gin.Context, event realtime.Event) bool {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return false
+	}
+	if _, err := fmt.Fprintf(c.Writer, "id: %s\nevent: %s\ndata: %s\n\n", event.Id, event.Type, data); err != nil {
+		return false
+	}
+	c.Writer.Flush()
+	return true
+}
+
+func (rc *RealtimeController) WebSocketHandler(c *gin.Context) {
+	conn, err := rc.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	conn.NetConn().SetDeadline(time.Time{})
+	conn.SetReadDeadline(time.Now().Add(2 * rc.config.HeartbeatInterval))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * rc.config.HeartbeatInterval))
+	})
+
+	events := make(chan realtime.Event, rc.config.BufferSize)
+	replies := make(chan any, rc.config.BufferSize)
+	stopped := make(chan struct{})
+	subscriptions := map[string]func(){}
+
+	send := func(message any) {
+		select {
+		case replies <- message:
+		case <-stopped:
+		}
+	}
+
+	readerDone := make(chan struct{})
+	go func() {
+		defer close(readerDone)
+		for {
+			var request liveRequest
+			if err := conn.ReadJSON(&request); err != nil {
+				return
+			}
+
+			switch request.Type {
+			case "subscribe":
+				if _, ok := subscriptions[request.TourId]; ok {
+					send(liveReply{Type: "subscribed", TourId: request.TourId})
+					continue
+				}
+				if len(subscriptions) >= rc.config.MaxSubscriptions {
+					send(liveReply{Type: "error", TourId: request.TourId, Code: apperrors.CodeBadRequest, Message: fmt.Sprintf("Cannot follow more than %d tours on one connection", rc.config.MaxSubscriptions)})
+					continue
+				}
+				tour := rc.tours.FindSharedTourById(c, request.TourId, request.AccessToken)
+				if tour == nil {
+					send(liveReply{Type: "error", TourId: request.TourId, Code: apperrors.CodeNotFound, Message: "Tour not found"})
+					continue
+				}
+
+				tourEvents, unsubscribe := rc.live.Subscribe(tour.Id)
+				subscriptions[tour.Id] = unsubscribe
+				go func() {
+					for event := range tourEvents {
+						select {
+						case events <- event:
+						default:
+						}
+					}
+				}()
+
+				send(liveReply{Type: "subscribed", TourId: tour.Id})
+				if snapshot, err := rc.live.Snapshot(c, tour); err == nil {
+					send(snapshot)
+				}
+			case "unsubscribe":
+				if unsubscribe, ok := subscriptions[request.TourId]; ok {
+					unsubscribe()
+					delete(subscriptions, request.TourId)
+				}
+				send(liveReply{Type: "unsubscribed", TourId: request.TourId})
+			default:
+				send(liveReply{Type: "error", Code: apperrors.CodeBadRequest, Message: "Message type must be subscribe or unsubscribe"})
+			}
+		}
+	}()
+
+	defer func() {
+		close(stopped)
+		conn.Close()
+		<-readerDone
+		for _, unsubscribe := range subscriptions {
+			unsubscribe()
+		}
+	}()
+
+	heartbeat := time.NewTicker(rc.config.HeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		var err error
+		select {
+		case <-readerDone:
+			return
+		case <-rc.live.Done():
+			conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"), time.Now().Add(writeWait))
+			return
+		case event := <-events:
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			err = conn.WriteJSON(event)
+		case reply := <-replies:
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			err = conn.WriteJSON(reply)
+		case <-heartbeat.C:
+			err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait))
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package controllers_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"github.com/hamid-nazari/tours-in-go/internal/realtime"
+)
+
+func TestEventStreamHandler(t *testing.T) {
+	a := newTestApp(t)
+	tour := a.createTour("The Forest Hiker")
+	secret := a.createTour("The Hidden Valley", func(tour *models.Tour) { tour.SecretTour = true })
+
+	expectError(t, a.do(http.MethodGet, "/api/v1/tours/missing/events", "", nil), http.StatusNotFound, "not_found")
+	expectError(t, a.do(http.MethodGet, "/api/v1/tours/"+secret.Id+"/events", "", nil), http.StatusNotFound, "not_found")
+
+	server := httptest.NewServer(a.Router)
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/tours/"+tour.Id+"/events", nil)
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("failed to open the event stream: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK || response.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("got status %d and content type %q", response.StatusCode, response.Header.Get("Content-Type"))
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(response.Body)
+	for scanner.Scan() && scanner.Text() != "" {
+		lines = append(lines, scanner.Text())
+	}
+	if len(lines) != 3 || lines[1] != "event: "+realtime.EventAvailability || !strings.Contains(lines[2], tour.Id) {
+		t.Errorf("unexpected first event: %q", lines)
+	}
+}
+
+func TestEventStreamHandlerAcceptsAccessToken(t *testing.T) {
+	a := newTestApp(t)
+	secret := a.createTour("The Hidden Valley", func(tour *models.Tour) { tour.SecretTour = true })
+	accessToken, err := a.Tours.CreateTourAccessToken(context.Background(), secret)
+	if err != nil {
+		t.Fatalf("failed to create access token: %v", err)
+	}
+
+	path := "/api/v1/tours/" + secret.Id + "/events"
+	expectError(t, a.do(http.MethodGet, path+"?accessToken=wrong", "", nil), http.StatusNotFound, "not_found")
+
+	server := httptest.NewServer(a.Router)
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+path+"?accessToken="+accessToken, nil)
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("failed to open the event stream: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("got status %d for a valid access token, want %d", response.StatusCode, http.StatusOK)
+	}
+}
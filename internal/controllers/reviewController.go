@@ -5,56 +5,81 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hamid-nazari/tours-in-go/internal/apperrors"
 	"github.com/hamid-nazari/tours-in-go/internal/models"
 	"github.com/hamid-nazari/tours-in-go/internal/services"
 )
 
-func CreateReviewHandler(c *gin.Context) {
+type ReviewController struct {
+	reviews *services.ReviewService
+	tours   *services.TourService
+	audit   *services.AuditService
+}
+
+func NewReviewController(reviews *services.ReviewService, tours *services.TourService, audit *services.AuditService) *ReviewController {
+	return &ReviewController{
+		reviews: reviews,
+		tours:   tours,
+		audit:   audit,
+	}
+}
+
+func (rc *ReviewController) CreateReviewHandler(c *gin.Context) {
 	review := models.NewReview()
 
 	if err := c.ShouldBindJSON(&review); err != nil {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: fmt.Errorf("Failed to bind JSON: %v", err).Error(),
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.Validation(err))
+		return
+	}
+
+	tour := rc.tours.FindSharedTourById(c, review.Tour.Id, c.Query("accessToken"))
+	if tour == nil {
+		apperrors.Abort(c, apperrors.NotFound("Tour not found"))
+		return
+	}
+	review.Tour = *tour
+	review.Tour.AccessToken = ""
+
+	currentUser, ok := c.Get("user")
+	if !ok {
+		apperrors.Abort(c, apperrors.Unauthorized("Unauthorized"))
 		return
 	}
+	review.User = *currentUser.(*models.User)
+	review.User.Password = ""
+	review.User.PasswordConfirm = ""
+	review.Reply = nil
+	review.Reports = []models.ReviewReport{}
+	review.HelpfulCount = 0
+	review.UnhelpfulCount = 0
 
 	if err := services.ValidateReview(*review); err != nil {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: fmt.Errorf("Validation failed: %v", err).Error(),
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.Validation(err))
 		return
 	}
 
-	if err := services.CreateReview(c, review); err != nil {
-		c.JSON(http.StatusInternalServerError, models.CustomResponse{
-			Status:  "Failed",
-			Message: fmt.Errorf("Failed to create review: %v", err).Error(),
-			Data:    nil,
-		})
+	if err := rc.reviews.CreateReview(c, review); err != nil {
+		apperrors.Abort(c, apperrors.Internal(err))
 		return
 	}
 
+	message := "Review created successfully"
+	if review.Status == models.ReviewStatusPending {
+		message = "Review submitted and awaiting moderation"
+	}
+
 	c.JSON(http.StatusOK, models.CustomResponse{
 		Status:  "Success",
-		Message: "Review created successfully",
+		Message: message,
 		Data:    review,
 	})
 
 }
 
-func GetAllReviewsHandler(c *gin.Context) {
-	tours := services.GetAllReviews(c)
+func (rc *ReviewController) GetAllReviewsHandler(c *gin.Context) {
+	tours := rc.reviews.GetAllReviews(c, c.Query("sort"))
 	if tours == nil {
-		c.JSON(http.StatusNotFound, models.CustomResponse{
-			Status:  "Failed",
-			Message: "No reviews not found",
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.NotFound("No reviews found"))
 		return
 	}
 
@@ -65,25 +90,17 @@ func GetAllReviewsHandler(c *gin.Context) {
 	})
 }
 
-func GetReviewHandler(c *gin.Context) {
+func (rc *ReviewController) GetReviewHandler(c *gin.Context) {
 	reviewId := c.Param("id")
 
 	if reviewId == "" {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: "Review ID is required",
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.BadRequest("Review ID is required"))
 		return
 	}
 
-	review := services.GetReviewById(c, reviewId)
-	if review == nil {
-		c.JSON(http.StatusNotFound, models.CustomResponse{
-			Status:  "Failed",
-			Message: "Review not found",
-			Data:    nil,
-		})
+	review := rc.reviews.GetReviewById(c, reviewId)
+	if review == nil || review.Status != models.ReviewStatusPublished {
+		apperrors.Abort(c, apperrors.NotFound("Review not found"))
 		return
 	}
 
@@ -95,52 +112,44 @@ func GetReviewHandler(c *gin.Context) {
 
 }
 
-func UpdateReviewHandler(c *gin.Context) {
+func (rc *ReviewController) UpdateReviewHandler(c *gin.Context) {
 	reviewId := c.Param("id")
 
 	if reviewId == "" {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: "Review ID is required",
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.BadRequest("Review ID is required"))
 		return
 	}
 
-	review := services.GetReviewById(c, reviewId)
+	review := rc.reviews.GetReviewById(c, reviewId)
 	if review == nil {
-		c.JSON(http.StatusNotFound, models.CustomResponse{
-			Status:  "Failed",
-			Message: "Review not found",
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.NotFound("Review not found"))
 		return
 	}
 
+	original := *review
+
 	if err := c.ShouldBindJSON(&review); err != nil {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: fmt.Errorf("Failed to bind JSON: %v", err).Error(),
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.Validation(err))
 		return
 	}
 
+	review.Id = original.Id
+	review.Tour = original.Tour
+	review.User = original.User
+	review.Status = original.Status
+	review.CreatedAt = original.CreatedAt
+	review.Reply = original.Reply
+	review.Reports = original.Reports
+	review.HelpfulCount = original.HelpfulCount
+	review.UnhelpfulCount = original.UnhelpfulCount
+
 	if err := services.ValidateReview(*review); err != nil {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: fmt.Errorf("Validation failed: %v", err).Error(),
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.Validation(err))
 		return
 	}
 
-	if err := services.UpdateReview(c, review); err != nil {
-		c.JSON(http.StatusInternalServerError, models.CustomResponse{
-			Status:  "Failed",
-			Message: fmt.Errorf("Failed to update review: %v", err).Error(),
-			Data:    nil,
-		})
+	if err := rc.reviews.EditReview(c, review); err != nil {
+		apperrors.Abort(c, apperrors.Internal(err))
 		return
 	}
 
@@ -152,35 +161,23 @@ func UpdateReviewHandler(c *gin.Context) {
 
 }
 
-func DeleteReviewHandler(c *gin.Context) {
+func (rc *ReviewController) DeleteReviewHandler(c *gin.Context) {
 
 	reviewId := c.Param("id")
 
 	if reviewId == "" {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: "Review ID is required",
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.BadRequest("Review ID is required"))
 		return
 	}
 
-	review := services.GetReviewById(c, reviewId)
+	review := rc.reviews.GetReviewById(c, reviewId)
 	if review == nil {
-		c.JSON(http.StatusNotFound, models.CustomResponse{
-			Status:  "Failed",
-			Message: "Review not found",
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.NotFound("Review not found"))
 		return
 	}
 
-	if err := services.DeleteReview(c, reviewId); err != nil {
-		c.JSON(http.StatusInternalServerError, models.CustomResponse{
-			Status:  "Failed",
-			Message: fmt.Errorf("Failed to delete review: %v", err).Error(),
-			Data:    nil,
-		})
+	if err := rc.reviews.DeleteReview(c, review); err != nil {
+		apperrors.Abort(c, apperrors.Internal(err))
 		return
 	}
 
@@ -190,3 +187,198 @@ func DeleteReviewHandler(c *gin.Context) {
 		Data:    nil,
 	})
 }
+
+func (rc *ReviewController) GetModerationQueueHandler(c *gin.Context) {
+	reviews, err := rc.reviews.GetModerationQueue(c)
+	if err != nil {
+		apperrors.Abort(c, apperrors.Internal(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CustomResponse{
+		Status:  "Success",
+		Message: fmt.Sprint(len(reviews)) + " reviews awaiting moderation",
+		Data:    reviews,
+	})
+}
+
+func (rc *ReviewController) ModerateReviewHandler(c *gin.Context) {
+	reviewId := c.Param("id")
+
+	review := rc.reviews.GetReviewById(c, reviewId)
+	if review == nil {
+		apperrors.Abort(c, apperrors.NotFound("Review not found"))
+		return
+	}
+
+	var jsonData map[string]string
+
+	if err := c.ShouldBindJSON(&jsonData); err != nil {
+		apperrors.Abort(c, apperrors.Validation(err))
+		return
+	}
+
+	currentUser, _ := c.Get("user")
+	before := services.AuditSnapshot(review)
+
+	if err := rc.reviews.ModerateReview(c, review, currentUser.(*models.User), jsonData["status"], jsonData["reason"]); err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+	rc.audit.Record(c, services.AuditRecord{Action: models.AuditReviewModerate, TargetType: "review", TargetId: review.Id, Before: before, After: review})
+
+	c.JSON(http.StatusOK, models.CustomResponse{
+		Status:  "Success",
+		Message: "Review " + review.Status + " successfully",
+		Data:    review,
+	})
+}
+
+func (rc *ReviewController) ReportReviewHandler(c *gin.Context) {
+	reviewId := c.Param("id")
+
+	review := rc.reviews.GetReviewById(c, reviewId)
+	if review == nil || review.Status != models.ReviewStatusPublished {
+		apperrors.Abort(c, apperrors.NotFound("Review not found"))
+		return
+	}
+
+	var jsonData map[string]string
+
+	if err := c.ShouldBindJSON(&jsonData); err != nil {
+		apperrors.Abort(c, apperrors.Validation(err))
+		return
+	}
+
+	reason := jsonData["reason"]
+	if reason == "" {
+		apperrors.Abort(c, apperrors.InvalidField("reason", "required", "is required"))
+		return
+	}
+
+	currentUser, _ := c.Get("user")
+
+	if err := rc.reviews.ReportReview(c, review, currentUser.(*models.User), reason); err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CustomResponse{
+		Status:  "Success",
+		Message: "Review reported successfully",
+		Data:    nil,
+	})
+}
+
+func (rc *ReviewController) ReplyToReviewHandler(c *gin.Context) {
+	reviewId := c.Param("id")
+
+	review := rc.reviews.GetReviewById(c, reviewId)
+	if review == nil || review.Status != models.ReviewStatusPublished {
+		apperrors.Abort(c, apperrors.NotFound("Review not found"))
+		return
+	}
+
+	var jsonData map[string]string
+
+	if err := c.ShouldBindJSON(&jsonData); err != nil {
+		apperrors.Abort(c, apperrors.Validation(err))
+		return
+	}
+
+	currentUser, _ := c.Get("user")
+
+	if err := rc.reviews.ReplyToReview(c, review, currentUser.(*models.User), jsonData["reply"]); err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CustomResponse{
+		Status:  "Success",
+		Message: "Reply saved successfully",
+		Data:    review,
+	})
+}
+
+func (rc *ReviewController) DeleteReviewReplyHandler(c *gin.Context) {
+	reviewId := c.Param("id")
+
+	review := rc.reviews.GetReviewById(c, reviewId)
+	if review == nil {
+		apperrors.Abort(c, apperrors.NotFound("Review not found"))
+		return
+	}
+
+	currentUser, _ := c.Get("user")
+
+	if err := rc.reviews.DeleteReviewReply(c, review, currentUser.(*models.User)); err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CustomResponse{
+		Status:  "Success",
+		Message: "Reply deleted successfully",
+		Data:    nil,
+	})
+}
+
+func (rc *ReviewController) VoteOnReviewHandler(c *gin.Context) {
+	reviewId := c.Param("id")
+
+	review := rc.reviews.GetReviewById(c, reviewId)
+	if review == nil || review.Status != models.ReviewStatusPublished {
+		apperrors.Abort(c, apperrors.NotFound("Review not found"))
+		return
+	}
+
+	var jsonData struct {
+		Helpful *bool `json:"helpful"`
+	}
+
+	if err := c.ShouldBindJSON(&jsonData); err != nil {
+		apperrors.Abort(c, apperrors.Validation(err))
+		return
+	}
+
+	if jsonData.Helpful == nil {
+		apperrors.Abort(c, apperrors.InvalidField("helpful", "required", "is required"))
+		return
+	}
+
+	currentUser, _ := c.Get("user")
+
+	if err := rc.reviews.VoteOnReview(c, review, currentUser.(*models.User), *jsonData.Helpful); err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CustomResponse{
+		Status:  "Success",
+		Message: "Vote recorded successfully",
+		Data:    review,
+	})
+}
+
+func (rc *ReviewController) RemoveReviewVoteHandler(c *gin.Context) {
+	reviewId := c.Param("id")
+
+	review := rc.reviews.GetReviewById(c, reviewId)
+	if review == nil {
+		apperrors.Abort(c, apperrors.NotFound("Review not found"))
+		return
+	}
+
+	currentUser, _ := c.Get("user")
+
+	if err := rc.reviews.RemoveReviewVote(c, review, currentUser.(*models.User)); err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CustomResponse{
+		Status:  "Success",
+		Message: "Vote removed successfully",
+		Data:    review,
+	})
+}
@@ -0,0 +1,235 @@
+package controllers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hamid-nazari/tours-in-go/internal/config"
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+)
+
+type reviewData struct {
+	Id             string `json:"id"`
+	Status         string `json:"status"`
+	HelpfulCount   int    `json:"helpfulCount"`
+	UnhelpfulCount int    `json:"unhelpfulCount"`
+	Reply          *struct {
+		Reply string `json:"reply"`
+	} `json:"reply"`
+}
+
+func TestCreateReviewHandler(t *testing.T) {
+	a := newTestApp(t)
+	tour := a.createTour("The Forest Hiker")
+	author := a.createUser("user", "author@example.com")
+	token := a.token(author)
+
+	body := map[string]any{"review": "Lovely tour overall", "rating": 5, "tour": map[string]string{"id": tour.Id}}
+	var review reviewData
+	expect(t, a.do(http.MethodPost, "/api/v1/reviews/", token, body), http.StatusOK).decode(t, &review)
+	if review.Status != "pending" {
+		t.Errorf("new review status = %q, want pending", review.Status)
+	}
+	expectError(t, a.do(http.MethodGet, "/api/v1/reviews/"+review.Id, "", nil), http.StatusNotFound, "not_found")
+
+	missing := map[string]any{"review": "Lovely tour overall", "rating": 5, "tour": map[string]string{"id": "missing"}}
+	expectError(t, a.do(http.MethodPost, "/api/v1/reviews/", token, missing), http.StatusNotFound, "not_found")
+
+	expectError(t, a.do(http.MethodPost, "/api/v1/reviews/", "", body), http.StatusUnauthorized, "unauthorized")
+}
+
+func TestCreateReviewForSharedTour(t *testing.T) {
+	a := newTestApp(t)
+	tour := a.createTour("The Hidden Valley", func(tour *models.Tour) { tour.SecretTour = true })
+	author := a.createUser("user", "author@example.com")
+	accessToken, err := a.Tours.CreateTourAccessToken(context.Background(), tour)
+	if err != nil {
+		t.Fatalf("failed to create access token: %v", err)
+	}
+
+	body := map[string]any{"review": "Lovely tour overall", "rating": 5, "tour": map[string]string{"id": tour.Id}}
+	expectError(t, a.do(http.MethodPost, "/api/v1/reviews/", a.token(author), body), http.StatusNotFound, "not_found")
+
+	var review reviewData
+	expect(t, a.do(http.MethodPost, "/api/v1/reviews/?accessToken="+accessToken, a.token(author), body), http.StatusOK).decode(t, &review)
+	if stored := a.Reviews.GetReviewById(context.Background(), review.Id); stored == nil || stored.Tour.Id != tour.Id || stored.Tour.AccessToken != "" {
+		t.Errorf("stored review = %+v, want it linked to the shared tour without its access token", stored)
+	}
+}
+
+func TestReviewPublishedWithoutApproval(t *testing.T) {
+	a := newTestApp(t, func(cfg *config.Config) { cfg.Reviews.RequireApproval = false })
+	tour := a.createTour("The Forest Hiker")
+	author := a.createUser("user", "author@example.com")
+
+	body := map[string]any{"review": "Lovely tour overall", "rating": 5, "tour": map[string]string{"id": tour.Id}}
+	var review reviewData
+	expect(t, a.do(http.MethodPost, "/api/v1/reviews/", a.token(author), body), http.StatusOK).decode(t, &review)
+	if review.Status != "published" {
+		t.Errorf("review status = %q, want published", review.Status)
+	}
+	expect(t, a.do(http.MethodGet, "/api/v1/reviews/"+review.Id, "", nil), http.StatusOK)
+}
+
+func TestReviewModerationAndVotes(t *testing.T) {
+	a := newTestApp(t)
+	tour := a.createTour("The Forest Hiker")
+	author := a.createUser("user", "author@example.com")
+	voter := a.createUser("user", "voter@example.com")
+	admin := a.createUser("admin", "admin@example.com")
+
+	body := map[string]any{"review": "Lovely tour overall", "rating": 5, "tour": map[string]string{"id": tour.Id}}
+	var review reviewData
+	expect(t, a.do(http.MethodPost, "/api/v1/reviews/", a.token(author), body), http.StatusOK).decode(t, &review)
+	path := "/api/v1/reviews/" + review.Id
+
+	expectError(t, a.do(http.MethodPatch, path+"/moderate", a.token(author), map[string]string{"status": "published"}), http.StatusForbidden, "forbidden")
+	expectError(t, a.do(http.MethodPost, path+"/vote", a.token(voter), map[string]bool{"helpful": true}), http.StatusNotFound, "not_found")
+
+	var queue []reviewData
+	expect(t, a.do(http.MethodGet, "/api/v1/reviews/moderation", a.token(admin), nil), http.StatusOK).decode(t, &queue)
+	if len(queue) != 1 || queue[0].Id != review.Id {
+		t.Fatalf("moderation queue = %+v, want the new review", queue)
+	}
+
+	expect(t, a.do(http.MethodPatch, path+"/moderate", a.token(admin), map[string]string{"status": "published"}), http.StatusOK).decode(t, &review)
+	if review.Status != "published" {
+		t.Fatalf("moderated review status = %q, want published", review.Status)
+	}
+
+	expectError(t, a.do(http.MethodPost, path+"/vote", a.token(voter), map[string]any{}), http.StatusBadRequest, "validation_failed")
+	expectError(t, a.do(http.MethodPost, path+"/vote", a.token(author), map[string]bool{"helpful": true}), http.StatusBadRequest, "bad_request")
+
+	expect(t, a.do(http.MethodPost, path+"/vote", a.token(voter), map[string]bool{"helpful": true}), http.StatusOK).decode(t, &review)
+	if review.HelpfulCount != 1 || review.UnhelpfulCount != 0 {
+		t.Errorf("after a helpful vote got %d/%d, want 1/0", review.HelpfulCount, review.UnhelpfulCount)
+	}
+	expect(t, a.do(http.MethodPost, path+"/vote", a.token(voter), map[string]bool{"helpful": false}), http.StatusOK).decode(t, &review)
+	if review.HelpfulCount != 0 || review.UnhelpfulCount != 1 {
+		t.Errorf("after changing the vote got %d/%d, want 0/1", review.HelpfulCount, review.UnhelpfulCount)
+	}
+
+	expect(t, a.do(http.MethodDelete, path+"/vote", a.token(voter), nil), http.StatusOK).decode(t, &review)
+	if review.HelpfulCount != 0 || review.UnhelpfulCount != 0 {
+		t.Errorf("after removing the vote got %d/%d, want 0/0", review.HelpfulCount, review.UnhelpfulCount)
+	}
+	expectError(t, a.do(http.MethodDelete, path+"/vote", a.token(voter), nil), http.StatusBadRequest, "bad_request")
+}
+
+func TestReviewVotesFromStaleCopies(t *testing.T) {
+	a := newTestApp(t, func(cfg *config.Config) { cfg.Reviews.RequireApproval = false })
+	tour := a.createTour("The Forest Hiker")
+	author := a.createUser("user", "author@example.com")
+	first := a.createUser("user", "first@example.com")
+	second := a.createUser("user", "second@example.com")
+	ctx := context.Background()
+
+	body := map[string]any{"review": "Lovely tour overall", "rating": 5, "tour": map[string]string{"id": tour.Id}}
+	var review reviewData
+	expect(t, a.do(http.MethodPost, "/api/v1/reviews/", a.token(author), body), http.StatusOK).decode(t, &review)
+
+	firstCopy, secondCopy, staleCopy := a.Reviews.GetReviewById(ctx, review.Id), a.Reviews.GetReviewById(ctx, review.Id), a.Reviews.GetReviewById(ctx, review.Id)
+	if err := a.Reviews.VoteOnReview(ctx, firstCopy, first, true); err != nil {
+		t.Fatalf("first vote failed: %v", err)
+	}
+	if err := a.Reviews.VoteOnReview(ctx, secondCopy, second, false); err != nil {
+		t.Fatalf("second vote failed: %v", err)
+	}
+	if secondCopy.HelpfulCount != 1 || secondCopy.UnhelpfulCount != 1 {
+		t.Errorf("after two votes on separate copies got %d/%d, want 1/1", secondCopy.HelpfulCount, secondCopy.UnhelpfulCount)
+	}
+
+	staleCopy.Review = "Lovely tour, edited"
+	if err := a.Reviews.UpdateReview(ctx, staleCopy); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	stored := a.Reviews.GetReviewById(ctx, review.Id)
+	if stored.HelpfulCount != 1 || stored.UnhelpfulCount != 1 || len(stored.Votes) != 2 {
+		t.Errorf("updating a stale copy left %d/%d with %d votes, want 1/1 with 2", stored.HelpfulCount, stored.UnhelpfulCount, len(stored.Votes))
+	}
+
+	if err := a.Reviews.RemoveReviewVote(ctx, firstCopy, second); err != nil {
+		t.Fatalf("removing the vote failed: %v", err)
+	}
+	if firstCopy.HelpfulCount != 1 || firstCopy.UnhelpfulCount != 0 {
+		t.Errorf("after removing the second vote got %d/%d, want 1/0", firstCopy.HelpfulCount, firstCopy.UnhelpfulCount)
+	}
+}
+
+func TestReportReviewHandler(t *testing.T) {
+	a := newTestApp(t, func(cfg *config.Config) { cfg.Reviews.RequireApproval = false })
+	tour := a.createTour("The Forest Hiker")
+	author := a.createUser("user", "author@example.com")
+	reporter := a.createUser("user", "reporter@example.com")
+
+	body := map[string]any{"review": "Lovely tour overall", "rating": 5, "tour": map[string]string{"id": tour.Id}}
+	var review reviewData
+	expect(t, a.do(http.MethodPost, "/api/v1/reviews/", a.token(author), body), http.StatusOK).decode(t, &review)
+	path := "/api/v1/reviews/" + review.Id + "/report"
+
+	expectError(t, a.do(http.MethodPost, path, a.token(reporter), map[string]string{}), http.StatusBadRequest, "validation_failed")
+	expect(t, a.do(http.MethodPost, path, a.token(reporter), map[string]string{"reason": "spam"}), http.StatusOK)
+
+	expectError(t, a.do(http.MethodPost, path, a.token(reporter), map[string]string{"reason": "spam"}), http.StatusConflict, "conflict")
+
+	stored := a.Reviews.GetReviewById(context.Background(), review.Id)
+	if stored == nil || len(stored.Reports) != 1 || stored.Reports[0].UserId != reporter.Id {
+		t.Errorf("stored reports = %+v, want one report by the reporter", stored)
+	}
+}
+
+func TestReviewReportsSurviveEdits(t *testing.T) {
+	a := newTestApp(t, func(cfg *config.Config) {
+		cfg.Reviews.RequireApproval = false
+		cfg.Reviews.ReportThreshold = 3
+	})
+	tour := a.createTour("The Forest Hiker")
+	author := a.createUser("user", "author@example.com")
+	ctx := context.Background()
+
+	body := map[string]any{"review": "Lovely tour overall", "rating": 5, "tour": map[string]string{"id": tour.Id}}
+	var review reviewData
+	expect(t, a.do(http.MethodPost, "/api/v1/reviews/", a.token(author), body), http.StatusOK).decode(t, &review)
+	path := "/api/v1/reviews/" + review.Id
+
+	staleCopy := a.Reviews.GetReviewById(ctx, review.Id)
+	for _, email := range []string{"first@example.com", "second@example.com"} {
+		expect(t, a.do(http.MethodPost, path+"/report", a.token(a.createUser("user", email)), map[string]string{"reason": "spam"}), http.StatusOK)
+	}
+	if err := a.Reviews.ReportReview(ctx, staleCopy, a.createUser("user", "third@example.com"), "spam"); err != nil {
+		t.Fatalf("reporting a stale copy failed: %v", err)
+	}
+	if len(staleCopy.Reports) != 3 || staleCopy.Status != "pending" {
+		t.Fatalf("after three reports got %d reports and status %q, want 3 and pending", len(staleCopy.Reports), staleCopy.Status)
+	}
+
+	expect(t, a.do(http.MethodPatch, path, a.token(author), map[string]any{"review": "Lovely tour, edited", "reports": []any{}}), http.StatusOK).decode(t, &review)
+	stored := a.Reviews.GetReviewById(ctx, review.Id)
+	if len(stored.Reports) != 3 || stored.Status != "pending" || stored.Review != "Lovely tour, edited" {
+		t.Errorf("after editing got %d reports, status %q and text %q, want 3 reports kept and pending", len(stored.Reports), stored.Status, stored.Review)
+	}
+}
+
+func TestReviewReplyHandlers(t *testing.T) {
+	a := newTestApp(t, func(cfg *config.Config) { cfg.Reviews.RequireApproval = false })
+	guide := a.createUser("guide", "guide@example.com")
+	tour := a.createTour("The Forest Hiker")
+	tour.Guides = []string{guide.Id}
+	if err := a.Tours.UpdateTour(context.Background(), tour); err != nil {
+		t.Fatalf("failed to assign guide: %v", err)
+	}
+	author := a.createUser("user", "author@example.com")
+
+	body := map[string]any{"review": "Lovely tour overall", "rating": 5, "tour": map[string]string{"id": tour.Id}}
+	var review reviewData
+	expect(t, a.do(http.MethodPost, "/api/v1/reviews/", a.token(author), body), http.StatusOK).decode(t, &review)
+	path := "/api/v1/reviews/" + review.Id + "/reply"
+
+	expectError(t, a.do(http.MethodPut, path, a.token(author), map[string]string{"reply": "Thanks!"}), http.StatusForbidden, "forbidden")
+	expect(t, a.do(http.MethodPut, path, a.token(guide), map[string]string{"reply": "Thanks!"}), http.StatusOK).decode(t, &review)
+	if review.Reply == nil || review.Reply.Reply != "Thanks!" {
+		t.Errorf("reply = %+v, want Thanks!", review.Reply)
+	}
+	expect(t, a.do(http.MethodDelete, path, a.token(guide), nil), http.StatusOK)
+}
@@ -3,41 +3,63 @@ package controllers
 import (
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hamid-nazari/tours-in-go/internal/apperrors"
 	"github.com/hamid-nazari/tours-in-go/internal/models"
 	"github.com/hamid-nazari/tours-in-go/internal/services"
 )
 
-func CreateTourHandler(c *gin.Context) {
+type TourController struct {
+	tours *services.TourService
+	users *services.UserService
+	audit *services.AuditService
+}
+
+func NewTourController(tours *services.TourService, users *services.UserService, audit *services.AuditService) *TourController {
+	return &TourController{
+		tours: tours,
+		users: users,
+		audit: audit,
+	}
+}
+
+func (tc *TourController) CreateTourHandler(c *gin.Context) {
 	tour := models.NewTour()
 
 	if err := c.ShouldBindJSON(&tour); err != nil {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: fmt.Errorf("Failed to bind JSON: %v", err).Error(),
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.Validation(err))
 		return
 	}
 
 	if err := services.ValidateTour(*tour); err != nil {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: fmt.Errorf("Validation failed: %v", err).Error(),
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.Validation(err))
+		return
+	}
+
+	if err := tc.tours.ValidateTourGuides(c, tour); err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+
+	tour.Slug = ""
+	tour.PreviousSlugs = nil
+
+	if err := tc.tours.GenerateTourSlug(c, tour); err != nil {
+		apperrors.Abort(c, apperrors.Internal(err))
 		return
 	}
 
-	if err := services.CreateTour(c, tour); err != nil {
-		c.JSON(http.StatusInternalServerError, models.CustomResponse{
-			Status:  "Failed",
-			Message: fmt.Errorf("Failed to create tour: %v", err).Error(),
-			Data:    nil,
-		})
+	if err := tc.tours.CreateTour(c, tour); err != nil {
+		apperrors.Abort(c, apperrors.Internal(err))
 		return
 	}
+	tc.audit.Record(c, services.AuditRecord{Action: models.AuditTourCreate, TargetType: "tour", TargetId: tour.Id, After: tour})
+
+	if populated := tc.tours.FindTourById(c, tour.Id); populated != nil {
+		tour = populated
+	}
 
 	c.JSON(http.StatusOK, models.CustomResponse{
 		Status:  "Success",
@@ -47,14 +69,10 @@ func CreateTourHandler(c *gin.Context) {
 
 }
 
-func GetAllToursHandler(c *gin.Context) {
-	tours := services.GetAllTours(c)
+func (tc *TourController) GetAllToursHandler(c *gin.Context) {
+	tours := tc.tours.GetAllTours(c)
 	if tours == nil {
-		c.JSON(http.StatusNotFound, models.CustomResponse{
-			Status:  "Failed",
-			Message: "No tours not found",
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.NotFound("No tours found"))
 		return
 	}
 
@@ -66,25 +84,17 @@ func GetAllToursHandler(c *gin.Context) {
 
 }
 
-func GetTourHandler(c *gin.Context) {
+func (tc *TourController) GetTourHandler(c *gin.Context) {
 	tourId := c.Param("id")
 
 	if tourId == "" {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: "Tour ID is required",
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.BadRequest("Tour ID is required"))
 		return
 	}
 
-	tour := services.FindTourById(c, tourId)
+	tour := tc.tours.FindTourById(c, tourId)
 	if tour == nil {
-		c.JSON(http.StatusNotFound, models.CustomResponse{
-			Status:  "Failed",
-			Message: "Tour not found",
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.NotFound("Tour not found"))
 		return
 	}
 
@@ -96,54 +106,79 @@ func GetTourHandler(c *gin.Context) {
 
 }
 
-func UpdateTourHandler(c *gin.Context) {
+func (tc *TourController) GetTourBySlugHandler(c *gin.Context) {
+	tourSlug := c.Param("slug")
+
+	tour := tc.tours.FindTourBySlug(c, tourSlug)
+	if tour == nil {
+		if renamed := tc.tours.FindTourByPreviousSlug(c, tourSlug); renamed != nil {
+			location := strings.TrimSuffix(c.Request.URL.Path, tourSlug) + renamed.Slug
+			if c.Request.URL.RawQuery != "" {
+				location += "?" + c.Request.URL.RawQuery
+			}
+			c.Redirect(http.StatusMovedPermanently, location)
+			return
+		}
+
+		apperrors.Abort(c, apperrors.NotFound("Tour not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CustomResponse{
+		Status:  "Success",
+		Message: "Tour retrieved successfully",
+		Data:    tour,
+	})
+}
+
+func (tc *TourController) UpdateTourHandler(c *gin.Context) {
 	tourId := c.Param("id")
 
 	if tourId == "" {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: "Tour ID is required",
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.BadRequest("Tour ID is required"))
 		return
 	}
 
-	tour := services.FindTourById(c, tourId)
+	tour := tc.tours.FindTourById(c, tourId)
 	if tour == nil {
-		c.JSON(http.StatusNotFound, models.CustomResponse{
-			Status:  "Failed",
-			Message: "Tour not found",
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.NotFound("Tour not found"))
 		return
 	}
 
+	slug, previousSlugs := tour.Slug, tour.PreviousSlugs
+	before := services.AuditSnapshot(tour)
+
 	if err := c.ShouldBindJSON(&tour); err != nil {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: err.Error(),
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.Validation(err))
 		return
 	}
 
+	tour.Slug, tour.PreviousSlugs = slug, previousSlugs
+
 	if err := services.ValidateTour(*tour); err != nil {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: fmt.Sprintf("Validation failed: %v", err),
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.Validation(err))
+		return
+	}
+
+	if err := tc.tours.ValidateTourGuides(c, tour); err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+
+	if err := tc.tours.GenerateTourSlug(c, tour); err != nil {
+		apperrors.Abort(c, apperrors.Internal(err))
 		return
 	}
 
-	if err := services.UpdateTour(c, tour); err != nil {
-		c.JSON(http.StatusInternalServerError, models.CustomResponse{
-			Status:  "Failed",
-			Message: "Failed to update tour",
-			Data:    nil,
-		})
+	if err := tc.tours.UpdateTour(c, tour); err != nil {
+		apperrors.Abort(c, apperrors.Internal(err))
 		return
 	}
+	tc.audit.Record(c, services.AuditRecord{Action: models.AuditTourUpdate, TargetType: "tour", TargetId: tour.Id, Before: before, After: tour})
+
+	if populated := tc.tours.FindTourById(c, tour.Id); populated != nil {
+		tour = populated
+	}
 
 	c.JSON(http.StatusOK, models.CustomResponse{
 		Status:  "Success",
@@ -153,36 +188,115 @@ func UpdateTourHandler(c *gin.Context) {
 
 }
 
-func DeleteTourHandler(c *gin.Context) {
+func (tc *TourController) DeleteTourHandler(c *gin.Context) {
 
 	tourId := c.Param("id")
 
 	if tourId == "" {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: "Tour ID is required",
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.BadRequest("Tour ID is required"))
+		return
+	}
+
+	tour := tc.tours.FindTourById(c, tourId)
+	if tour == nil {
+		apperrors.Abort(c, apperrors.NotFound("Tour not found"))
+		return
+	}
+
+	if err := tc.tours.DeleteTour(c, tour); err != nil {
+		apperrors.Abort(c, apperrors.Internal(err))
+		return
+	}
+	tc.audit.Record(c, services.AuditRecord{Action: models.AuditTourDelete, TargetType: "tour", TargetId: tour.Id, Before: tour})
+
+}
+
+func (tc *TourController) GetGuideToursHandler(c *gin.Context) {
+	guideId := c.Param("id")
+
+	guide := tc.users.FindUserById(c, guideId)
+	if guide == nil || (guide.Role != "guide" && guide.Role != "lead-guide") {
+		apperrors.Abort(c, apperrors.NotFound("Guide not found"))
+		return
+	}
+
+	tours, err := tc.tours.GetToursByGuide(c, guideId)
+	if err != nil {
+		apperrors.Abort(c, apperrors.Internal(err))
 		return
 	}
 
-	tour := services.FindTourById(c, tourId)
+	c.JSON(http.StatusOK, models.CustomResponse{
+		Status:  "Success",
+		Message: fmt.Sprint(len(tours)) + " tours found",
+		Data:    tours,
+	})
+}
+
+func (tc *TourController) CreateTourShareLinkHandler(c *gin.Context) {
+	tourId := c.Param("id")
+
+	tour := tc.tours.FindTourById(c, tourId)
 	if tour == nil {
-		c.JSON(http.StatusNotFound, models.CustomResponse{
-			Status:  "Failed",
-			Message: "Tour not found",
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.NotFound("Tour not found"))
+		return
+	}
+
+	if !tour.SecretTour {
+		apperrors.Abort(c, apperrors.BadRequest("Only secret tours can be shared with an access link"))
 		return
 	}
 
-	if err := services.DeleteTour(c, tour); err != nil {
-		c.JSON(http.StatusInternalServerError, models.CustomResponse{
-			Status:  "Failed",
-			Message: fmt.Sprintf("Failed to delete tour: %v", err),
-			Data:    nil,
-		})
+	accessToken, err := tc.tours.CreateTourAccessToken(c, tour)
+	if err != nil {
+		apperrors.Abort(c, apperrors.Internal(err))
 		return
 	}
 
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	shareURL := fmt.Sprintf("%s://%s%s/shared/%s", scheme, c.Request.Host, strings.TrimSuffix(c.Request.URL.Path, "/"+tourId+"/share-link"), accessToken)
+
+	c.JSON(http.StatusOK, models.CustomResponse{
+		Status:  "Success",
+		Message: "Share link created successfully",
+		Data:    gin.H{"shareUrl": shareURL},
+	})
+}
+
+func (tc *TourController) RevokeTourShareLinkHandler(c *gin.Context) {
+	tourId := c.Param("id")
+
+	tour := tc.tours.FindTourById(c, tourId)
+	if tour == nil {
+		apperrors.Abort(c, apperrors.NotFound("Tour not found"))
+		return
+	}
+
+	if err := tc.tours.RevokeTourAccessToken(c, tour); err != nil {
+		apperrors.Abort(c, apperrors.Internal(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CustomResponse{
+		Status:  "Success",
+		Message: "Share link revoked successfully",
+		Data:    nil,
+	})
+}
+
+func (tc *TourController) GetSharedTourHandler(c *gin.Context) {
+	tour := tc.tours.FindTourByAccessToken(c, c.Param("token"))
+	if tour == nil {
+		apperrors.Abort(c, apperrors.NotFound("Tour not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CustomResponse{
+		Status:  "Success",
+		Message: "Tour retrieved successfully",
+		Data:    tour,
+	})
 }
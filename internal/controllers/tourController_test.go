@@ -0,0 +1,143 @@
+package controllers_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+)
+
+type tourData struct {
+	Id    string  `json:"id"`
+	Name  string  `json:"name"`
+	Slug  string  `json:"slug"`
+	Price float64 `json:"price"`
+}
+
+func TestGetAllToursHandler(t *testing.T) {
+	a := newTestApp(t)
+	expectError(t, a.do(http.MethodGet, "/api/v1/tours/", "", nil), http.StatusNotFound, "not_found")
+
+	a.createTour("The Forest Hiker")
+	a.createTour("The Sea Explorer")
+	a.createTour("The Secret Cave", func(tour *models.Tour) { tour.SecretTour = true })
+	admin := a.createUser("admin", "admin@example.com")
+
+	var tours []tourData
+	expect(t, a.do(http.MethodGet, "/api/v1/tours/", "", nil), http.StatusOK).decode(t, &tours)
+	if len(tours) != 2 || tours[0].Name != "The Forest Hiker" || tours[1].Name != "The Sea Explorer" {
+		t.Errorf("public listing = %+v, want the two public tours oldest first", tours)
+	}
+
+	expect(t, a.do(http.MethodGet, "/api/v1/tours/?includeSecret=true", "", nil), http.StatusOK).decode(t, &tours)
+	if len(tours) != 2 {
+		t.Errorf("anonymous includeSecret listed %d tours, want 2", len(tours))
+	}
+
+	expect(t, a.do(http.MethodGet, "/api/v1/tours/?includeSecret=true", a.token(admin), nil), http.StatusOK).decode(t, &tours)
+	if len(tours) != 3 {
+		t.Errorf("admin includeSecret listed %d tours, want 3", len(tours))
+	}
+}
+
+func TestTourWriteHandlers(t *testing.T) {
+	a := newTestApp(t)
+	lead := a.createUser("lead-guide", "lead@example.com")
+	token := a.token(lead)
+
+	create := map[string]any{"name": "The Forest Hiker", "duration": "5", "price": 397, "maxGroupSize": 25, "ratingAvg": 4.5, "summary": "Breathtaking hike"}
+	var created tourData
+	expect(t, a.do(http.MethodPost, "/api/v1/tours/", token, create), http.StatusOK).decode(t, &created)
+	if created.Id == "" || created.Slug != "the-forest-hiker" {
+		t.Fatalf("unexpected created tour: %+v", created)
+	}
+
+	expectError(t, a.do(http.MethodPost, "/api/v1/tours/", token, map[string]any{"name": "No price"}), http.StatusBadRequest, "validation_failed")
+
+	user := a.createUser("user", "user@example.com")
+	expectError(t, a.do(http.MethodPost, "/api/v1/tours/", a.token(user), create), http.StatusForbidden, "forbidden")
+
+	var updated tourData
+	expect(t, a.do(http.MethodPatch, "/api/v1/tours/"+created.Id, token, map[string]any{"name": "The Forest Runner", "price": 450}), http.StatusOK).decode(t, &updated)
+	if updated.Price != 450 || updated.Slug != "the-forest-runner" {
+		t.Errorf("unexpected updated tour: %+v", updated)
+	}
+
+	recorder := a.do(http.MethodGet, "/api/v1/tours/slug/the-forest-hiker", "", nil)
+	if recorder.Code != http.StatusMovedPermanently || !strings.HasSuffix(recorder.Header().Get("Location"), "/the-forest-runner") {
+		t.Errorf("old slug answered %d with location %q, want a redirect to the new slug", recorder.Code, recorder.Header().Get("Location"))
+	}
+	recorder = a.do(http.MethodGet, "/api/v1/tours/slug/the-forest-hiker?includeSecret=true", "", nil)
+	if location := recorder.Header().Get("Location"); location != "/api/v1/tours/slug/the-forest-runner?includeSecret=true" {
+		t.Errorf("old slug with a query redirected to %q, want the query kept", location)
+	}
+
+	a.do(http.MethodDelete, "/api/v1/tours/"+created.Id, token, nil)
+	expectError(t, a.do(http.MethodGet, "/api/v1/tours/"+created.Id, token, nil), http.StatusNotFound, "not_found")
+}
+
+func TestGenerateTourSlugKeepsSlugsUnique(t *testing.T) {
+	a := newTestApp(t)
+	ctx := context.Background()
+	a.createTour("The Forest Hiker")
+	a.createTour("The Forest Hiker")
+
+	imported := models.NewTour()
+	imported.Name = "The Forest Hiker"
+	imported.Slug = "the-forest-hiker-2"
+	if err := a.Tours.GenerateTourSlug(ctx, imported); err != nil {
+		t.Fatalf("failed to generate slug: %v", err)
+	}
+	if imported.Slug != "the-forest-hiker-3" || len(imported.PreviousSlugs) != 0 {
+		t.Errorf("imported tour got slug %q and previous slugs %v, want the-forest-hiker-3 and none", imported.Slug, imported.PreviousSlugs)
+	}
+
+	own := a.createTour("The Sea Explorer")
+	if err := a.Tours.GenerateTourSlug(ctx, own); err != nil {
+		t.Fatalf("failed to generate slug: %v", err)
+	}
+	if own.Slug != "the-sea-explorer" {
+		t.Errorf("regenerating an unchanged slug gave %q, want the-sea-explorer", own.Slug)
+	}
+}
+
+func TestTourShareLinkHandlers(t *testing.T) {
+	a := newTestApp(t)
+	admin := a.createUser("admin", "admin@example.com")
+	token := a.token(admin)
+	public := a.createTour("The Forest Hiker")
+	secret := a.createTour("The Secret Cave", func(tour *models.Tour) { tour.SecretTour = true })
+
+	expectError(t, a.do(http.MethodPost, "/api/v1/tours/"+public.Id+"/share-link", token, nil), http.StatusBadRequest, "bad_request")
+	expectError(t, a.do(http.MethodGet, "/api/v1/tours/slug/"+secret.Slug, "", nil), http.StatusNotFound, "not_found")
+
+	var link struct {
+		ShareURL string `json:"shareUrl"`
+	}
+	expect(t, a.do(http.MethodPost, "/api/v1/tours/"+secret.Id+"/share-link", token, nil), http.StatusOK).decode(t, &link)
+	sharedPath := link.ShareURL[strings.Index(link.ShareURL, "/api/"):]
+
+	var shared tourData
+	expect(t, a.do(http.MethodGet, sharedPath, "", nil), http.StatusOK).decode(t, &shared)
+	if shared.Id != secret.Id {
+		t.Errorf("share link resolved to %q, want %q", shared.Id, secret.Id)
+	}
+
+	expect(t, a.do(http.MethodDelete, "/api/v1/tours/"+secret.Id+"/share-link", token, nil), http.StatusOK)
+	expectError(t, a.do(http.MethodGet, sharedPath, "", nil), http.StatusNotFound, "not_found")
+}
+
+func TestGetGuideToursHandler(t *testing.T) {
+	a := newTestApp(t)
+	guide := a.createUser("guide", "guide@example.com")
+	a.createTour("The Forest Hiker", func(tour *models.Tour) { tour.Guides = []string{guide.Id} })
+	a.createTour("The Sea Explorer")
+
+	var tours []tourData
+	expect(t, a.do(http.MethodGet, "/api/v1/users/"+guide.Id+"/tours", "", nil), http.StatusOK).decode(t, &tours)
+	if len(tours) != 1 || tours[0].Name != "The Forest Hiker" {
+		t.Errorf("guide tours = %+v, want only The Forest Hiker", tours)
+	}
+}
@@ -5,65 +5,59 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hamid-nazari/tours-in-go/internal/apperrors"
 	"github.com/hamid-nazari/tours-in-go/internal/models"
 	"github.com/hamid-nazari/tours-in-go/internal/services"
 )
 
-func ResizeUserPhotoHandler(c *gin.Context) {
+type UserController struct {
+	users *services.UserService
+	audit *services.AuditService
+}
+
+func NewUserController(users *services.UserService, audit *services.AuditService) *UserController {
+	return &UserController{
+		users: users,
+		audit: audit,
+	}
+}
+
+func (uc *UserController) ResizeUserPhotoHandler(c *gin.Context) {
 
 }
 
-func CreateUserHandler(c *gin.Context) {
+func (uc *UserController) CreateUserHandler(c *gin.Context) {
 
 	newUser := models.NewUser()
 
 	if err := c.ShouldBindJSON(&newUser); err != nil {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: err.Error(),
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.Validation(err))
 		return
 	}
 
 	if err := services.ValidateUser(*newUser); err != nil {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: err.Error(),
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.Validation(err))
 		return
 	}
 
-	if existingUser := services.FindUserByEmail(c, newUser.Email); existingUser != nil {
-		c.JSON(http.StatusConflict, models.CustomResponse{
-			Status:  "Failed",
-			Message: "A user with this email already exists",
-			Data:    nil,
-		})
+	if existingUser := uc.users.FindUserByEmail(c, newUser.Email); existingUser != nil {
+		apperrors.Abort(c, services.ErrEmailTaken)
 		return
 	}
 
 	hashedPassword, err := services.HashPassword(newUser.Password)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.CustomResponse{
-			Status:  "Failed",
-			Message: err.Error(),
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.Internal(err))
 		return
 	}
 	newUser.Password = hashedPassword
 	newUser.PasswordConfirm = ""
 
-	if err := services.CreateUser(c, newUser); err != nil {
-		c.JSON(http.StatusInternalServerError, models.CustomResponse{
-			Status:  "Failed",
-			Message: err.Error(),
-			Data:    nil,
-		})
+	if err := uc.users.CreateUser(c, newUser); err != nil {
+		apperrors.Abort(c, err)
 		return
 	}
+	uc.audit.Record(c, services.AuditRecord{Action: models.AuditUserCreate, TargetType: "user", TargetId: newUser.Id, After: newUser})
 
 	c.JSON(http.StatusOK, models.CustomResponse{
 		Status:  "Success",
@@ -73,15 +67,11 @@ func CreateUserHandler(c *gin.Context) {
 
 }
 
-func GetAllUsersHandler(c *gin.Context) {
+func (uc *UserController) GetAllUsersHandler(c *gin.Context) {
 
-	users, err := services.GetAllUsers(c)
+	users, err := uc.users.GetAllUsers(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.CustomResponse{
-			Status:  "Failed",
-			Message: err.Error(),
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.Internal(err))
 		return
 	}
 
@@ -92,17 +82,13 @@ func GetAllUsersHandler(c *gin.Context) {
 	})
 }
 
-func GetUserHandler(c *gin.Context) {
+func (uc *UserController) GetUserHandler(c *gin.Context) {
 
 	id := c.Param("id")
 
-	user := services.FindUserById(c, id)
+	user := uc.users.FindUserById(c, id)
 	if user == nil {
-		c.JSON(http.StatusNotFound, models.CustomResponse{
-			Status:  "Failed",
-			Message: "User not found",
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.NotFound("User not found"))
 		return
 	}
 
@@ -113,50 +99,37 @@ func GetUserHandler(c *gin.Context) {
 	})
 }
 
-func UpdateUserHandler(c *gin.Context) {
+func (uc *UserController) UpdateUserHandler(c *gin.Context) {
 
 	id := c.Param("id")
 
-	user := services.FindUserById(c, id)
+	user := uc.users.FindUserById(c, id)
 	if user == nil {
-		c.JSON(http.StatusNotFound, models.CustomResponse{
-			Status:  "Failed",
-			Message: "User not found",
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.NotFound("User not found"))
 		return
 	}
 
+	before := services.AuditSnapshot(user)
+
 	form, err := c.MultipartForm()
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: err.Error(),
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.Validation(err))
 		return
 	}
 	name := form.Value["name"][0]
 	photo := form.File["photo"][0].Filename
 	if name == "" {
-		c.JSON(http.StatusBadRequest, models.CustomResponse{
-			Status:  "Failed",
-			Message: "Name is required",
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.InvalidField("name", "required", "is required"))
 		return
 	}
 	user.Name = name
 	user.Photo = photo
 
-	if err := services.UpdateUser(c, user); err != nil {
-		c.JSON(http.StatusInternalServerError, models.CustomResponse{
-			Status:  "Failed",
-			Message: err.Error(),
-			Data:    nil,
-		})
+	if err := uc.users.UpdateUser(c, user); err != nil {
+		apperrors.Abort(c, err)
 		return
 	}
+	uc.audit.Record(c, services.AuditRecord{Action: models.AuditUserUpdate, TargetType: "user", TargetId: user.Id, Before: before, After: user})
 
 	c.JSON(http.StatusOK, models.CustomResponse{
 		Status:  "Success",
@@ -165,25 +138,18 @@ func UpdateUserHandler(c *gin.Context) {
 	})
 }
 
-func DeleteAllUsersHandler(c *gin.Context) {
-	users, err := services.GetAllUsers(c)
+func (uc *UserController) DeleteAllUsersHandler(c *gin.Context) {
+	users, err := uc.users.GetAllUsers(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.CustomResponse{
-			Status:  "Failed",
-			Message: err.Error(),
-			Data:    nil,
-		})
+		apperrors.Abort(c, apperrors.Internal(err))
 		return
 	}
 
-	if err := services.DeleteAllUsers(c); err != nil {
-		c.JSON(http.StatusInternalServerError, models.CustomResponse{
-			Status:  "Failed",
-			Message: err.Error(),
-			Data:    nil,
-		})
+	if err := uc.users.DeleteAllUsers(c); err != nil {
+		apperrors.Abort(c, apperrors.Internal(err))
 		return
 	}
+	uc.audit.Record(c, services.AuditRecord{Action: models.AuditUserDeleteAll, TargetType: "user", Before: map[string]int{"count": len(users)}, After: map[string]int{"count": 0}})
 
 	c.JSON(http.StatusOK, models.CustomResponse{
 		Status:  "Success",
@@ -192,18 +158,16 @@ func DeleteAllUsersHandler(c *gin.Context) {
 	})
 }
 
-func DeleteUserdHandler(c *gin.Context) {
+func (uc *UserController) DeleteUserdHandler(c *gin.Context) {
 
 	id := c.Param("id")
+	user := uc.users.FindUserById(c, id)
 
-	if err := services.DeleteUser(c, id); err != nil {
-		c.JSON(http.StatusInternalServerError, models.CustomResponse{
-			Status:  "Failed",
-			Message: err.Error(),
-			Data:    nil,
-		})
+	if err := uc.users.DeleteUser(c, id); err != nil {
+		apperrors.Abort(c, apperrors.Internal(err))
 		return
 	}
+	uc.audit.Record(c, services.AuditRecord{Action: models.AuditUserDelete, TargetType: "user", TargetId: id, Before: user})
 
 	c.JSON(http.StatusOK, models.CustomResponse{
 		Status:  "Success",
@@ -212,11 +176,11 @@ func DeleteUserdHandler(c *gin.Context) {
 	})
 }
 
-func GetMeHandler(c *gin.Context) {
+func (uc *UserController) GetMeHandler(c *gin.Context) {
 
 }
-func UpdateMeHandler(c *gin.Context) {
+func (uc *UserController) UpdateMeHandler(c *gin.Context) {
 }
 
-func DeleteMeHandler(c *gin.Context) {
+func (uc *UserController) DeleteMeHandler(c *gin.Context) {
 }
@@ -0,0 +1,48 @@
+package controllers_test
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestUserAdminHandlers(t *testing.T) {
+	a := newTestApp(t)
+	admin := a.createUser("admin", "admin@example.com")
+	token := a.token(admin)
+
+	create := map[string]string{"name": "Guide", "email": "guide@example.com", "role": "guide", "password": testPassword, "passwordConfirm": testPassword}
+	response := expect(t, a.do(http.MethodPost, "/api/v1/users/", token, create), http.StatusOK)
+	var created struct {
+		Id   string `json:"id"`
+		Role string `json:"role"`
+	}
+	response.decode(t, &created)
+	if created.Id == "" || created.Role != "guide" {
+		t.Fatalf("unexpected created user: %+v", created)
+	}
+	expectError(t, a.do(http.MethodPost, "/api/v1/users/", token, create), http.StatusConflict, "conflict")
+
+	response = expect(t, a.do(http.MethodGet, "/api/v1/users/", token, nil), http.StatusOK)
+	var users []struct {
+		Id string `json:"id"`
+	}
+	response.decode(t, &users)
+	if len(users) != 2 {
+		t.Errorf("listed %d users, want 2", len(users))
+	}
+
+	expect(t, a.do(http.MethodGet, "/api/v1/users/"+created.Id, token, nil), http.StatusOK)
+	expectError(t, a.do(http.MethodGet, "/api/v1/users/missing", token, nil), http.StatusNotFound, "not_found")
+
+	expect(t, a.do(http.MethodDelete, "/api/v1/users/"+created.Id, token, nil), http.StatusOK)
+	expectError(t, a.do(http.MethodGet, "/api/v1/users/"+created.Id, token, nil), http.StatusNotFound, "not_found")
+}
+
+func TestUserHandlersRequireAdmin(t *testing.T) {
+	a := newTestApp(t)
+	user := a.createUser("user", "user@example.com")
+	token := a.token(user)
+
+	expectError(t, a.do(http.MethodGet, "/api/v1/users/"+user.Id, token, nil), http.StatusForbidden, "forbidden")
+	expectError(t, a.do(http.MethodDelete, "/api/v1/users/", token, nil), http.StatusForbidden, "forbidden")
+}
@@ -0,0 +1,209 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamid-nazari/tours-in-go/internal/apperrors"
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"github.com/hamid-nazari/tours-in-go/internal/services"
+)
+
+type createWebhookRequest struct {
+	URL    string   `json:"url" binding:"required"`
+	Events []string `json:"events" binding:"required"`
+	Secret string   `json:"secret"`
+}
+
+type createdWebhook struct {
+	*models.WebhookSubscription
+	Secret string `json:"secret"`
+}
+
+type WebhookController struct {
+	webhooks *services.WebhookService
+	audit    *services.AuditService
+}
+
+func NewWebhookController(webhooks *services.WebhookService, audit *services.AuditService) *WebhookController {
+	return &WebhookController{
+		webhooks: webhooks,
+		audit:    audit,
+	}
+}
+
+func (wc *WebhookController) CreateWebhookHandler(c *gin.Context) {
+	var request createWebhookRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		apperrors.Abort(c, apperrors.Validation(err))
+		return
+	}
+
+	currentUser, _ := c.Get("user")
+
+	subscription := models.NewWebhookSubscription()
+	subscription.URL = request.URL
+	subscription.Events = request.Events
+	subscription.Secret = request.Secret
+	subscription.CreatedBy = currentUser.(*models.User).Id
+
+	if err := wc.webhooks.CreateSubscription(c, subscription); err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+	wc.audit.Record(c, services.AuditRecord{Action: models.AuditWebhookCreate, TargetType: "webhook", TargetId: subscription.Id, After: subscription})
+
+	c.JSON(http.StatusOK, models.CustomResponse{
+		Status:  "Success",
+		Message: "Webhook created successfully, store the secret now as it will not be shown again",
+		Data:    createdWebhook{WebhookSubscription: subscription, Secret: subscription.Secret},
+	})
+}
+
+func (wc *WebhookController) GetAllWebhooksHandler(c *gin.Context) {
+	subscriptions, err := wc.webhooks.GetSubscriptions(c)
+	if err != nil {
+		apperrors.Abort(c, apperrors.Internal(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CustomResponse{
+		Status:  "Success",
+		Message: fmt.Sprint(len(subscriptions)) + " webhooks found",
+		Data:    subscriptions,
+	})
+}
+
+func (wc *WebhookController) GetWebhookHandler(c *gin.Context) {
+	subscription := wc.webhooks.FindSubscriptionById(c, c.Param("id"))
+	if subscription == nil {
+		apperrors.Abort(c, apperrors.NotFound("Webhook not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CustomResponse{
+		Status:  "Success",
+		Message: "Webhook retrieved successfully",
+		Data:    subscription,
+	})
+}
+
+func (wc *WebhookController) UpdateWebhookHandler(c *gin.Context) {
+	subscription := wc.webhooks.FindSubscriptionById(c, c.Param("id"))
+	if subscription == nil {
+		apperrors.Abort(c, apperrors.NotFound("Webhook not found"))
+		return
+	}
+
+	id, createdBy, createdAt := subscription.Id, subscription.CreatedBy, subscription.CreatedAt
+	before := services.AuditSnapshot(subscription)
+
+	if err := c.ShouldBindJSON(&subscription); err != nil {
+		apperrors.Abort(c, apperrors.Validation(err))
+		return
+	}
+
+	subscription.Id, subscription.CreatedBy, subscription.CreatedAt = id, createdBy, createdAt
+
+	if err := wc.webhooks.UpdateSubscription(c, subscription); err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+	wc.audit.Record(c, services.AuditRecord{Action: models.AuditWebhookUpdate, TargetType: "webhook", TargetId: subscription.Id, Before: before, After: subscription})
+
+	c.JSON(http.StatusOK, models.CustomResponse{
+		Status:  "Success",
+		Message: "Webhook updated successfully",
+		Data:    subscription,
+	})
+}
+
+func (wc *WebhookController) DeleteWebhookHandler(c *gin.Context) {
+	subscription := wc.webhooks.FindSubscriptionById(c, c.Param("id"))
+	if subscription == nil {
+		apperrors.Abort(c, apperrors.NotFound("Webhook not found"))
+		return
+	}
+
+	if err := wc.webhooks.DeleteSubscription(c, subscription); err != nil {
+		apperrors.Abort(c, apperrors.Internal(err))
+		return
+	}
+	wc.audit.Record(c, services.AuditRecord{Action: models.AuditWebhookDelete, TargetType: "webhook", TargetId: subscription.Id, Before: subscription})
+
+	c.JSON(http.StatusOK, models.CustomResponse{
+		Status:  "Success",
+		Message: "Webhook deleted successfully",
+		Data:    nil,
+	})
+}
+
+func (wc *WebhookController) GetWebhookDeliveriesHandler(c *gin.Context) {
+	subscription := wc.webhooks.FindSubscriptionById(c, c.Param("id"))
+	if subscription == nil {
+		apperrors.Abort(c, apperrors.NotFound("Webhook not found"))
+		return
+	}
+
+	status := c.Query("status")
+	switch status {
+	case "", models.WebhookDeliveryPending, models.WebhookDeliverySucceeded, models.WebhookDeliveryFailed:
+	default:
+		apperrors.Abort(c, apperrors.InvalidField("status", "oneof", fmt.Sprintf("must be one of: %s, %s, %s", models.WebhookDeliveryPending, models.WebhookDeliverySucceeded, models.WebhookDeliveryFailed)))
+		return
+	}
+
+	deliveries, err := wc.webhooks.GetDeliveries(c, subscription.Id, status)
+	if err != nil {
+		apperrors.Abort(c, apperrors.Internal(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CustomResponse{
+		Status:  "Success",
+		Message: fmt.Sprint(len(deliveries)) + " deliveries found",
+		Data:    deliveries,
+	})
+}
+
+func (wc *WebhookController) PingWebhookHandler(c *gin.Context) {
+	subscription := wc.webhooks.FindSubscriptionById(c, c.Param("id"))
+	if subscription == nil {
+		apperrors.Abort(c, apperrors.NotFound("Webhook not found"))
+		return
+	}
+
+	delivery, err := wc.webhooks.Ping(c, subscription)
+	if err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CustomResponse{
+		Status:  "Success",
+		Message: "Ping queued for delivery",
+		Data:    delivery,
+	})
+}
+
+func (wc *WebhookController) ReplayDeliveryHandler(c *gin.Context) {
+	original := wc.webhooks.FindDeliveryById(c, c.Param("deliveryId"))
+	if original == nil {
+		apperrors.Abort(c, apperrors.NotFound("Delivery not found"))
+		return
+	}
+
+	delivery, err := wc.webhooks.ReplayDelivery(c, original)
+	if err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CustomResponse{
+		Status:  "Success",
+		Message: "Delivery queued for replay",
+		Data:    delivery,
+	})
+}
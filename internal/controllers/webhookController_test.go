@@ -0,0 +1,69 @@
+package controllers_test
+
+import (
+	"net/http"
+	"testing"
+)
+
+type webhookData struct {
+	Id     string   `json:"id"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Active bool     `json:"active"`
+	Secret string   `json:"secret"`
+}
+
+func TestWebhookHandlers(t *testing.T) {
+	a := newTestApp(t)
+	admin := a.createUser("admin", "admin@example.com")
+	token := a.token(admin)
+
+	expectError(t, a.do(http.MethodPost, "/api/v1/webhooks/", token, map[string]any{"url": "not a url", "events": []string{"tour.created"}}), http.StatusBadRequest, "validation_failed")
+
+	var created webhookData
+	body := map[string]any{"url": "https://partner.example.com/hooks", "events": []string{"booking.created", "tour.updated"}}
+	expect(t, a.do(http.MethodPost, "/api/v1/webhooks/", token, body), http.StatusOK).decode(t, &created)
+	if created.Id == "" || created.Secret == "" || !created.Active {
+		t.Fatalf("unexpected created webhook: %+v", created)
+	}
+
+	var fetched webhookData
+	expect(t, a.do(http.MethodGet, "/api/v1/webhooks/"+created.Id, token, nil), http.StatusOK).decode(t, &fetched)
+	if fetched.Secret != "" {
+		t.Error("the secret was returned after creation")
+	}
+
+	var updated webhookData
+	expect(t, a.do(http.MethodPatch, "/api/v1/webhooks/"+created.Id, token, map[string]any{"events": []string{"tour.created"}}), http.StatusOK).decode(t, &updated)
+	if len(updated.Events) != 1 || updated.Events[0] != "tour.created" || updated.URL != body["url"] {
+		t.Errorf("unexpected updated webhook: %+v", updated)
+	}
+
+	var delivery struct {
+		Id     string `json:"id"`
+		Event  string `json:"event"`
+		Status string `json:"status"`
+	}
+	expect(t, a.do(http.MethodPost, "/api/v1/webhooks/"+created.Id+"/ping", token, nil), http.StatusOK).decode(t, &delivery)
+	if delivery.Event != "webhook.ping" || delivery.Status != "pending" {
+		t.Errorf("unexpected ping delivery: %+v", delivery)
+	}
+
+	var deliveries []struct {
+		Id string `json:"id"`
+	}
+	expect(t, a.do(http.MethodGet, "/api/v1/webhooks/"+created.Id+"/deliveries", token, nil), http.StatusOK).decode(t, &deliveries)
+	if len(deliveries) != 1 || deliveries[0].Id != delivery.Id {
+		t.Errorf("deliveries = %+v, want the ping", deliveries)
+	}
+	expectError(t, a.do(http.MethodGet, "/api/v1/webhooks/"+created.Id+"/deliveries?status=bogus", token, nil), http.StatusBadRequest, "validation_failed")
+
+	expect(t, a.do(http.MethodPost, "/api/v1/webhooks/deliveries/"+delivery.Id+"/replay", token, nil), http.StatusOK)
+	expectError(t, a.do(http.MethodPost, "/api/v1/webhooks/deliveries/missing/replay", token, nil), http.StatusNotFound, "not_found")
+
+	expect(t, a.do(http.MethodDelete, "/api/v1/webhooks/"+created.Id, token, nil), http.StatusOK)
+	expectError(t, a.do(http.MethodGet, "/api/v1/webhooks/"+created.Id, token, nil), http.StatusNotFound, "not_found")
+
+	user := a.createUser("user", "user@example.com")
+	expectError(t, a.do(http.MethodGet, "/api/v1/webhooks/", a.token(user), nil), http.StatusForbidden, "forbidden")
+}
@@ -0,0 +1,44 @@
+package events
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+type Handler func(ctx context.Context, event Event) error
+
+type Subscription struct {
+	Name    string
+	Types   []string
+	Handler Handler
+}
+
+type Bus struct {
+	mutex         sync.RWMutex
+	subscriptions []Subscription
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+func (b *Bus) Subscribe(name string, handler Handler, types ...string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.subscriptions = append(b.subscriptions, Subscription{Name: name, Types: types, Handler: handler})
+}
+
+func (b *Bus) Subscriptions(eventType string) []Subscription {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	var matching []Subscription
+	for _, subscription := range b.subscriptions {
+		if len(subscription.Types) == 0 || slices.Contains(subscription.Types, eventType) {
+			matching = append(matching, subscription)
+		}
+	}
+	return matching
+}
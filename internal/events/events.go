@@ -0,0 +1,113 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+)
+
+const (
+	UserSignedUp     = "user.signed_up"
+	BookingCreated   = "booking.created"
+	BookingUpdated   = "booking.updated"
+	BookingPaid      = "booking.paid"
+	BookingCancelled = "booking.cancelled"
+	ReviewCreated    = "review.created"
+	ReviewUpdated    = "review.updated"
+	ReviewDeleted    = "review.deleted"
+	TourCreated      = "tour.created"
+	TourUpdated      = "tour.updated"
+	TourDeleted      = "tour.deleted"
+)
+
+type Event struct {
+	Id          string          `json:"id"`
+	Type        string          `json:"type"`
+	AggregateId string          `json:"aggregateId"`
+	Data        json.RawMessage `json:"data"`
+	OccurredAt  time.Time       `json:"occurredAt"`
+}
+
+func (e Event) Decode(target any) error {
+	if err := json.Unmarshal(e.Data, target); err != nil {
+		return fmt.Errorf("failed to decode %s event: %v", e.Type, err)
+	}
+	return nil
+}
+
+type UserData struct {
+	Id    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type TourRef struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type UserRef struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type BookingData struct {
+	Id        string    `json:"id"`
+	Tour      TourRef   `json:"tour"`
+	User      UserRef   `json:"user"`
+	Price     float64   `json:"price"`
+	Paid      bool      `json:"paid"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type ReviewData struct {
+	Id             string    `json:"id"`
+	TourId         string    `json:"tourId"`
+	UserId         string    `json:"userId"`
+	UserName       string    `json:"userName"`
+	UserPhoto      string    `json:"userPhoto"`
+	Review         string    `json:"review"`
+	Rating         int       `json:"rating"`
+	Status         string    `json:"status"`
+	PreviousStatus string    `json:"previousStatus,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+type TourData struct {
+	Tour                models.Tour `json:"tour"`
+	PreviousPrice       float64     `json:"previousPrice,omitempty"`
+	AvailabilityChanged bool        `json:"availabilityChanged,omitempty"`
+}
+
+func NewUserData(user *models.User) UserData {
+	return UserData{Id: user.Id, Name: user.Name, Email: user.Email}
+}
+
+func NewBookingData(booking *models.Booking) BookingData {
+	return BookingData{
+		Id:        booking.Id,
+		Tour:      TourRef{Id: booking.Tour.Id, Name: booking.Tour.Name, Slug: booking.Tour.Slug},
+		User:      UserRef{Id: booking.User.Id, Name: booking.User.Name},
+		Price:     booking.Price,
+		Paid:      booking.Paid,
+		CreatedAt: booking.CreatedAt,
+	}
+}
+
+func NewReviewData(review *models.Review, previousStatus string) ReviewData {
+	return ReviewData{
+		Id:             review.Id,
+		TourId:         review.Tour.Id,
+		UserId:         review.User.Id,
+		UserName:       review.User.Name,
+		UserPhoto:      review.User.Photo,
+		Review:         review.Review,
+		Rating:         review.Rating,
+		Status:         review.Status,
+		PreviousStatus: previousStatus,
+		CreatedAt:      review.CreatedAt,
+	}
+}
@@ -0,0 +1,189 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"github.com/hamid-nazari/tours-in-go/internal/repositories"
+	"github.com/hamid-nazari/tours-in-go/internal/utils"
+)
+
+const outboxLease = time.Minute
+
+type Policy struct {
+	PollInterval   time.Duration
+	BatchSize      int
+	MaxAttempts    int
+	RetryBaseDelay time.Duration
+	MaxRetryDelay  time.Duration
+}
+
+type Tx struct {
+	messages []models.OutboxMessage
+}
+
+func (tx *Tx) Record(eventType string, aggregateId string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %v", eventType, err)
+	}
+
+	tx.messages = append(tx.messages, models.OutboxMessage{
+		Id:          uuid.New().String(),
+		Type:        eventType,
+		AggregateId: aggregateId,
+		Data:        payload,
+		OccurredAt:  time.Now(),
+		Status:      models.OutboxPending,
+		Completed:   []string{},
+	})
+	return nil
+}
+
+type Outbox struct {
+	messages   repositories.OutboxRepository
+	transactor repositories.Transactor
+	bus        *Bus
+	policy     Policy
+	logger     *slog.Logger
+	wake       chan struct{}
+}
+
+func NewOutbox(messages repositories.OutboxRepository, transactor repositories.Transactor, bus *Bus, policy Policy, logger *slog.Logger) *Outbox {
+	return &Outbox{
+		messages:   messages,
+		transactor: transactor,
+		bus:        bus,
+		policy:     policy,
+		logger:     logger,
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+func (o *Outbox) Transaction(ctx context.Context, fn func(ctx context.Context, tx *Tx) error) error {
+	var recorded int
+
+	err := o.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		tx := &Tx{}
+		if err := fn(ctx, tx); err != nil {
+			return err
+		}
+
+		recorded = len(tx.messages)
+		return o.messages.Append(ctx, tx.messages)
+	})
+	if err != nil {
+		return err
+	}
+
+	if recorded > 0 {
+		o.notify()
+	}
+	return nil
+}
+
+func (o *Outbox) notify() {
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (o *Outbox) Name() string {
+	return "outbox"
+}
+
+func (o *Outbox) Run(ctx context.Context) error {
+	ticker := time.NewTicker(o.policy.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		o.dispatch(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		case <-o.wake:
+		}
+	}
+}
+
+func (o *Outbox) dispatch(ctx context.Context) {
+	for ctx.Err() == nil {
+		messages, err := o.messages.ClaimDue(ctx, time.Now(), outboxLease, o.policy.BatchSize)
+		if err != nil {
+			o.logger.ErrorContext(ctx, "failed to claim outbox messages", "error", err)
+			return
+		}
+
+		for i := range messages {
+			o.deliver(ctx, &messages[i])
+		}
+
+		if len(messages) < o.policy.BatchSize {
+			return
+		}
+	}
+}
+
+func (o *Outbox) deliver(ctx context.Context, message *models.OutboxMessage) {
+	event := Event{
+		Id:          message.Id,
+		Type:        message.Type,
+		AggregateId: message.AggregateId,
+		Data:        message.Data,
+		OccurredAt:  message.OccurredAt,
+	}
+
+	var failures []string
+	for _, subscription := range o.bus.Subscriptions(message.Type) {
+		if slices.Contains(message.Completed, subscription.Name) {
+			continue
+		}
+
+		if err := handle(ctx, subscription, event); err != nil {
+			o.logger.WarnContext(ctx, "event handler failed", "handler", subscription.Name, "type", event.Type, "eventId", event.Id, "error", err)
+			failures = append(failures, fmt.Sprintf("%s: %v", subscription.Name, err))
+			continue
+		}
+		message.Completed = append(message.Completed, subscription.Name)
+	}
+
+	now := time.Now()
+	message.Attempts++
+	message.LastError = strings.Join(failures, "; ")
+
+	switch {
+	case len(failures) == 0:
+		message.Status = models.OutboxDelivered
+		message.DeliveredAt = now
+	case message.Attempts >= o.policy.MaxAttempts:
+		message.Status = models.OutboxFailed
+		o.logger.ErrorContext(ctx, "giving up on event", "type", event.Type, "eventId", event.Id, "attempts", message.Attempts, "error", message.LastError)
+	default:
+		message.NextAttemptAt = now.Add(utils.RetryDelay(message.Attempts, o.policy.RetryBaseDelay, o.policy.MaxRetryDelay))
+	}
+
+	if err := o.messages.Update(context.WithoutCancel(ctx), message); err != nil {
+		o.logger.ErrorContext(ctx, "failed to record outbox message", "eventId", message.Id, "error", err)
+	}
+}
+
+func handle(ctx context.Context, subscription Subscription, event Event) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = errors.New(fmt.Sprint("panic: ", recovered))
+		}
+	}()
+	return subscription.Handler(ctx, event)
+}
@@ -0,0 +1,143 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hamid-nazari/tours-in-go/internal/repositories"
+)
+
+type recordingHandler struct {
+	mutex    sync.Mutex
+	events   []string
+	failures int
+}
+
+func (h *recordingHandler) handle(ctx context.Context, event Event) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.events = append(h.events, event.AggregateId)
+	if h.failures > 0 {
+		h.failures--
+		return errors.New("handler unavailable")
+	}
+	return nil
+}
+
+func (h *recordingHandler) received() []string {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return append([]string(nil), h.events...)
+}
+
+func newTestOutbox() (*Outbox, *repositories.MemoryOutboxRepository, *Bus) {
+	messages := repositories.NewMemoryOutboxRepository()
+	bus := NewBus()
+	outbox := NewOutbox(messages, repositories.MemoryTransactor{}, bus, Policy{
+		PollInterval:   time.Hour,
+		BatchSize:      10,
+		MaxAttempts:    5,
+		RetryBaseDelay: time.Millisecond,
+		MaxRetryDelay:  time.Millisecond,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	return outbox, messages, bus
+}
+
+func record(t *testing.T, outbox *Outbox, aggregateId string) {
+	t.Helper()
+
+	err := outbox.Transaction(context.Background(), func(ctx context.Context, tx *Tx) error {
+		return tx.Record(TourCreated, aggregateId, map[string]string{"id": aggregateId})
+	})
+	if err != nil {
+		t.Fatalf("failed to record event: %v", err)
+	}
+}
+
+func expectReceived(t *testing.T, handler *recordingHandler, expected ...string) {
+	t.Helper()
+
+	received := handler.received()
+	if len(received) != len(expected) {
+		t.Fatalf("expected events %v, got %v", expected, received)
+	}
+	for i := range expected {
+		if received[i] != expected[i] {
+			t.Fatalf("expected events %v, got %v", expected, received)
+		}
+	}
+}
+
+func TestOutboxRollbackDropsEvents(t *testing.T) {
+	outbox, _, bus := newTestOutbox()
+	handler := &recordingHandler{}
+	bus.Subscribe("test", handler.handle)
+	ctx := context.Background()
+
+	failure := errors.New("write failed")
+	err := outbox.Transaction(ctx, func(ctx context.Context, tx *Tx) error {
+		if err := tx.Record(TourCreated, "rolled-back", nil); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("expected the transaction error, got %v", err)
+	}
+	record(t, outbox, "committed")
+
+	outbox.dispatch(ctx)
+	expectReceived(t, handler, "committed")
+}
+
+func TestOutboxReclaimsExpiredLeases(t *testing.T) {
+	outbox, messages, bus := newTestOutbox()
+	handler := &recordingHandler{}
+	bus.Subscribe("test", handler.handle)
+	ctx := context.Background()
+
+	record(t, outbox, "in-flight")
+	if claimed, err := messages.ClaimDue(ctx, time.Now(), outboxLease, 10); err != nil || len(claimed) != 1 {
+		t.Fatalf("failed to claim message: %v (%d claimed)", err, len(claimed))
+	}
+	record(t, outbox, "crashed")
+	if claimed, err := messages.ClaimDue(ctx, time.Now().Add(-2*outboxLease), outboxLease, 10); err != nil || len(claimed) != 1 {
+		t.Fatalf("failed to claim message: %v (%d claimed)", err, len(claimed))
+	}
+
+	outbox.dispatch(ctx)
+	expectReceived(t, handler, "crashed")
+}
+
+func TestOutboxRedeliversAfterHandlerError(t *testing.T) {
+	outbox, messages, bus := newTestOutbox()
+	flaky := &recordingHandler{failures: 1}
+	steady := &recordingHandler{}
+	bus.Subscribe("flaky", flaky.handle)
+	bus.Subscribe("steady", steady.handle)
+	ctx := context.Background()
+
+	record(t, outbox, "tour")
+
+	outbox.dispatch(ctx)
+	expectReceived(t, flaky, "tour")
+	expectReceived(t, steady, "tour")
+
+	time.Sleep(5 * time.Millisecond)
+	outbox.dispatch(ctx)
+	expectReceived(t, flaky, "tour", "tour")
+	expectReceived(t, steady, "tour")
+
+	time.Sleep(5 * time.Millisecond)
+	outbox.dispatch(ctx)
+	expectReceived(t, flaky, "tour", "tour")
+	if pending, err := messages.ClaimDue(ctx, time.Now(), outboxLease, 10); err != nil || len(pending) != 0 {
+		t.Errorf("expected the event to be delivered, got %d pending (%v)", len(pending), err)
+	}
+}
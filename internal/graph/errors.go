@@ -0,0 +1,31 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/hamid-nazari/tours-in-go/internal/apperrors"
+	"github.com/hamid-nazari/tours-in-go/internal/logging"
+)
+
+const CodeQueryTooComplex = "query_too_complex"
+
+type graphError struct {
+	code    string
+	message string
+}
+
+func (e *graphError) Error() string {
+	return e.message
+}
+
+func (e *graphError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.code}
+}
+
+func resolverError(ctx context.Context, err error) error {
+	appErr := apperrors.From(err)
+	if appErr.Code == apperrors.CodeInternal {
+		logging.Package(logging.FromContext(ctx), "graphql").ErrorContext(ctx, "resolver failed", "error", appErr.Err)
+	}
+	return &graphError{code: appErr.Code, message: appErr.Message}
+}
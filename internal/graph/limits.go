@@ -0,0 +1,184 @@
+package graph
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+const unboundedListSize = 5
+
+type Limits struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+type queryCost struct {
+	depth      int
+	complexity int
+}
+
+type costAnalyzer struct {
+	schema    *graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+	visiting  map[string]bool
+}
+
+func (l Limits) check(schema *graphql.Schema, document *ast.Document, operationName string, variables map[string]interface{}) *graphError {
+	analyzer := &costAnalyzer{
+		schema:    schema,
+		fragments: map[string]*ast.FragmentDefinition{},
+		variables: variables,
+		visiting:  map[string]bool{},
+	}
+
+	var operation *ast.OperationDefinition
+	for _, definition := range document.Definitions {
+		switch definition := definition.(type) {
+		case *ast.FragmentDefinition:
+			analyzer.fragments[definition.Name.Value] = definition
+		case *ast.OperationDefinition:
+			if operation == nil && (operationName == "" || definition.Name != nil && definition.Name.Value == operationName) {
+				operation = definition
+			}
+		}
+	}
+	if operation == nil {
+		return nil
+	}
+
+	root := schema.QueryType()
+	if operation.Operation == ast.OperationTypeMutation {
+		root = schema.MutationType()
+	}
+	if root == nil {
+		return nil
+	}
+
+	cost := analyzer.selectionSet(operation.SelectionSet, root)
+	if l.MaxDepth > 0 && cost.depth > l.MaxDepth {
+		return &graphError{code: CodeQueryTooComplex, message: fmt.Sprintf("Query depth %d exceeds the maximum of %d", cost.depth, l.MaxDepth)}
+	}
+	if l.MaxComplexity > 0 && cost.complexity > l.MaxComplexity {
+		return &graphError{code: CodeQueryTooComplex, message: fmt.Sprintf("Query complexity %d exceeds the maximum of %d", cost.complexity, l.MaxComplexity)}
+	}
+	return nil
+}
+
+func (a *costAnalyzer) selectionSet(set *ast.SelectionSet, parent *graphql.Object) queryCost {
+	var total queryCost
+	if set == nil || parent == nil {
+		return total
+	}
+
+	add := func(cost queryCost) {
+		total.depth = max(total.depth, cost.depth)
+		total.complexity += cost.complexity
+	}
+
+	for _, selection := range set.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			add(a.field(selection, parent))
+		case *ast.InlineFragment:
+			add(a.selectionSet(selection.SelectionSet, a.typeCondition(selection.TypeCondition, parent)))
+		case *ast.FragmentSpread:
+			name := selection.Name.Value
+			fragment, ok := a.fragments[name]
+			if !ok || a.visiting[name] {
+				continue
+			}
+			a.visiting[name] = true
+			add(a.selectionSet(fragment.SelectionSet, a.typeCondition(fragment.TypeCondition, parent)))
+			delete(a.visiting, name)
+		}
+	}
+
+	return total
+}
+
+func (a *costAnalyzer) field(field *ast.Field, parent *graphql.Object) queryCost {
+	name := field.Name.Value
+	if strings.HasPrefix(name, "__") {
+		return queryCost{}
+	}
+
+	definition, ok := parent.Fields()[name]
+	if !ok {
+		return queryCost{depth: 1, complexity: 1}
+	}
+
+	fieldType := definition.Type
+	multiplier := 1
+	for {
+		if nonNull, ok := fieldType.(*graphql.NonNull); ok {
+			fieldType = nonNull.OfType
+			continue
+		}
+		if list, ok := fieldType.(*graphql.List); ok {
+			multiplier = a.listSize(field, definition)
+			fieldType = list.OfType
+			continue
+		}
+		break
+	}
+
+	object, _ := fieldType.(*graphql.Object)
+	children := a.selectionSet(field.SelectionSet, object)
+
+	return queryCost{
+		depth:      children.depth + 1,
+		complexity: 1 + multiplier*children.complexity,
+	}
+}
+
+func (a *costAnalyzer) listSize(field *ast.Field, definition *graphql.FieldDefinition) int {
+	for _, argument := range field.Arguments {
+		if argument.Name.Value != "limit" {
+			continue
+		}
+		switch value := argument.Value.(type) {
+		case *ast.IntValue:
+			if limit, err := strconv.Atoi(value.Value); err == nil {
+				return min(max(limit, 0), maxListLimit)
+			}
+		case *ast.Variable:
+			if limit, ok := numeric(a.variables[value.Name.Value]); ok {
+				return min(max(limit, 0), maxListLimit)
+			}
+		}
+	}
+
+	for _, argument := range definition.Args {
+		if argument.Name() == "limit" {
+			if limit, ok := argument.DefaultValue.(int); ok {
+				return limit
+			}
+		}
+	}
+	return unboundedListSize
+}
+
+func (a *costAnalyzer) typeCondition(condition *ast.Named, parent *graphql.Object) *graphql.Object {
+	if condition == nil {
+		return parent
+	}
+	if object, ok := a.schema.Type(condition.Name.Value).(*graphql.Object); ok {
+		return object
+	}
+	return parent
+}
+
+func numeric(value interface{}) (int, bool) {
+	switch value := value.(type) {
+	case int:
+		return value, true
+	case float64:
+		return int(value), true
+	}
+	return 0, false
+}
@@ -0,0 +1,153 @@
+package graph
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+)
+
+type batchFunc[V any] func(ctx context.Context, keys []string) (map[string]V, error)
+
+type loaderResult[V any] struct {
+	value V
+	err   error
+}
+
+type loader[V any] struct {
+	fetch   batchFunc[V]
+	mutex   sync.Mutex
+	pending []string
+	results map[string]loaderResult[V]
+}
+
+func newLoader[V any](fetch batchFunc[V]) *loader[V] {
+	return &loader[V]{
+		fetch:   fetch,
+		results: map[string]loaderResult[V]{},
+	}
+}
+
+func (l *loader[V]) load(ctx context.Context, key string) func() (V, error) {
+	l.mutex.Lock()
+	if _, ok := l.results[key]; !ok && !slices.Contains(l.pending, key) {
+		l.pending = append(l.pending, key)
+	}
+	l.mutex.Unlock()
+
+	return func() (V, error) {
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+
+		if len(l.pending) > 0 {
+			keys := l.pending
+			l.pending = nil
+
+			values, err := l.fetch(ctx, keys)
+			for _, k := range keys {
+				l.results[k] = loaderResult[V]{value: values[k], err: err}
+			}
+		}
+
+		result := l.results[key]
+		return result.value, result.err
+	}
+}
+
+func thunk[V any](ctx context.Context, load func() (V, error)) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		value, err := load()
+		if err != nil {
+			return nil, resolverError(ctx, err)
+		}
+		return value, nil
+	}
+}
+
+type loaders struct {
+	services     Services
+	tours        *loader[*models.Tour]
+	users        *loader[*models.User]
+	tourReviews  *loader[[]models.Review]
+	tourBookings *loader[[]models.Booking]
+
+	mutex      sync.Mutex
+	guideTours map[[2]int]*loader[[]models.Tour]
+}
+
+func newLoaders(services Services) *loaders {
+	return &loaders{
+		services:   services,
+		guideTours: map[[2]int]*loader[[]models.Tour]{},
+		tours: newLoader(func(ctx context.Context, ids []string) (map[string]*models.Tour, error) {
+			tours, err := services.Tours.FindToursByIds(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			byId := make(map[string]*models.Tour, len(tours))
+			for i := range tours {
+				byId[tours[i].Id] = &tours[i]
+			}
+			return byId, nil
+		}),
+		users: newLoader(func(ctx context.Context, ids []string) (map[string]*models.User, error) {
+			users, err := services.Users.FindUsersByIds(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			byId := make(map[string]*models.User, len(users))
+			for i := range users {
+				byId[users[i].Id] = &users[i]
+			}
+			return byId, nil
+		}),
+		tourReviews: newLoader(func(ctx context.Context, tourIds []string) (map[string][]models.Review, error) {
+			reviews, err := services.Reviews.GetReviewsForTours(ctx, tourIds, "")
+			if err != nil {
+				return nil, err
+			}
+			byTour := map[string][]models.Review{}
+			for _, review := range reviews {
+				byTour[review.Tour.Id] = append(byTour[review.Tour.Id], review)
+			}
+			return byTour, nil
+		}),
+		tourBookings: newLoader(func(ctx context.Context, tourIds []string) (map[string][]models.Booking, error) {
+			bookings, err := services.Bookings.GetBookingsForTours(ctx, tourIds)
+			if err != nil {
+				return nil, err
+			}
+			byTour := map[string][]models.Booking{}
+			for _, booking := range bookings {
+				byTour[booking.Tour.Id] = append(byTour[booking.Tour.Id], booking)
+			}
+			return byTour, nil
+		}),
+	}
+}
+
+func (l *loaders) guideTourPage(limit int, offset int) *loader[[]models.Tour] {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	key := [2]int{limit, offset}
+	if page, ok := l.guideTours[key]; ok {
+		return page
+	}
+	page := newLoader(func(ctx context.Context, guideIds []string) (map[string][]models.Tour, error) {
+		return l.services.Tours.GetToursByGuides(ctx, guideIds, limit, offset)
+	})
+	l.guideTours[key] = page
+	return page
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, services Services) context.Context {
+	return context.WithValue(ctx, loadersKey{}, newLoaders(services))
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
@@ -0,0 +1,360 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/hamid-nazari/tours-in-go/internal/apperrors"
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"github.com/hamid-nazari/tours-in-go/internal/services"
+)
+
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+)
+
+type Services struct {
+	Users    *services.UserService
+	Tours    *services.TourService
+	Reviews  *services.ReviewService
+	Bookings *services.BookingService
+}
+
+func newSchema(deps Services) (graphql.Schema, error) {
+	var tourType, userType, reviewType, bookingType *graphql.Object
+
+	listArgs := func(defaultLimit int) graphql.FieldConfigArgument {
+		return graphql.FieldConfigArgument{
+			"limit":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultLimit},
+			"offset": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+		}
+	}
+
+	availabilityType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Availability",
+		Fields: graphql.Fields{
+			"startDates":   &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.DateTime)))},
+			"maxGroupSize": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"booked":       &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"remaining":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+
+	replyType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "ReviewReply",
+		Fields: graphql.Fields{
+			"guideId":   &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"guideName": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"reply":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"createdAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"updatedAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+		},
+	})
+
+	tourType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Tour",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":             &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+				"name":           &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"slug":           &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"duration":       &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"difficulty":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"price":          &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+				"maxGroupSize":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"ratingAvg":      &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+				"ratingQuantity": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"imageCover":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"images":         &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+				"startDates":     &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.DateTime)))},
+				"secretTour":     &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+				"summary":        &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"description":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"startLocation":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"locations":      &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+				"createdAt":      &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+				"guides": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(userType))),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						tour := p.Source.(*models.Tour)
+						guides := make([]*models.User, len(tour.GuideProfiles))
+						for i, profile := range tour.GuideProfiles {
+							guides[i] = &models.User{Id: profile.Id, Name: profile.Name, Photo: profile.Photo, Role: profile.Role}
+						}
+						return guides, nil
+					},
+				},
+				"reviews": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(reviewType))),
+					Args: listArgs(10),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						tour := p.Source.(*models.Tour)
+						load := loadersFrom(p.Context).tourReviews.load(p.Context, tour.Id)
+						return thunk(p.Context, func() ([]*models.Review, error) {
+							reviews, err := load()
+							return page(pointers(reviews), p.Args), err
+						}), nil
+					},
+				},
+				"availability": &graphql.Field{
+					Type: graphql.NewNonNull(availabilityType),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						tour := p.Source.(*models.Tour)
+						load := loadersFrom(p.Context).tourBookings.load(p.Context, tour.Id)
+						return thunk(p.Context, func() (*services.Availability, error) {
+							bookings, err := load()
+							if err != nil {
+								return nil, err
+							}
+							return services.ComputeAvailability(tour, bookings), nil
+						}), nil
+					},
+				},
+				"bookings": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(bookingType))),
+					Args: listArgs(defaultListLimit),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						if err := restrictTo(p.Context, "admin", "lead-guide"); err != nil {
+							return nil, resolverError(p.Context, err)
+						}
+						tour := p.Source.(*models.Tour)
+						load := loadersFrom(p.Context).tourBookings.load(p.Context, tour.Id)
+						return thunk(p.Context, func() ([]*models.Booking, error) {
+							bookings, err := load()
+							return page(pointers(bookings), p.Args), err
+						}), nil
+					},
+				},
+			}
+		}),
+	})
+
+	userType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":    &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+				"name":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"photo": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"role":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"tours": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(tourType))),
+					Args: listArgs(10),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						user := p.Source.(*models.User)
+						limit, offset := pageArgs(p.Args)
+						if limit == 0 {
+							return []*models.Tour{}, nil
+						}
+						load := loadersFrom(p.Context).guideTourPage(limit, offset).load(p.Context, user.Id)
+						return thunk(p.Context, func() ([]*models.Tour, error) {
+							tours, err := load()
+							return pointers(tours), err
+						}), nil
+					},
+				},
+			}
+		}),
+	})
+
+	reviewType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Review",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":             &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+				"review":         &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"rating":         &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"helpfulCount":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"unhelpfulCount": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"createdAt":      &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+				"reply":          &graphql.Field{Type: replyType},
+				"tour": &graphql.Field{
+					Type: tourType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						review := p.Source.(*models.Review)
+						return thunk(p.Context, loadersFrom(p.Context).tours.load(p.Context, review.Tour.Id)), nil
+					},
+				},
+				"user": &graphql.Field{
+					Type: userType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						review := p.Source.(*models.Review)
+						return thunk(p.Context, loadersFrom(p.Context).users.load(p.Context, review.User.Id)), nil
+					},
+				},
+			}
+		}),
+	})
+
+	bookingType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Booking",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+				"price":     &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+				"paid":      &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+				"createdAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+				"tour": &graphql.Field{
+					Type: tourType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						booking := p.Source.(*models.Booking)
+						return thunk(p.Context, loadersFrom(p.Context).tours.load(p.Context, booking.Tour.Id)), nil
+					},
+				},
+				"user": &graphql.Field{
+					Type: userType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						booking := p.Source.(*models.Booking)
+						return thunk(p.Context, loadersFrom(p.Context).users.load(p.Context, booking.User.Id)), nil
+					},
+				},
+			}
+		}),
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"tours": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(tourType))),
+				Args: listArgs(defaultListLimit),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					limit, offset := pageArgs(p.Args)
+					if limit == 0 {
+						return []*models.Tour{}, nil
+					}
+					tours, err := deps.Tours.GetTourPage(p.Context, "", limit, offset)
+					if err != nil {
+						return nil, resolverError(p.Context, err)
+					}
+					return pointers(tours), nil
+				},
+			},
+			"tour": &graphql.Field{
+				Type: tourType,
+				Args: graphql.FieldConfigArgument{
+					"id":   &graphql.ArgumentConfig{Type: graphql.ID},
+					"slug": &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if id, ok := p.Args["id"].(string); ok {
+						return deps.Tours.FindTourById(p.Context, id), nil
+					}
+					if slug, ok := p.Args["slug"].(string); ok {
+						return deps.Tours.FindTourBySlug(p.Context, slug), nil
+					}
+					return nil, resolverError(p.Context, apperrors.BadRequest("Either id or slug is required"))
+				},
+			},
+			"reviews": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(reviewType))),
+				Args: graphql.FieldConfigArgument{
+					"sort":   &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: ""},
+					"limit":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultListLimit},
+					"offset": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					sort, _ := p.Args["sort"].(string)
+					limit, offset := pageArgs(p.Args)
+					if limit == 0 {
+						return []*models.Review{}, nil
+					}
+					reviews, err := deps.Reviews.GetReviewPage(p.Context, "", sort, limit, offset)
+					if err != nil {
+						return nil, resolverError(p.Context, err)
+					}
+					return pointers(reviews), nil
+				},
+			},
+			"review": &graphql.Field{
+				Type: reviewType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					review := deps.Reviews.GetReviewById(p.Context, p.Args["id"].(string))
+					if review == nil || review.Status != models.ReviewStatusPublished {
+						return nil, nil
+					}
+					return review, nil
+				},
+			},
+			"user": &graphql.Field{
+				Type: userType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return thunk(p.Context, loadersFrom(p.Context).users.load(p.Context, p.Args["id"].(string))), nil
+				},
+			},
+			"me": &graphql.Field{
+				Type: userType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return currentUser(p.Context), nil
+				},
+			},
+			"myBookings": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(bookingType))),
+				Args: listArgs(defaultListLimit),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					user := currentUser(p.Context)
+					if user == nil {
+						return nil, resolverError(p.Context, apperrors.Unauthorized("Unauthorized"))
+					}
+					limit, offset := pageArgs(p.Args)
+					if limit == 0 {
+						return []*models.Booking{}, nil
+					}
+					bookings, err := deps.Bookings.GetBookingPage(p.Context, "", user.Id, limit, offset)
+					if err != nil {
+						return nil, resolverError(p.Context, err)
+					}
+					return pointers(bookings), nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
+
+func currentUser(ctx context.Context) *models.User {
+	user, _ := ctx.Value("user").(*models.User)
+	return user
+}
+
+func restrictTo(ctx context.Context, roles ...string) error {
+	user := currentUser(ctx)
+	if user == nil {
+		return apperrors.Unauthorized("Unauthorized")
+	}
+	for _, role := range roles {
+		if user.Role == role {
+			return nil
+		}
+	}
+	return apperrors.Forbidden("You are not authorized to access this resource")
+}
+
+func pointers[T any](values []T) []*T {
+	result := make([]*T, len(values))
+	for i := range values {
+		result[i] = &values[i]
+	}
+	return result
+}
+
+func pageArgs(args map[string]interface{}) (int, int) {
+	offset, _ := args["offset"].(int)
+	limit, _ := args["limit"].(int)
+	return min(max(limit, 0), maxListLimit), max(offset, 0)
+}
+
+func page[T any](values []T, args map[string]interface{}) []T {
+	limit, offset := pageArgs(args)
+	offset = min(offset, len(values))
+	return values[offset:min(offset+limit, len(values))]
+}
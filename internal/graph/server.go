@@ -0,0 +1,88 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+type Request struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type Server struct {
+	schema   graphql.Schema
+	services Services
+	limits   Limits
+}
+
+func New(services Services, limits Limits) (*Server, error) {
+	schema, err := newSchema(services)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build GraphQL schema: %v", err)
+	}
+
+	return &Server{
+		schema:   schema,
+		services: services,
+		limits:   limits,
+	}, nil
+}
+
+func (s *Server) Schema() graphql.Schema {
+	return s.schema
+}
+
+func (s *Server) CheckLimits(request Request) *graphql.Result {
+	document, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(request.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return nil
+	}
+
+	if err := s.limits.check(&s.schema, document, request.OperationName, request.Variables); err != nil {
+		return &graphql.Result{Errors: []gqlerrors.FormattedError{{Message: err.Error(), Locations: []location.SourceLocation{}, Extensions: err.Extensions()}}}
+	}
+	return nil
+}
+
+func (s *Server) Execute(ctx context.Context, request Request) *graphql.Result {
+	result := graphql.Do(graphql.Params{
+		Schema:         s.schema,
+		RequestString:  request.Query,
+		OperationName:  request.OperationName,
+		VariableValues: request.Variables,
+		Context:        withLoaders(ctx, s.services),
+	})
+
+	for i, err := range result.Errors {
+		if err.Extensions == nil {
+			result.Errors[i].Extensions = extensionsOf(err)
+		}
+	}
+	return result
+}
+
+func extensionsOf(err error) map[string]interface{} {
+	for err != nil {
+		switch e := err.(type) {
+		case *graphError:
+			return e.Extensions()
+		case *gqlerrors.Error:
+			err = e.OriginalError
+		case gqlerrors.FormattedError:
+			err = e.OriginalError()
+		default:
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"github.com/hamid-nazari/tours-in-go/internal/repositories"
+	"github.com/hamid-nazari/tours-in-go/internal/services"
+	"github.com/hamid-nazari/tours-in-go/pkg/toursv1"
+)
+
+type bookingServer struct {
+	toursv1.UnimplementedBookingServiceServer
+	bookings *services.BookingService
+}
+
+func (s *bookingServer) ListBookings(ctx context.Context, req *toursv1.ListBookingsRequest) (*toursv1.ListBookingsResponse, error) {
+	if err := restrictTo(ctx, "admin", "lead-guide"); err != nil {
+		return nil, err
+	}
+
+	size, offset, err := pageBounds(req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+	bookings, err := s.bookings.GetBookingPage(ctx, req.GetTourId(), req.GetUserId(), size+1, offset)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	page, nextPageToken := nextPage(bookings, size, offset)
+
+	response := &toursv1.ListBookingsResponse{NextPageToken: nextPageToken}
+	for i := range page {
+		response.Bookings = append(response.Bookings, bookingMessage(&page[i]))
+	}
+	return response, nil
+}
+
+func (s *bookingServer) GetBooking(ctx context.Context, req *toursv1.GetBookingRequest) (*toursv1.GetBookingResponse, error) {
+	if err := restrictTo(ctx, "admin", "lead-guide"); err != nil {
+		return nil, err
+	}
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	booking, err := s.bookings.GetBooking(ctx, &models.Booking{Id: req.GetId()})
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "No booking found with that ID")
+	}
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &toursv1.GetBookingResponse{Booking: bookingMessage(booking)}, nil
+}
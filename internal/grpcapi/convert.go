@@ -0,0 +1,109 @@
+package grpcapi
+
+import (
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"github.com/hamid-nazari/tours-in-go/pkg/toursv1"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+func pageBounds(pageSize int32, pageToken string) (int, int, error) {
+	offset := 0
+	if pageToken != "" {
+		parsed, err := strconv.Atoi(pageToken)
+		if err != nil || parsed < 0 {
+			return 0, 0, status.Error(codes.InvalidArgument, "Invalid page token")
+		}
+		offset = parsed
+	}
+
+	size := int(pageSize)
+	if size <= 0 {
+		size = defaultPageSize
+	}
+	return min(size, maxPageSize), offset, nil
+}
+
+func nextPage[T any](items []T, size int, offset int) ([]T, string) {
+	if len(items) <= size {
+		return items, ""
+	}
+	return items[:size], strconv.Itoa(offset + size)
+}
+
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func userSummary(user models.User) *toursv1.UserSummary {
+	return &toursv1.UserSummary{
+		Id:    user.Id,
+		Name:  user.Name,
+		Photo: user.Photo,
+		Role:  user.Role,
+	}
+}
+
+func tourMessage(tour *models.Tour) *toursv1.Tour {
+	message := &toursv1.Tour{
+		Id:              tour.Id,
+		Name:            tour.Name,
+		Slug:            tour.Slug,
+		Duration:        tour.Duration,
+		Difficulty:      tour.Difficulty,
+		Price:           tour.Price,
+		MaxGroupSize:    int32(tour.MaxGroupSize),
+		RatingsAverage:  tour.RatingsAvg,
+		RatingsQuantity: int32(tour.RatingQuantity),
+		Summary:         tour.Summary,
+		Description:     tour.Description,
+		ImageCover:      tour.ImageCover,
+		SecretTour:      tour.SecretTour,
+		CreatedAt:       timestamp(tour.CreatedAt),
+	}
+	for _, date := range tour.StartDates {
+		message.StartDates = append(message.StartDates, timestamppb.New(date))
+	}
+	for _, guide := range tour.GuideProfiles {
+		message.Guides = append(message.Guides, &toursv1.UserSummary{Id: guide.Id, Name: guide.Name, Photo: guide.Photo, Role: guide.Role})
+	}
+	return message
+}
+
+func reviewMessage(review *models.Review) *toursv1.Review {
+	return &toursv1.Review{
+		Id:             review.Id,
+		TourId:         review.Tour.Id,
+		User:           userSummary(review.User),
+		Review:         review.Review,
+		Rating:         int32(review.Rating),
+		HelpfulCount:   int32(review.HelpfulCount),
+		UnhelpfulCount: int32(review.UnhelpfulCount),
+		CreatedAt:      timestamp(review.CreatedAt),
+	}
+}
+
+func bookingMessage(booking *models.Booking) *toursv1.Booking {
+	return &toursv1.Booking{
+		Id:        booking.Id,
+		TourId:    booking.Tour.Id,
+		TourName:  booking.Tour.Name,
+		User:      userSummary(booking.User),
+		Price:     booking.Price,
+		Paid:      booking.Paid,
+		CreatedAt: timestamp(booking.CreatedAt),
+	}
+}
@@ -0,0 +1,38 @@
+package grpcapi
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPageBounds(t *testing.T) {
+	size, offset, err := pageBounds(0, "")
+	if err != nil || size != defaultPageSize || offset != 0 {
+		t.Errorf("default page is %d at %d (%v)", size, offset, err)
+	}
+
+	size, offset, err = pageBounds(1000, "40")
+	if err != nil || size != maxPageSize || offset != 40 {
+		t.Errorf("large page is %d at %d (%v)", size, offset, err)
+	}
+
+	for _, token := range []string{"-1", "next"} {
+		if _, _, err := pageBounds(10, token); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("page token %q returned %v, want InvalidArgument", token, err)
+		}
+	}
+}
+
+func TestNextPage(t *testing.T) {
+	page, token := nextPage([]int{1, 2, 3}, 2, 4)
+	if len(page) != 2 || token != "6" {
+		t.Errorf("got %v with token %q, want two items and token 6", page, token)
+	}
+
+	page, token = nextPage([]int{1, 2}, 2, 4)
+	if len(page) != 2 || token != "" {
+		t.Errorf("got %v with token %q, want the last page", page, token)
+	}
+}
@@ -0,0 +1,137 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/hamid-nazari/tours-in-go/internal/apperrors"
+	"github.com/hamid-nazari/tours-in-go/internal/logging"
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"github.com/hamid-nazari/tours-in-go/internal/services"
+)
+
+type interceptors struct {
+	auth    *services.AuthService
+	apiKeys []string
+	logger  *slog.Logger
+}
+
+func (i *interceptors) log(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+
+	ctx = logging.WithLogger(ctx, i.logger.With("method", info.FullMethod))
+
+	response, err := handler(ctx, req)
+
+	code := status.Code(err)
+	attrs := []any{"code", code.String(), "durationMs", time.Since(start).Milliseconds()}
+	switch code {
+	case codes.OK:
+		logging.FromContext(ctx).InfoContext(ctx, "call completed", attrs...)
+	case codes.Internal, codes.Unknown, codes.DataLoss:
+		logging.FromContext(ctx).ErrorContext(ctx, "call completed", append(attrs, "error", err)...)
+	default:
+		logging.FromContext(ctx).WarnContext(ctx, "call completed", append(attrs, "error", err)...)
+	}
+
+	return response, err
+}
+
+func (i *interceptors) recover(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (response any, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = toStatus(ctx, apperrors.Internal(fmt.Errorf("panic: %v", recovered)))
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+func (i *interceptors) authenticate(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if keys := md.Get("x-api-key"); len(keys) > 0 {
+		if !services.ValidAPIKey(keys[0], i.apiKeys) {
+			return nil, status.Error(codes.Unauthenticated, "Invalid API key")
+		}
+		ctx = context.WithValue(ctx, apiClientKey{}, true)
+		ctx = logging.WithLogger(ctx, logging.FromContext(ctx).With("caller", "api-key"))
+	} else if values := md.Get("authorization"); len(values) > 0 {
+		currentUser, err := i.auth.Authenticate(ctx, values[0])
+		if err != nil {
+			return nil, toStatus(ctx, err)
+		}
+		ctx = context.WithValue(ctx, "user", currentUser)
+		ctx = logging.WithLogger(ctx, logging.FromContext(ctx).With("userId", currentUser.Id))
+	}
+
+	return handler(ctx, req)
+}
+
+type apiClientKey struct{}
+
+func isAPIClient(ctx context.Context) bool {
+	apiClient, _ := ctx.Value(apiClientKey{}).(bool)
+	return apiClient
+}
+
+func currentUser(ctx context.Context) *models.User {
+	user, _ := ctx.Value("user").(*models.User)
+	return user
+}
+
+func hasRole(ctx context.Context, roles ...string) bool {
+	user := currentUser(ctx)
+	if user == nil {
+		return false
+	}
+	for _, role := range roles {
+		if user.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+func restrictTo(ctx context.Context, roles ...string) error {
+	if isAPIClient(ctx) || hasRole(ctx, roles...) {
+		return nil
+	}
+	if currentUser(ctx) == nil {
+		return status.Error(codes.Unauthenticated, "Unauthorized")
+	}
+	return status.Error(codes.PermissionDenied, "You are not authorized to access this resource")
+}
+
+func toStatus(ctx context.Context, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	appErr := apperrors.From(err)
+
+	code := codes.Internal
+	switch appErr.Code {
+	case apperrors.CodeBadRequest, apperrors.CodeValidation:
+		code = codes.InvalidArgument
+	case apperrors.CodeUnauthorized:
+		code = codes.Unauthenticated
+	case apperrors.CodeForbidden:
+		code = codes.PermissionDenied
+	case apperrors.CodeNotFound:
+		code = codes.NotFound
+	case apperrors.CodeConflict:
+		code = codes.AlreadyExists
+	default:
+		logging.FromContext(ctx).ErrorContext(ctx, "call failed", "error", appErr.Err)
+	}
+
+	return status.Error(code, appErr.Message)
+}
@@ -0,0 +1,92 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/tls"
+	"log/slog"
+	"net"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/hamid-nazari/tours-in-go/internal/config"
+	"github.com/hamid-nazari/tours-in-go/internal/logging"
+	"github.com/hamid-nazari/tours-in-go/internal/services"
+	"github.com/hamid-nazari/tours-in-go/pkg/toursv1"
+)
+
+type Services struct {
+	Auth     *services.AuthService
+	Users    *services.UserService
+	Tours    *services.TourService
+	Reviews  *services.ReviewService
+	Bookings *services.BookingService
+}
+
+type Server struct {
+	server *grpc.Server
+	health *health.Server
+}
+
+func New(cfg config.GRPCConfig, deps Services, logger *slog.Logger, tlsConfig *tls.Config) *Server {
+	interceptors := &interceptors{
+		auth:    deps.Auth,
+		apiKeys: cfg.APIKeys,
+		logger:  logging.Package(logger, "grpc"),
+	}
+
+	options := []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(interceptors.log, interceptors.recover, interceptors.authenticate),
+	}
+	if tlsConfig != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	server := grpc.NewServer(options...)
+	healthServer := health.NewServer()
+
+	toursv1.RegisterTourServiceServer(server, &tourServer{tours: deps.Tours, reviews: deps.Reviews})
+	toursv1.RegisterBookingServiceServer(server, &bookingServer{bookings: deps.Bookings})
+	toursv1.RegisterUserServiceServer(server, &userServer{users: deps.Users})
+	healthpb.RegisterHealthServer(server, healthServer)
+	if cfg.Reflection {
+		reflection.Register(server)
+	}
+
+	for name := range server.GetServiceInfo() {
+		healthServer.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+
+	return &Server{
+		server: server,
+		health: healthServer,
+	}
+}
+
+func (s *Server) Serve(listener net.Listener) error {
+	return s.server.Serve(listener)
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.health.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,80 @@
+package grpcapi
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"github.com/hamid-nazari/tours-in-go/internal/services"
+	"github.com/hamid-nazari/tours-in-go/pkg/toursv1"
+)
+
+type tourServer struct {
+	toursv1.UnimplementedTourServiceServer
+	tours   *services.TourService
+	reviews *services.ReviewService
+}
+
+func scopeTours(ctx context.Context, includeSecret bool) context.Context {
+	allowed := isAPIClient(ctx) || hasRole(ctx, "admin")
+	return services.WithTourScope(ctx, services.TourScope{IncludeSecret: includeSecret && allowed})
+}
+
+func (s *tourServer) ListTours(ctx context.Context, req *toursv1.ListToursRequest) (*toursv1.ListToursResponse, error) {
+	ctx = scopeTours(ctx, req.GetIncludeSecret())
+
+	size, offset, err := pageBounds(req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+	tours, err := s.tours.GetTourPage(ctx, req.GetGuideId(), size+1, offset)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	page, nextPageToken := nextPage(tours, size, offset)
+
+	response := &toursv1.ListToursResponse{NextPageToken: nextPageToken}
+	for i := range page {
+		response.Tours = append(response.Tours, tourMessage(&page[i]))
+	}
+	return response, nil
+}
+
+func (s *tourServer) GetTour(ctx context.Context, req *toursv1.GetTourRequest) (*toursv1.GetTourResponse, error) {
+	ctx = scopeTours(ctx, isAPIClient(ctx) || hasRole(ctx, "admin", "lead-guide"))
+
+	var tour *models.Tour
+	switch {
+	case req.GetId() != "":
+		tour = s.tours.FindTourById(ctx, req.GetId())
+	case req.GetSlug() != "":
+		tour = s.tours.FindTourBySlug(ctx, req.GetSlug())
+	default:
+		return nil, status.Error(codes.InvalidArgument, "Either id or slug is required")
+	}
+
+	if tour == nil {
+		return nil, status.Error(codes.NotFound, "No tour found")
+	}
+	return &toursv1.GetTourResponse{Tour: tourMessage(tour)}, nil
+}
+
+func (s *tourServer) ListReviews(ctx context.Context, req *toursv1.ListReviewsRequest) (*toursv1.ListReviewsResponse, error) {
+	size, offset, err := pageBounds(req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := s.reviews.GetReviewPage(ctx, req.GetTourId(), req.GetSort(), size+1, offset)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	page, nextPageToken := nextPage(reviews, size, offset)
+
+	response := &toursv1.ListReviewsResponse{NextPageToken: nextPageToken}
+	for i := range page {
+		response.Reviews = append(response.Reviews, reviewMessage(&page[i]))
+	}
+	return response, nil
+}
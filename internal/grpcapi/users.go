@@ -0,0 +1,50 @@
+package grpcapi
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hamid-nazari/tours-in-go/internal/services"
+	"github.com/hamid-nazari/tours-in-go/pkg/toursv1"
+)
+
+const maxBatchSize = 100
+
+type userServer struct {
+	toursv1.UnimplementedUserServiceServer
+	users *services.UserService
+}
+
+func (s *userServer) GetUser(ctx context.Context, req *toursv1.GetUserRequest) (*toursv1.GetUserResponse, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	user := s.users.FindUserById(ctx, req.GetId())
+	if user == nil {
+		return nil, status.Error(codes.NotFound, "No user found with that ID")
+	}
+	return &toursv1.GetUserResponse{User: userSummary(*user)}, nil
+}
+
+func (s *userServer) BatchGetUsers(ctx context.Context, req *toursv1.BatchGetUsersRequest) (*toursv1.BatchGetUsersResponse, error) {
+	if len(req.GetIds()) > maxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "At most %d ids can be requested at once", maxBatchSize)
+	}
+	if len(req.GetIds()) == 0 {
+		return &toursv1.BatchGetUsersResponse{}, nil
+	}
+
+	users, err := s.users.FindUsersByIds(ctx, req.GetIds())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	response := &toursv1.BatchGetUsersResponse{}
+	for _, user := range users {
+		response.Users = append(response.Users, userSummary(user))
+	}
+	return response, nil
+}
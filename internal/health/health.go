@@ -0,0 +1,112 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+type Check struct {
+	Name    string
+	Timeout time.Duration
+	Run     func(ctx context.Context) error
+}
+
+type CheckResult struct {
+	Status     string `json:"status"`
+	DurationMs int64  `json:"durationMs"`
+	Error      string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Ready  bool                   `json:"ready"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+type Registry struct {
+	mutex  sync.RWMutex
+	checks []Check
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) Register(check Check) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.checks = append(r.checks, check)
+}
+
+func (r *Registry) Run(ctx context.Context) Report {
+	r.mutex.RLock()
+	checks := append([]Check(nil), r.checks...)
+	r.mutex.RUnlock()
+
+	report := Report{Ready: true, Checks: map[string]CheckResult{}}
+
+	var mutex sync.Mutex
+	var wait sync.WaitGroup
+	for _, check := range checks {
+		wait.Add(1)
+		go func(check Check) {
+			defer wait.Done()
+			result := runCheck(ctx, check)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			report.Checks[check.Name] = result
+			if result.Status != StatusUp {
+				report.Ready = false
+			}
+		}(check)
+	}
+	wait.Wait()
+
+	return report
+}
+
+func runCheck(ctx context.Context, check Check) CheckResult {
+	if check.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, check.Timeout)
+		defer cancel()
+	}
+
+	started := time.Now()
+	errs := make(chan error, 1)
+	go func() { errs <- check.Run(ctx) }()
+
+	var err error
+	select {
+	case err = <-errs:
+	case <-ctx.Done():
+		err = fmt.Errorf("timed out after %s", check.Timeout)
+	}
+
+	result := CheckResult{Status: StatusUp, DurationMs: time.Since(started).Milliseconds()}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func TCPCheck(address string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		var dialer net.Dialer
+		connection, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return err
+		}
+		return connection.Close()
+	}
+}
@@ -0,0 +1,305 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/hamid-nazari/tours-in-go/internal/apperrors"
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"github.com/hamid-nazari/tours-in-go/internal/repositories"
+	"github.com/hamid-nazari/tours-in-go/internal/utils"
+)
+
+var ErrDuplicate = apperrors.Conflict("An identical job is already queued or running")
+
+type Handler func(ctx context.Context, job *models.Job) error
+
+type Policy struct {
+	Workers        int
+	PollInterval   time.Duration
+	Timeout        time.Duration
+	MaxAttempts    int
+	RetryBaseDelay time.Duration
+	MaxRetryDelay  time.Duration
+}
+
+type Options struct {
+	UniqueKey   string
+	RunAt       time.Time
+	MaxAttempts int
+}
+
+type schedule struct {
+	name     string
+	spec     string
+	schedule cron.Schedule
+	jobType  string
+	payload  json.RawMessage
+}
+
+type Queue struct {
+	jobs      repositories.JobRepository
+	policy    Policy
+	logger    *slog.Logger
+	handlers  map[string]Handler
+	schedules []schedule
+	wake      chan struct{}
+}
+
+func NewQueue(jobs repositories.JobRepository, policy Policy, logger *slog.Logger) *Queue {
+	return &Queue{
+		jobs:     jobs,
+		policy:   policy,
+		logger:   logger,
+		handlers: map[string]Handler{},
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+func (q *Queue) Register(jobType string, handler Handler) {
+	q.handlers[jobType] = handler
+}
+
+func (q *Queue) Schedule(name string, spec string, jobType string, payload any) error {
+	parsed, err := cron.ParseStandard(spec)
+	if err != nil {
+		return fmt.Errorf("invalid schedule %q for %s: %v", spec, name, err)
+	}
+
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload for %s: %v", name, err)
+	}
+
+	q.schedules = append(q.schedules, schedule{name: name, spec: spec, schedule: parsed, jobType: jobType, payload: encoded})
+	return nil
+}
+
+func (q *Queue) Enqueue(ctx context.Context, jobType string, payload any, options Options) (*models.Job, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s job: %v", jobType, err)
+	}
+	return q.enqueue(ctx, jobType, encoded, "", options)
+}
+
+func (q *Queue) enqueue(ctx context.Context, jobType string, payload json.RawMessage, scheduleName string, options Options) (*models.Job, error) {
+	if _, ok := q.handlers[jobType]; !ok {
+		return nil, fmt.Errorf("no handler registered for %s jobs", jobType)
+	}
+
+	job := models.NewJob(jobType)
+	job.Payload = payload
+	job.Schedule = scheduleName
+	job.UniqueKey = options.UniqueKey
+	job.ActiveKey = options.UniqueKey
+	job.MaxAttempts = q.policy.MaxAttempts
+	if options.MaxAttempts > 0 {
+		job.MaxAttempts = options.MaxAttempts
+	}
+	if !options.RunAt.IsZero() {
+		job.RunAt = options.RunAt
+	}
+
+	err := q.jobs.Enqueue(ctx, job)
+	if errors.Is(err, repositories.ErrDuplicate) {
+		return nil, ErrDuplicate
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	q.notify()
+	return job, nil
+}
+
+func (q *Queue) GetJobs(ctx context.Context, jobType string, status string, limit int) ([]models.Job, error) {
+	return q.jobs.Find(ctx, repositories.JobFilter{Type: jobType, Status: status, Limit: limit})
+}
+
+func (q *Queue) FindJobById(ctx context.Context, id string) *models.Job {
+	job, err := q.jobs.FindById(ctx, id)
+	if err != nil {
+		if !errors.Is(err, repositories.ErrNotFound) {
+			q.logger.ErrorContext(ctx, "failed to find job", "jobId", id, "error", err)
+		}
+		return nil
+	}
+	return job
+}
+
+func (q *Queue) Retry(ctx context.Context, job *models.Job) error {
+	if job.Status == models.JobRunning || job.Status == models.JobSucceeded {
+		return apperrors.Conflict(fmt.Sprintf("Only queued or dead jobs can be retried, this job is %s", job.Status))
+	}
+
+	job.Status = models.JobQueued
+	job.ActiveKey = job.UniqueKey
+	job.Attempts = 0
+	job.RunAt = time.Now()
+	job.LockedUntil = time.Time{}
+	job.FinishedAt = time.Time{}
+
+	err := q.jobs.Update(ctx, job)
+	if errors.Is(err, repositories.ErrDuplicate) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return err
+	}
+
+	q.notify()
+	return nil
+}
+
+func (q *Queue) Types() []string {
+	types := make([]string, 0, len(q.handlers))
+	for jobType := range q.handlers {
+		types = append(types, jobType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func (q *Queue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *Queue) Name() string {
+	return "jobs"
+}
+
+func (q *Queue) Run(ctx context.Context) error {
+	ticker := time.NewTicker(q.policy.PollInterval)
+	defer ticker.Stop()
+
+	slots := make(chan struct{}, q.policy.Workers)
+	var running sync.WaitGroup
+	defer running.Wait()
+
+	for {
+		q.runSchedules(ctx)
+		q.dispatch(ctx, slots, &running)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		case <-q.wake:
+		}
+	}
+}
+
+func (q *Queue) runSchedules(ctx context.Context) {
+	now := time.Now()
+
+	for _, schedule := range q.schedules {
+		due, err := q.jobs.ClaimSchedule(ctx, schedule.name, now, schedule.schedule.Next(now))
+		if err != nil {
+			q.logger.ErrorContext(ctx, "failed to claim job schedule", "schedule", schedule.name, "error", err)
+			continue
+		}
+		if !due {
+			continue
+		}
+
+		_, err = q.enqueue(ctx, schedule.jobType, schedule.payload, schedule.name, Options{UniqueKey: "schedule:" + schedule.name})
+		if errors.Is(err, ErrDuplicate) {
+			q.logger.InfoContext(ctx, "skipping scheduled job, the previous run has not finished", "schedule", schedule.name)
+		} else if err != nil {
+			q.logger.ErrorContext(ctx, "failed to enqueue scheduled job", "schedule", schedule.name, "error", err)
+		}
+	}
+}
+
+func (q *Queue) dispatch(ctx context.Context, slots chan struct{}, running *sync.WaitGroup) {
+	types := q.Types()
+	if len(types) == 0 {
+		return
+	}
+
+	for ctx.Err() == nil {
+		free := cap(slots) - len(slots)
+		if free == 0 {
+			return
+		}
+
+		claimed, err := q.jobs.ClaimDue(ctx, types, time.Now(), q.policy.Timeout+time.Minute, free)
+		if err != nil {
+			q.logger.ErrorContext(ctx, "failed to claim jobs", "error", err)
+			return
+		}
+
+		for i := range claimed {
+			slots <- struct{}{}
+			running.Add(1)
+			go func(job *models.Job) {
+				defer running.Done()
+				q.execute(ctx, job)
+				<-slots
+				q.notify()
+			}(&claimed[i])
+		}
+
+		if len(claimed) < free {
+			return
+		}
+	}
+}
+
+func (q *Queue) execute(ctx context.Context, job *models.Job) {
+	runCtx, cancel := context.WithTimeout(ctx, q.policy.Timeout)
+	err := run(runCtx, q.handlers[job.Type], job)
+	cancel()
+
+	now := time.Now()
+	job.LockedUntil = time.Time{}
+	job.LastError = ""
+
+	switch {
+	case err == nil:
+		job.Status = models.JobSucceeded
+		job.ActiveKey = ""
+		job.FinishedAt = now
+	case ctx.Err() != nil:
+		job.Status = models.JobQueued
+		job.Attempts--
+		job.RunAt = now
+		job.LastError = "interrupted by shutdown"
+	case job.Attempts >= job.MaxAttempts:
+		job.Status = models.JobDead
+		job.ActiveKey = ""
+		job.FinishedAt = now
+		job.LastError = err.Error()
+		q.logger.ErrorContext(ctx, "job moved to the dead letter queue", "jobId", job.Id, "type", job.Type, "attempts", job.Attempts, "error", err)
+	default:
+		job.Status = models.JobQueued
+		job.RunAt = now.Add(utils.RetryDelay(job.Attempts, q.policy.RetryBaseDelay, q.policy.MaxRetryDelay))
+		job.LastError = err.Error()
+		q.logger.WarnContext(ctx, "job failed, retrying", "jobId", job.Id, "type", job.Type, "attempts", job.Attempts, "error", err)
+	}
+
+	if err := q.jobs.Update(context.WithoutCancel(ctx), job); err != nil {
+		q.logger.ErrorContext(ctx, "failed to record job result", "jobId", job.Id, "error", err)
+	}
+}
+
+func run(ctx context.Context, handler Handler, job *models.Job) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = errors.New(fmt.Sprint("panic: ", recovered))
+		}
+	}()
+	return handler(ctx, job)
+}
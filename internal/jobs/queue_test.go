@@ -0,0 +1,245 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"github.com/hamid-nazari/tours-in-go/internal/repositories"
+)
+
+const testJob = "test.job"
+
+func newTestQueue() (*Queue, *repositories.MemoryJobRepository) {
+	repo := repositories.NewMemoryJobRepository()
+	queue := NewQueue(repo, Policy{
+		Workers:        2,
+		PollInterval:   5 * time.Millisecond,
+		Timeout:        time.Second,
+		MaxAttempts:    3,
+		RetryBaseDelay: time.Millisecond,
+		MaxRetryDelay:  2 * time.Millisecond,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	return queue, repo
+}
+
+func startQueue(t *testing.T, queue *Queue) (stop func()) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- queue.Run(ctx) }()
+
+	stopped := false
+	stop = func() {
+		if stopped {
+			return
+		}
+		stopped = true
+		cancel()
+		if err := <-done; !errors.Is(err, context.Canceled) {
+			t.Errorf("expected the queue to stop with context.Canceled, got %v", err)
+		}
+	}
+	t.Cleanup(stop)
+	return stop
+}
+
+func waitForStatus(t *testing.T, queue *Queue, id string, status string) *models.Job {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job := queue.FindJobById(context.Background(), id)
+		if job != nil && job.Status == status {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s did not become %s: %+v", id, status, job)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestQueueRunsJobs(t *testing.T) {
+	queue, _ := newTestQueue()
+	received := make(chan string, 1)
+	queue.Register(testJob, func(ctx context.Context, job *models.Job) error {
+		received <- string(job.Payload)
+		return nil
+	})
+	startQueue(t, queue)
+
+	job, err := queue.Enqueue(context.Background(), testJob, map[string]string{"name": "value"}, Options{})
+	if err != nil {
+		t.Fatalf("failed to enqueue job: %v", err)
+	}
+
+	finished := waitForStatus(t, queue, job.Id, models.JobSucceeded)
+	if finished.Attempts != 1 || finished.FinishedAt.IsZero() || finished.LastError != "" {
+		t.Errorf("unexpected finished job: %+v", finished)
+	}
+	if payload := <-received; payload != `{"name":"value"}` {
+		t.Errorf("unexpected payload %s", payload)
+	}
+}
+
+func TestQueueRejectsUnregisteredJobs(t *testing.T) {
+	queue, _ := newTestQueue()
+
+	if _, err := queue.Enqueue(context.Background(), testJob, nil, Options{}); err == nil {
+		t.Error("expected enqueueing a job without a handler to fail")
+	}
+}
+
+func TestQueueRetriesFailedJobs(t *testing.T) {
+	queue, _ := newTestQueue()
+	var calls atomic.Int32
+	queue.Register(testJob, func(ctx context.Context, job *models.Job) error {
+		if calls.Add(1) == 1 {
+			return errors.New("temporary failure")
+		}
+		return nil
+	})
+	startQueue(t, queue)
+
+	job, err := queue.Enqueue(context.Background(), testJob, nil, Options{})
+	if err != nil {
+		t.Fatalf("failed to enqueue job: %v", err)
+	}
+
+	finished := waitForStatus(t, queue, job.Id, models.JobSucceeded)
+	if finished.Attempts != 2 || calls.Load() != 2 {
+		t.Errorf("expected one retry, got %d attempts and %d calls", finished.Attempts, calls.Load())
+	}
+}
+
+func TestQueueDeadLettersExhaustedJobs(t *testing.T) {
+	queue, _ := newTestQueue()
+	var calls atomic.Int32
+	queue.Register(testJob, func(ctx context.Context, job *models.Job) error {
+		calls.Add(1)
+		if calls.Load() == 2 {
+			panic("handler crashed")
+		}
+		return errors.New("permanent failure")
+	})
+	startQueue(t, queue)
+
+	job, err := queue.Enqueue(context.Background(), testJob, nil, Options{UniqueKey: "dead", MaxAttempts: 4})
+	if err != nil {
+		t.Fatalf("failed to enqueue job: %v", err)
+	}
+
+	dead := waitForStatus(t, queue, job.Id, models.JobDead)
+	if dead.Attempts != 4 || calls.Load() != 4 || dead.LastError != "permanent failure" || dead.ActiveKey != "" {
+		t.Errorf("unexpected dead job after %d calls: %+v", calls.Load(), dead)
+	}
+
+	if _, err := queue.Enqueue(context.Background(), testJob, nil, Options{UniqueKey: "dead"}); err != nil {
+		t.Errorf("expected a dead job to release its unique key, got %v", err)
+	}
+}
+
+func TestQueueRejectsDuplicateUniqueKeys(t *testing.T) {
+	queue, _ := newTestQueue()
+	release := make(chan struct{})
+	queue.Register(testJob, func(ctx context.Context, job *models.Job) error {
+		<-release
+		return nil
+	})
+	ctx := context.Background()
+
+	first, err := queue.Enqueue(ctx, testJob, nil, Options{UniqueKey: "report"})
+	if err != nil {
+		t.Fatalf("failed to enqueue job: %v", err)
+	}
+	if _, err := queue.Enqueue(ctx, testJob, nil, Options{UniqueKey: "report"}); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("expected a queued duplicate to be rejected, got %v", err)
+	}
+	if _, err := queue.Enqueue(ctx, testJob, nil, Options{UniqueKey: "other"}); err != nil {
+		t.Fatalf("expected a different key to be accepted, got %v", err)
+	}
+
+	startQueue(t, queue)
+	waitForStatus(t, queue, first.Id, models.JobRunning)
+	if _, err := queue.Enqueue(ctx, testJob, nil, Options{UniqueKey: "report"}); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("expected a running duplicate to be rejected, got %v", err)
+	}
+
+	close(release)
+	waitForStatus(t, queue, first.Id, models.JobSucceeded)
+	if _, err := queue.Enqueue(ctx, testJob, nil, Options{UniqueKey: "report"}); err != nil {
+		t.Errorf("expected the key to be free after the job finished, got %v", err)
+	}
+}
+
+func TestQueueEnqueuesDueSchedules(t *testing.T) {
+	queue, repo := newTestQueue()
+	queue.Register(testJob, func(ctx context.Context, job *models.Job) error { return nil })
+	if err := queue.Schedule("nightly", "0 3 * * *", testJob, map[string]int{"days": 7}); err != nil {
+		t.Fatalf("failed to add schedule: %v", err)
+	}
+	if err := queue.Schedule("broken", "not a schedule", testJob, nil); err == nil {
+		t.Error("expected an invalid schedule to be rejected")
+	}
+	ctx := context.Background()
+
+	queue.runSchedules(ctx)
+	if jobs, _ := queue.GetJobs(ctx, testJob, "", 0); len(jobs) != 0 {
+		t.Fatalf("expected the first claim to only record the next run, got %d jobs", len(jobs))
+	}
+
+	makeDue := func() {
+		t.Helper()
+		if _, err := repo.ClaimSchedule(ctx, "nightly", time.Now().Add(48*time.Hour), time.Now().Add(-time.Minute)); err != nil {
+			t.Fatalf("failed to reset schedule: %v", err)
+		}
+	}
+
+	makeDue()
+	queue.runSchedules(ctx)
+	queue.runSchedules(ctx)
+
+	jobs, err := queue.GetJobs(ctx, testJob, "", 0)
+	if err != nil {
+		t.Fatalf("failed to list jobs: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].Schedule != "nightly" || jobs[0].UniqueKey != "schedule:nightly" || string(jobs[0].Payload) != `{"days":7}` {
+		t.Fatalf("expected one scheduled job, got %+v", jobs)
+	}
+
+	makeDue()
+	queue.runSchedules(ctx)
+	if jobs, _ := queue.GetJobs(ctx, testJob, "", 0); len(jobs) != 1 {
+		t.Errorf("expected an unfinished scheduled job to block the next run, got %d jobs", len(jobs))
+	}
+}
+
+func TestQueueRequeuesJobsOnShutdown(t *testing.T) {
+	queue, _ := newTestQueue()
+	started := make(chan struct{})
+	queue.Register(testJob, func(ctx context.Context, job *models.Job) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	stop := startQueue(t, queue)
+
+	job, err := queue.Enqueue(context.Background(), testJob, nil, Options{UniqueKey: "shutdown"})
+	if err != nil {
+		t.Fatalf("failed to enqueue job: %v", err)
+	}
+	<-started
+	stop()
+
+	requeued := queue.FindJobById(context.Background(), job.Id)
+	if requeued == nil || requeued.Status != models.JobQueued || requeued.Attempts != 0 || requeued.LastError != "interrupted by shutdown" || requeued.ActiveKey != "shutdown" {
+		t.Errorf("expected the job to be requeued without using an attempt, got %+v", requeued)
+	}
+}
@@ -0,0 +1,93 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type levels struct {
+	fallback slog.Level
+	packages map[string]slog.Level
+}
+
+func parseLevels(fallback string, packages []string) (levels, error) {
+	parsed := levels{packages: map[string]slog.Level{}}
+
+	if err := parsed.fallback.UnmarshalText([]byte(fallback)); err != nil {
+		return parsed, fmt.Errorf("invalid log level %q", fallback)
+	}
+
+	for _, entry := range packages {
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok || name == "" {
+			return parsed, fmt.Errorf("invalid package log level %q, expected package=level", entry)
+		}
+
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(value)); err != nil {
+			return parsed, fmt.Errorf("invalid log level %q for package %s", value, name)
+		}
+		parsed.packages[name] = level
+	}
+
+	return parsed, nil
+}
+
+func (l levels) forPackage(name string) slog.Level {
+	if level, ok := l.packages[name]; ok {
+		return level
+	}
+	return l.fallback
+}
+
+func (l levels) min() slog.Level {
+	lowest := l.fallback
+	for _, level := range l.packages {
+		lowest = min(lowest, level)
+	}
+	return lowest
+}
+
+type Handler struct {
+	handler slog.Handler
+	levels  levels
+	pkg     string
+	traced  bool
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.levels.forPackage(h.pkg)
+}
+
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	if !h.traced && ctx != nil {
+		if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+			record.AddAttrs(
+				slog.String("traceId", spanContext.TraceID().String()),
+				slog.String("spanId", spanContext.SpanID().String()),
+			)
+		}
+	}
+	return h.handler.Handle(ctx, record)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	pkg, traced := h.pkg, h.traced
+	for _, attr := range attrs {
+		switch attr.Key {
+		case "package":
+			pkg = attr.Value.String()
+		case "traceId":
+			traced = true
+		}
+	}
+	return &Handler{handler: h.handler.WithAttrs(attrs), levels: h.levels, pkg: pkg, traced: traced}
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{handler: h.handler.WithGroup(name), levels: h.levels, pkg: h.pkg, traced: h.traced}
+}
@@ -0,0 +1,91 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/hamid-nazari/tours-in-go/internal/config"
+)
+
+const Redacted = "[REDACTED]"
+
+var sensitiveKeys = map[string]bool{
+	"authorization":      true,
+	"cookie":             true,
+	"set-cookie":         true,
+	"password":           true,
+	"passwordconfirm":    true,
+	"currentpassword":    true,
+	"newpassword":        true,
+	"newpasswordconfirm": true,
+	"passwordresettoken": true,
+	"accesstoken":        true,
+	"token":              true,
+	"jwt":                true,
+	"secret":             true,
+}
+
+func New(cfg config.LoggingConfig, output io.Writer) (*slog.Logger, error) {
+	levels, err := parseLevels(cfg.Level, cfg.Levels)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &slog.HandlerOptions{
+		Level:       levels.min(),
+		ReplaceAttr: redact,
+	}
+
+	var handler slog.Handler
+	switch cfg.Format {
+	case "json":
+		handler = slog.NewJSONHandler(output, options)
+	case "text":
+		handler = slog.NewTextHandler(output, options)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", cfg.Format)
+	}
+
+	return slog.New(&Handler{handler: handler, levels: levels}), nil
+}
+
+func Package(logger *slog.Logger, name string) *slog.Logger {
+	return logger.With("package", name)
+}
+
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, "logger", logger)
+}
+
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value("logger").(*slog.Logger); ok {
+			return logger
+		}
+	}
+	return slog.Default()
+}
+
+func SetRequestLogger(c *gin.Context, logger *slog.Logger) {
+	c.Set("logger", logger)
+}
+
+func Attach(c *gin.Context, args ...any) {
+	SetRequestLogger(c, FromContext(c).With(args...))
+}
+
+func IsSensitive(key string) bool {
+	return sensitiveKeys[strings.ToLower(key)]
+}
+
+func redact(groups []string, attr slog.Attr) slog.Attr {
+	if IsSensitive(attr.Key) {
+		return slog.String(attr.Key, Redacted)
+	}
+	return attr
+}
@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "tours"
+
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests        *prometheus.CounterVec
+	httpRequestDuration *prometheus.HistogramVec
+	mongoCommands       *prometheus.HistogramVec
+
+	signups         prometheus.Counter
+	logins          *prometheus.CounterVec
+	bookingsCreated prometheus.Counter
+	bookingRevenue  prometheus.Counter
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests by method, route template and status.",
+		}, []string{"method", "route", "status"}),
+		httpRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, route template and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		mongoCommands: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "mongo_command_duration_seconds",
+			Help:      "MongoDB command latency by collection, command and outcome.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"collection", "command", "outcome"}),
+		signups: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "signups_total",
+			Help:      "Number of users who signed up.",
+		}),
+		logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "logins_total",
+			Help:      "Number of login attempts by outcome.",
+		}, []string{"outcome"}),
+		bookingsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "bookings_created_total",
+			Help:      "Number of bookings created.",
+		}),
+		bookingRevenue: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "booking_revenue_total",
+			Help:      "Revenue of paid bookings in the configured currency.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpRequestDuration,
+		m.mongoCommands,
+		m.signups,
+		m.logins,
+		m.bookingsCreated,
+		m.bookingRevenue,
+	)
+
+	m.logins.WithLabelValues("success")
+	m.logins.WithLabelValues("failure")
+
+	return m
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+func (m *Metrics) ObserveRequest(method string, route string, status int, duration time.Duration) {
+	statusLabel := strconv.Itoa(status)
+	m.httpRequests.WithLabelValues(method, route, statusLabel).Inc()
+	m.httpRequestDuration.WithLabelValues(method, route, statusLabel).Observe(duration.Seconds())
+}
+
+func (m *Metrics) RecordSignup() {
+	m.signups.Inc()
+}
+
+func (m *Metrics) RecordLogin(success bool) {
+	if success {
+		m.logins.WithLabelValues("success").Inc()
+	} else {
+		m.logins.WithLabelValues("failure").Inc()
+	}
+}
+
+func (m *Metrics) RecordBooking() {
+	m.bookingsCreated.Inc()
+}
+
+func (m *Metrics) RecordBookingPaid(price float64) {
+	if price > 0 {
+		m.bookingRevenue.Add(price)
+	}
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/event"
+)
+
+type commandKey struct {
+	requestId    int64
+	connectionId string
+}
+
+type mongoCommand struct {
+	collection string
+	command    string
+}
+
+func (m *Metrics) MongoMonitor() *event.CommandMonitor {
+	var inFlight sync.Map
+
+	finish := func(requestId int64, connectionId string, outcome string, seconds float64) {
+		key := commandKey{requestId: requestId, connectionId: connectionId}
+		started, ok := inFlight.LoadAndDelete(key)
+		if !ok {
+			return
+		}
+		command := started.(mongoCommand)
+		m.mongoCommands.WithLabelValues(command.collection, command.command, outcome).Observe(seconds)
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			collection := "none"
+			if evt.CommandName == "getMore" {
+				if value, ok := evt.Command.Lookup("collection").StringValueOK(); ok {
+					collection = value
+				}
+			} else if value, ok := evt.Command.Lookup(evt.CommandName).StringValueOK(); ok {
+				collection = value
+			}
+
+			key := commandKey{requestId: evt.RequestID, connectionId: evt.ConnectionID}
+			inFlight.Store(key, mongoCommand{collection: collection, command: evt.CommandName})
+		},
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			finish(evt.RequestID, evt.ConnectionID, "success", evt.Duration.Seconds())
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			finish(evt.RequestID, evt.ConnectionID, "failure", evt.Duration.Seconds())
+		},
+	}
+}
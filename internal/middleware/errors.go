@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamid-nazari/tours-in-go/internal/apperrors"
+	"github.com/hamid-nazari/tours-in-go/internal/logging"
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+)
+
+func HandleErrors(c *gin.Context) {
+	c.Next()
+
+	if len(c.Errors) == 0 {
+		return
+	}
+
+	err := c.Errors.Last().Err
+	if c.Writer.Written() {
+		logging.Package(logging.FromContext(c), "http").Error("error after response was written", "error", err)
+		return
+	}
+
+	renderError(c, apperrors.From(err))
+}
+
+func Recover(c *gin.Context, recovered any) {
+	renderError(c, apperrors.Internal(fmt.Errorf("panic: %v", recovered)))
+	c.Abort()
+}
+
+func NoRoute(c *gin.Context) {
+	apperrors.Abort(c, apperrors.NotFound(fmt.Sprintf("Cannot find %s %s", c.Request.Method, c.Request.URL.Path)))
+}
+
+func renderError(c *gin.Context, appErr *apperrors.Error) {
+	if appErr.Code == apperrors.CodeInternal {
+		logging.Package(logging.FromContext(c), "http").Error("request failed", "error", appErr.Err)
+	}
+
+	c.JSON(appErr.Status, models.CustomResponse{
+		Status:  "Failed",
+		Code:    appErr.Code,
+		Message: appErr.Message,
+		Details: appErr.Details,
+		Data:    nil,
+	})
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/hamid-nazari/tours-in-go/internal/logging"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+func RequestID(c *gin.Context) {
+	requestId := c.GetHeader(RequestIDHeader)
+	if !validRequestID(requestId) {
+		requestId = uuid.New().String()
+	}
+
+	c.Set("requestId", requestId)
+	c.Header(RequestIDHeader, requestId)
+	c.Next()
+}
+
+func validRequestID(requestId string) bool {
+	if requestId == "" || len(requestId) > 128 {
+		return false
+	}
+	for _, r := range requestId {
+		if r < '!' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func RequestLogger(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		attrs := []any{
+			"requestId", c.GetString("requestId"),
+			"method", c.Request.Method,
+			"route", route,
+		}
+		if spanContext := trace.SpanContextFromContext(c.Request.Context()); spanContext.IsValid() {
+			attrs = append(attrs, "traceId", spanContext.TraceID().String(), "spanId", spanContext.SpanID().String())
+		}
+		logging.SetRequestLogger(c, logger.With(attrs...))
+
+		c.Next()
+
+		attrs = []any{
+			"path", c.Request.URL.Path,
+			"status", c.Writer.Status(),
+			"durationMs", time.Since(start).Milliseconds(),
+			"bytes", c.Writer.Size(),
+			"clientIp", c.ClientIP(),
+		}
+		if len(c.Errors) > 0 && c.Writer.Status() < 500 {
+			attrs = append(attrs, "error", c.Errors.Last().Error())
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case c.Writer.Status() >= 500:
+			level = slog.LevelError
+		case c.Writer.Status() >= 400:
+			level = slog.LevelWarn
+		}
+
+		logging.Package(logging.FromContext(c), "http").Log(c, level, "request completed", attrs...)
+	}
+}
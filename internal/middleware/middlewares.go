@@ -1,6 +1,10 @@
 package middleware
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"github.com/hamid-nazari/tours-in-go/internal/services"
+)
 
 func AliasTopTours(c *gin.Context) {
 	c.Request.URL.Query().Add("limit", "5")
@@ -8,3 +12,19 @@ func AliasTopTours(c *gin.Context) {
 	c.Request.URL.Query().Add("fields", "name,price,ratingsAverage,summary,difficulty")
 	c.Next()
 }
+
+func ScopeTours(c *gin.Context) {
+	scope := services.TourScope{}
+
+	if currentUser, ok := c.Get("user"); ok && currentUser.(*models.User).Role == "admin" {
+		scope.IncludeSecret = c.Query("includeSecret") == "true"
+	}
+
+	services.SetTourScope(c, scope)
+	c.Next()
+}
+
+func IncludeSecretTours(c *gin.Context) {
+	services.SetTourScope(c, services.TourScope{IncludeSecret: true})
+	c.Next()
+}
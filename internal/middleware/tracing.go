@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+var untracedRoutes = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+func Tracing(service string) gin.HandlerFunc {
+	return otelgin.Middleware(service, otelgin.WithGinFilter(func(c *gin.Context) bool {
+		return !untracedRoutes[c.FullPath()]
+	}))
+}
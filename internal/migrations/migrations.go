@@ -0,0 +1,291 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const namespaceExistsCode = 48
+
+func All() []Migration {
+	return []Migration{
+		{
+			Version: 1,
+			Name:    "create_user_indexes",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				return createIndexes(ctx, db.Collection("users"), []mongo.IndexModel{
+					{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetName("id_unique").SetUnique(true)},
+					{Keys: bson.D{{Key: "email", Value: 1}}, Options: options.Index().SetName("email_unique").SetUnique(true)},
+					{
+						Keys: bson.D{{Key: "passwordresettoken", Value: 1}},
+						Options: options.Index().SetName("passwordresettoken").
+							SetPartialFilterExpression(bson.M{"passwordresettoken": bson.M{"$gt": ""}}),
+					},
+				})
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				return dropIndexes(ctx, db.Collection("users"), "id_unique", "email_unique", "passwordresettoken")
+			},
+		},
+		{
+			Version: 2,
+			Name:    "create_tour_indexes",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				return createIndexes(ctx, db.Collection("tours"), []mongo.IndexModel{
+					{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetName("id_unique").SetUnique(true)},
+					{
+						Keys: bson.D{{Key: "slug", Value: 1}},
+						Options: options.Index().SetName("slug_unique").SetUnique(true).
+							SetPartialFilterExpression(bson.M{"slug": bson.M{"$gt": ""}}),
+					},
+					{Keys: bson.D{{Key: "previousslugs", Value: 1}}, Options: options.Index().SetName("previousslugs")},
+					{Keys: bson.D{{Key: "guides", Value: 1}}, Options: options.Index().SetName("guides")},
+					{
+						Keys: bson.D{{Key: "accesstoken", Value: 1}},
+						Options: options.Index().SetName("accesstoken").
+							SetPartialFilterExpression(bson.M{"accesstoken": bson.M{"$gt": ""}}),
+					},
+				})
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				return dropIndexes(ctx, db.Collection("tours"), "id_unique", "slug_unique", "previousslugs", "guides", "accesstoken")
+			},
+		},
+		{
+			Version: 3,
+			Name:    "create_review_and_booking_indexes",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				err := createIndexes(ctx, db.Collection("reviews"), []mongo.IndexModel{
+					{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetName("id_unique").SetUnique(true)},
+					{
+						Keys:    bson.D{{Key: "tour.id", Value: 1}, {Key: "status", Value: 1}, {Key: "createdat", Value: -1}},
+						Options: options.Index().SetName("tour_status_createdat"),
+					},
+					{
+						Keys:    bson.D{{Key: "status", Value: 1}, {Key: "createdat", Value: 1}},
+						Options: options.Index().SetName("status_createdat"),
+					},
+				})
+				if err != nil {
+					return err
+				}
+
+				return createIndexes(ctx, db.Collection("bookings"), []mongo.IndexModel{
+					{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetName("id_unique").SetUnique(true)},
+					{
+						Keys:    bson.D{{Key: "tour.id", Value: 1}, {Key: "user.id", Value: 1}},
+						Options: options.Index().SetName("tour_user"),
+					},
+				})
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				if err := dropIndexes(ctx, db.Collection("reviews"), "id_unique", "tour_status_createdat", "status_createdat"); err != nil {
+					return err
+				}
+				return dropIndexes(ctx, db.Collection("bookings"), "id_unique", "tour_user")
+			},
+		},
+		{
+			Version: 4,
+			Name:    "convert_embedded_tour_guides_to_ids",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				_, err := db.Collection("tours").UpdateMany(ctx,
+					bson.M{"guides": bson.M{"$type": "object"}},
+					mongo.Pipeline{
+						{{Key: "$set", Value: bson.M{"guides": bson.M{"$map": bson.M{
+							"input": "$guides",
+							"as":    "guide",
+							"in": bson.M{"$cond": bson.A{
+								bson.M{"$eq": bson.A{bson.M{"$type": "$$guide"}, "object"}},
+								"$$guide.id",
+								"$$guide",
+							}},
+						}}}}},
+					},
+				)
+				if err != nil {
+					return fmt.Errorf("failed to convert tour guides: %v", err)
+				}
+				return nil
+			},
+		},
+		{
+			Version: 5,
+			Name:    "publish_reviews_without_status",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				_, err := db.Collection("reviews").UpdateMany(ctx,
+					bson.M{"status": bson.M{"$exists": false}},
+					bson.M{"$set": bson.M{"status": "published"}},
+				)
+				if err != nil {
+					return fmt.Errorf("failed to set review status: %v", err)
+				}
+				return nil
+			},
+		},
+		{
+			Version: 6,
+			Name:    "create_realtime_events_collection",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				err := db.CreateCollection(ctx, "realtime_events", options.CreateCollection().SetCapped(true).SetSizeInBytes(16<<20).SetMaxDocuments(10000))
+				if err != nil && !isNamespaceExists(err) {
+					return fmt.Errorf("failed to create realtime_events: %v", err)
+				}
+				return nil
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				if err := db.Collection("realtime_events").Drop(ctx); err != nil {
+					return fmt.Errorf("failed to drop realtime_events: %v", err)
+				}
+				return nil
+			},
+		},
+		{
+			Version: 7,
+			Name:    "create_webhook_indexes",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				err := createIndexes(ctx, db.Collection("webhook_subscriptions"), []mongo.IndexModel{
+					{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetName("id_unique").SetUnique(true)},
+					{Keys: bson.D{{Key: "events", Value: 1}, {Key: "active", Value: 1}}, Options: options.Index().SetName("events_active")},
+				})
+				if err != nil {
+					return err
+				}
+				return createIndexes(ctx, db.Collection("webhook_deliveries"), []mongo.IndexModel{
+					{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetName("id_unique").SetUnique(true)},
+					{Keys: bson.D{{Key: "status", Value: 1}, {Key: "nextattemptat", Value: 1}}, Options: options.Index().SetName("status_nextattemptat")},
+					{Keys: bson.D{{Key: "subscriptionid", Value: 1}, {Key: "createdat", Value: -1}}, Options: options.Index().SetName("subscriptionid_createdat")},
+				})
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				if err := dropIndexes(ctx, db.Collection("webhook_subscriptions"), "id_unique", "events_active"); err != nil {
+					return err
+				}
+				return dropIndexes(ctx, db.Collection("webhook_deliveries"), "id_unique", "status_nextattemptat", "subscriptionid_createdat")
+			},
+		},
+		{
+			Version: 8,
+			Name:    "create_outbox_indexes",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				return createIndexes(ctx, db.Collection("outbox"), []mongo.IndexModel{
+					{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetName("id_unique").SetUnique(true)},
+					{Keys: bson.D{{Key: "status", Value: 1}, {Key: "nextattemptat", Value: 1}}, Options: options.Index().SetName("status_nextattemptat")},
+					{
+						Keys: bson.D{{Key: "deliveredat", Value: 1}},
+						Options: options.Index().SetName("deliveredat_ttl").
+							SetExpireAfterSeconds(7 * 24 * 60 * 60).
+							SetPartialFilterExpression(bson.M{"status": "delivered"}),
+					},
+				})
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				return dropIndexes(ctx, db.Collection("outbox"), "id_unique", "status_nextattemptat", "deliveredat_ttl")
+			},
+		},
+		{
+			Version: 9,
+			Name:    "create_job_indexes",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				err := createIndexes(ctx, db.Collection("jobs"), []mongo.IndexModel{
+					{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetName("id_unique").SetUnique(true)},
+					{
+						Keys: bson.D{{Key: "activekey", Value: 1}},
+						Options: options.Index().SetName("activekey_unique").SetUnique(true).
+							SetPartialFilterExpression(bson.M{"activekey": bson.M{"$gt": ""}}),
+					},
+					{Keys: bson.D{{Key: "status", Value: 1}, {Key: "runat", Value: 1}}, Options: options.Index().SetName("status_runat")},
+					{Keys: bson.D{{Key: "type", Value: 1}, {Key: "status", Value: 1}, {Key: "createdat", Value: -1}}, Options: options.Index().SetName("type_status_createdat")},
+					{
+						Keys: bson.D{{Key: "finishedat", Value: 1}},
+						Options: options.Index().SetName("finishedat_ttl").
+							SetExpireAfterSeconds(7 * 24 * 60 * 60).
+							SetPartialFilterExpression(bson.M{"status": "succeeded"}),
+					},
+				})
+				if err != nil {
+					return err
+				}
+				return createIndexes(ctx, db.Collection("job_schedules"), []mongo.IndexModel{
+					{Keys: bson.D{{Key: "name", Value: 1}}, Options: options.Index().SetName("name_unique").SetUnique(true)},
+				})
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				if err := dropIndexes(ctx, db.Collection("jobs"), "id_unique", "activekey_unique", "status_runat", "type_status_createdat", "finishedat_ttl"); err != nil {
+					return err
+				}
+				return dropIndexes(ctx, db.Collection("job_schedules"), "name_unique")
+			},
+		},
+		{
+			Version: 10,
+			Name:    "create_audit_log_indexes",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				return createIndexes(ctx, db.Collection("audit_log"), []mongo.IndexModel{
+					{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetName("id_unique").SetUnique(true)},
+					{Keys: bson.D{{Key: "createdat", Value: -1}}, Options: options.Index().SetName("createdat")},
+					{Keys: bson.D{{Key: "actorid", Value: 1}, {Key: "createdat", Value: -1}}, Options: options.Index().SetName("actorid_createdat")},
+					{Keys: bson.D{{Key: "action", Value: 1}, {Key: "createdat", Value: -1}}, Options: options.Index().SetName("action_createdat")},
+					{Keys: bson.D{{Key: "targettype", Value: 1}, {Key: "targetid", Value: 1}, {Key: "createdat", Value: -1}}, Options: options.Index().SetName("target_createdat")},
+				})
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				return dropIndexes(ctx, db.Collection("audit_log"), "id_unique", "createdat", "actorid_createdat", "action_createdat", "target_createdat")
+			},
+		},
+		{
+			Version: 11,
+			Name:    "default_null_review_votes",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				_, err := db.Collection("reviews").UpdateMany(ctx,
+					bson.M{"votes": nil},
+					bson.M{"$set": bson.M{"votes": bson.A{}}},
+				)
+				if err != nil {
+					return fmt.Errorf("failed to default review votes: %v", err)
+				}
+				return nil
+			},
+		},
+		{
+			Version: 12,
+			Name:    "default_null_review_reports",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				_, err := db.Collection("reviews").UpdateMany(ctx,
+					bson.M{"reports": nil},
+					bson.M{"$set": bson.M{"reports": bson.A{}}},
+				)
+				if err != nil {
+					return fmt.Errorf("failed to default review reports: %v", err)
+				}
+				return nil
+			},
+		},
+	}
+}
+
+func createIndexes(ctx context.Context, collection *mongo.Collection, indexes []mongo.IndexModel) error {
+	if _, err := collection.Indexes().CreateMany(ctx, indexes); err != nil {
+		return fmt.Errorf("failed to create indexes on %s: %v", collection.Name(), err)
+	}
+	return nil
+}
+
+func isNamespaceExists(err error) bool {
+	var serverErr mongo.ServerError
+	return errors.As(err, &serverErr) && serverErr.HasErrorCode(namespaceExistsCode)
+}
+
+func dropIndexes(ctx context.Context, collection *mongo.Collection, names ...string) error {
+	for _, name := range names {
+		if _, err := collection.Indexes().DropOne(ctx, name); err != nil {
+			return fmt.Errorf("failed to drop index %s on %s: %v", name, collection.Name(), err)
+		}
+	}
+	return nil
+}
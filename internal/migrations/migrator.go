@@ -0,0 +1,227 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hamid-nazari/tours-in-go/internal/logging"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	appliedCollection = "migrations"
+	lockCollection    = "migration_locks"
+	lockId            = "migrations"
+)
+
+var (
+	ErrLocked       = errors.New("migrations are locked by another instance")
+	ErrIrreversible = errors.New("migration cannot be reverted")
+)
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(ctx context.Context, db *mongo.Database) error
+	Down    func(ctx context.Context, db *mongo.Database) error
+}
+
+type AppliedMigration struct {
+	Version   int       `bson:"version" json:"version"`
+	Name      string    `bson:"name" json:"name"`
+	AppliedAt time.Time `bson:"appliedAt" json:"appliedAt"`
+}
+
+type Status struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"appliedAt,omitempty"`
+}
+
+type Migrator struct {
+	db         *mongo.Database
+	migrations []Migration
+	owner      string
+	lockTTL    time.Duration
+	lockPoll   time.Duration
+	logger     *slog.Logger
+}
+
+func NewMigrator(db *mongo.Database, migrations []Migration) *Migrator {
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	hostname, _ := os.Hostname()
+
+	return &Migrator{
+		db:         db,
+		migrations: sorted,
+		owner:      fmt.Sprintf("%s-%s", hostname, uuid.New().String()),
+		lockTTL:    10 * time.Minute,
+		lockPoll:   2 * time.Second,
+		logger:     logging.Package(slog.Default(), "migrations"),
+	}
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []Status
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if record, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &record.AppliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	if err := m.lock(ctx); err != nil {
+		return 0, err
+	}
+	defer m.unlock(ctx)
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		m.logger.Info("applying migration", "version", migration.Version, "name", migration.Name)
+		if err := migration.Up(ctx, m.db); err != nil {
+			return count, fmt.Errorf("migration %d_%s failed: %v", migration.Version, migration.Name, err)
+		}
+
+		_, err := m.db.Collection(appliedCollection).InsertOne(ctx, AppliedMigration{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			AppliedAt: time.Now().UTC(),
+		})
+		if err != nil {
+			return count, fmt.Errorf("failed to record migration %d_%s: %v", migration.Version, migration.Name, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+func (m *Migrator) UpWhenUnlocked(ctx context.Context, timeout time.Duration) (int, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		applied, err := m.Up(ctx)
+		if !errors.Is(err, ErrLocked) {
+			return applied, err
+		}
+		if !time.Now().Before(deadline) {
+			return 0, fmt.Errorf("gave up after %s: %v", timeout, err)
+		}
+
+		m.logger.Info("waiting for migration lock", "timeout", timeout)
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(m.lockPoll):
+		}
+	}
+}
+
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	if err := m.lock(ctx); err != nil {
+		return 0, err
+	}
+	defer m.unlock(ctx)
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := len(m.migrations) - 1; i >= 0 && count < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		if migration.Down == nil {
+			return count, fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, ErrIrreversible)
+		}
+
+		m.logger.Info("reverting migration", "version", migration.Version, "name", migration.Name)
+		if err := migration.Down(ctx, m.db); err != nil {
+			return count, fmt.Errorf("reverting migration %d_%s failed: %v", migration.Version, migration.Name, err)
+		}
+
+		_, err := m.db.Collection(appliedCollection).DeleteOne(ctx, bson.M{"version": migration.Version})
+		if err != nil {
+			return count, fmt.Errorf("failed to unrecord migration %d_%s: %v", migration.Version, migration.Name, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int]AppliedMigration, error) {
+	cursor, err := m.db.Collection(appliedCollection).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %v", err)
+	}
+
+	var records []AppliedMigration
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %v", err)
+	}
+
+	applied := map[int]AppliedMigration{}
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+func (m *Migrator) lock(ctx context.Context) error {
+	now := time.Now().UTC()
+
+	_, err := m.db.Collection(lockCollection).UpdateOne(ctx,
+		bson.M{"_id": lockId, "$or": bson.A{
+			bson.M{"expiresAt": bson.M{"$lt": now}},
+			bson.M{"owner": m.owner},
+		}},
+		bson.M{"$set": bson.M{"owner": m.owner, "lockedAt": now, "expiresAt": now.Add(m.lockTTL)}},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrLocked
+	}
+	if err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %v", err)
+	}
+	return nil
+}
+
+func (m *Migrator) unlock(ctx context.Context) {
+	_, err := m.db.Collection(lockCollection).DeleteOne(ctx, bson.M{"_id": lockId, "owner": m.owner})
+	if err != nil {
+		m.logger.Error("failed to release migration lock", "error", err)
+	}
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"encoding/json"
+	"log/slog"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -8,8 +10,10 @@ import (
 )
 
 type CustomResponse struct {
-	Status  string      `json:"success"`
+	Status  string      `json:"status"`
+	Code    string      `json:"code,omitempty"`
 	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
 	Data    interface{} `json:"data"`
 }
 
@@ -32,6 +36,14 @@ type User struct {
 	Active                   bool      `json:"active"`
 }
 
+func (u User) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", u.Id),
+		slog.String("email", u.Email),
+		slog.String("role", u.Role),
+	)
+}
+
 func NewUser() *User {
 	return &User{
 		Id:                uuid.New().String(),
@@ -46,51 +58,102 @@ type Tour struct {
 	Id             string      `json:"id"`
 	Name           string      `json:"name" validate:"required" min:"10" max:"50"`
 	Slug           string      `json:"slug"`
+	PreviousSlugs  []string    `json:"previousSlugs,omitempty"`
 	Duration       string      `json:"duration" validate:"required"`
 	Difficulty     string      `json:"difficulty"`
 	Price          float64     `json:"price" validate:"required"`
 	MaxGroupSize   int         `json:"maxGroupSize" validate:"required"`
 	RatingsAvg     float64     `json:"ratingAvg" validate:"required" default:"4.5" min:"1" max:"5"`
-	RatingQuantity int         `json:"ratingQuantity" validate:"required" default:"0"`
+	RatingQuantity int         `json:"ratingQuantity" validate:"gte=0" default:"0"`
 	ImageCover     string      `json:"imageCover"`
 	Images         []string    `json:"images"`
 	CreatedAt      time.Time   `json:"createdAt" default:"time.Now()"`
 	StartDates     []time.Time `json:"startDates"`
 	SecretTour     bool        `json:"secretTour" default:"false"`
+	AccessToken    string      `json:"-"`
 	Summary        string      `json:"summary" validate:"required"`
 	Description    string      `json:"description"`
 	StartLocation  string      `json:"startLocation"`
 	Locations      []string    `json:"locations"`
-	Guides         []User      `json:"guides"`
+	Guides         []string    `json:"guides"`
+	GuideProfiles  []Guide     `json:"guideProfiles,omitempty" bson:"guideprofiles,omitempty"`
+}
+
+type Guide struct {
+	Id    string `json:"id"`
+	Name  string `json:"name"`
+	Photo string `json:"photo"`
+	Role  string `json:"role"`
 }
 
 func NewTour() *Tour {
 	return &Tour{
-		Id: uuid.New().String(),
+		Id:        uuid.New().String(),
+		CreatedAt: time.Now(),
 	}
 }
 
+const (
+	ReviewStatusPending   = "pending"
+	ReviewStatusPublished = "published"
+	ReviewStatusRejected  = "rejected"
+)
+
 type Review struct {
-	Id     string `json:"id"`
-	Review string `json:"review" validate:"required" min:"10" max:"50"`
-	Rating int    `json:"rating" min:"1" max:"5"`
-	Tour   Tour   `json:"tour"`
-	User   User   `json:"user"`
+	Id               string         `json:"id"`
+	Review           string         `json:"review" validate:"required" min:"10" max:"50"`
+	Rating           int            `json:"rating" min:"1" max:"5"`
+	Tour             Tour           `json:"tour"`
+	User             User           `json:"user"`
+	Status           string         `json:"status" validate:"oneof=pending published rejected"`
+	ModerationReason string         `json:"moderationReason,omitempty"`
+	ModeratedBy      string         `json:"moderatedBy,omitempty"`
+	ModeratedAt      time.Time      `json:"moderatedAt,omitempty"`
+	Reports          []ReviewReport `json:"reports,omitempty"`
+	Reply            *ReviewReply   `json:"reply,omitempty"`
+	Votes            []ReviewVote   `json:"-"`
+	HelpfulCount     int            `json:"helpfulCount"`
+	UnhelpfulCount   int            `json:"unhelpfulCount"`
+	CreatedAt        time.Time      `json:"createdAt"`
 }
 
 func NewReview() *Review {
 	return &Review{
-		Id: uuid.New().String(),
+		Id:        uuid.New().String(),
+		Status:    ReviewStatusPending,
+		CreatedAt: time.Now(),
+		Votes:     []ReviewVote{},
+		Reports:   []ReviewReport{},
 	}
 }
 
+type ReviewReply struct {
+	GuideId   string    `json:"guideId"`
+	GuideName string    `json:"guideName"`
+	Reply     string    `json:"reply" validate:"required,max=1000"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type ReviewVote struct {
+	UserId  string `json:"userId"`
+	Helpful bool   `json:"helpful"`
+}
+
+type ReviewReport struct {
+	UserId    string    `json:"userId"`
+	Reason    string    `json:"reason" validate:"required"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
 type Booking struct {
-	Id        string    `json:"id"`
-	Tour      Tour      `json:"tour"`
-	User      User      `json:"user"`
-	Price     float64   `json:"price" validate:"required"`
-	CreatedAt time.Time `json:"createdAt" default:"time.Now()"`
-	Paid      bool      `json:"paid" default:"true"`
+	Id                string    `json:"id"`
+	Tour              Tour      `json:"tour"`
+	User              User      `json:"user"`
+	Price             float64   `json:"price" validate:"required"`
+	CreatedAt         time.Time `json:"createdAt" default:"time.Now()"`
+	Paid              bool      `json:"paid" default:"true"`
+	CheckoutExpiresAt time.Time `json:"checkoutExpiresAt,omitempty"`
 }
 
 func NewBooking() *Booking {
@@ -98,3 +161,169 @@ func NewBooking() *Booking {
 		Id: uuid.New().String(),
 	}
 }
+
+const (
+	WebhookEventBookingCreated   = "booking.created"
+	WebhookEventBookingCancelled = "booking.cancelled"
+	WebhookEventTourCreated      = "tour.created"
+	WebhookEventTourUpdated      = "tour.updated"
+	WebhookEventTourDeleted      = "tour.deleted"
+	WebhookEventPing             = "webhook.ping"
+)
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+type WebhookSubscription struct {
+	Id                  string    `json:"id"`
+	URL                 string    `json:"url" validate:"required,http_url"`
+	Events              []string  `json:"events" validate:"required,min=1,dive,oneof=booking.created booking.cancelled tour.created tour.updated tour.deleted"`
+	Secret              string    `json:"-"`
+	Active              bool      `json:"active"`
+	ConsecutiveFailures int       `json:"consecutiveFailures"`
+	DisabledAt          time.Time `json:"disabledAt,omitempty"`
+	DisabledReason      string    `json:"disabledReason,omitempty"`
+	CreatedBy           string    `json:"createdBy"`
+	CreatedAt           time.Time `json:"createdAt"`
+}
+
+func NewWebhookSubscription() *WebhookSubscription {
+	return &WebhookSubscription{
+		Id:        uuid.New().String(),
+		Active:    true,
+		CreatedAt: time.Now(),
+	}
+}
+
+type WebhookDelivery struct {
+	Id             string          `json:"id"`
+	SubscriptionId string          `json:"subscriptionId"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"nextAttemptAt,omitempty"`
+	LastStatusCode int             `json:"lastStatusCode,omitempty"`
+	LastError      string          `json:"lastError,omitempty"`
+	ReplayOf       string          `json:"replayOf,omitempty"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	DeliveredAt    time.Time       `json:"deliveredAt,omitempty"`
+}
+
+func NewWebhookDelivery() *WebhookDelivery {
+	now := time.Now()
+	return &WebhookDelivery{
+		Id:            uuid.New().String(),
+		Status:        WebhookDeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+}
+
+const (
+	OutboxPending   = "pending"
+	OutboxDelivered = "delivered"
+	OutboxFailed    = "failed"
+)
+
+type OutboxMessage struct {
+	Id            string          `json:"id"`
+	Type          string          `json:"type"`
+	AggregateId   string          `json:"aggregateId"`
+	Data          json.RawMessage `json:"data"`
+	OccurredAt    time.Time       `json:"occurredAt"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	Completed     []string        `json:"completed"`
+	NextAttemptAt time.Time       `json:"nextAttemptAt"`
+	LastError     string          `json:"lastError,omitempty"`
+	DeliveredAt   time.Time       `json:"deliveredAt,omitempty"`
+}
+
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobDead      = "dead"
+)
+
+type Job struct {
+	Id          string          `json:"id"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"`
+	UniqueKey   string          `json:"uniqueKey,omitempty"`
+	ActiveKey   string          `json:"-"`
+	Schedule    string          `json:"schedule,omitempty"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"maxAttempts"`
+	RunAt       time.Time       `json:"runAt"`
+	LockedUntil time.Time       `json:"lockedUntil,omitempty"`
+	LastError   string          `json:"lastError,omitempty"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	StartedAt   time.Time       `json:"startedAt,omitempty"`
+	FinishedAt  time.Time       `json:"finishedAt,omitempty"`
+}
+
+func NewJob(jobType string) *Job {
+	now := time.Now()
+	return &Job{
+		Id:        uuid.New().String(),
+		Type:      jobType,
+		Status:    JobQueued,
+		RunAt:     now,
+		CreatedAt: now,
+	}
+}
+
+const (
+	AuditSuccess = "success"
+	AuditFailure = "failure"
+)
+
+const (
+	AuditLogin                = "auth.login"
+	AuditPasswordChange       = "auth.password_change"
+	AuditPasswordResetRequest = "auth.password_reset_request"
+	AuditPasswordReset        = "auth.password_reset"
+	AuditUserCreate           = "user.create"
+	AuditUserUpdate           = "user.update"
+	AuditUserDelete           = "user.delete"
+	AuditUserDeleteAll        = "user.delete_all"
+	AuditUserRoleChange       = "user.role_change"
+	AuditTourCreate           = "tour.create"
+	AuditTourUpdate           = "tour.update"
+	AuditTourDelete           = "tour.delete"
+	AuditReviewModerate       = "review.moderate"
+	AuditWebhookCreate        = "webhook.create"
+	AuditWebhookUpdate        = "webhook.update"
+	AuditWebhookDelete        = "webhook.delete"
+	AuditJobRetry             = "job.retry"
+)
+
+type AuditChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+type AuditEntry struct {
+	Id         string        `json:"id"`
+	Action     string        `json:"action"`
+	Outcome    string        `json:"outcome"`
+	Reason     string        `json:"reason,omitempty"`
+	ActorId    string        `json:"actorId,omitempty"`
+	ActorEmail string        `json:"actorEmail,omitempty"`
+	ActorRole  string        `json:"actorRole,omitempty"`
+	TargetType string        `json:"targetType"`
+	TargetId   string        `json:"targetId,omitempty"`
+	Changes    []AuditChange `json:"changes,omitempty"`
+	Channel    string        `json:"channel"`
+	IP         string        `json:"ip,omitempty"`
+	UserAgent  string        `json:"userAgent,omitempty"`
+	RequestId  string        `json:"requestId,omitempty"`
+	CreatedAt  time.Time     `json:"createdAt"`
+}
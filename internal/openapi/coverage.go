@@ -0,0 +1,38 @@
+package openapi
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Undocumented(document *Document, registered gin.RoutesInfo) []string {
+	var missing []string
+
+	for _, route := range registered {
+		if !document.Documents(route.Method, route.Path) {
+			missing = append(missing, route.Method+" "+route.Path)
+		}
+	}
+
+	sort.Strings(missing)
+	return missing
+}
+
+func (d *Document) Documents(method, ginPath string) bool {
+	path, _ := pathParameters(ginPath)
+	item, ok := d.Paths[path]
+	return ok && item.get(method) != nil
+}
+
+func Describe(value any) *Schema {
+	t := reflect.TypeOf(value)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return newSchemaRegistry().schemaFor(t)
+	}
+	return newSchemaRegistry().structSchema(t)
+}
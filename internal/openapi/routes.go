@@ -0,0 +1,155 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/hamid-nazari/tours-in-go/internal/health"
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+)
+
+type updatePasswordRequest struct {
+	CurrentPassword    string `json:"currentPassword" validate:"required"`
+	NewPassword        string `json:"newPassword" validate:"required,min=8"`
+	NewPasswordConfirm string `json:"newPasswordConfirm" validate:"required"`
+}
+
+type updateUserForm struct {
+	Name  string `json:"name" validate:"required"`
+	Photo []byte `json:"photo" validate:"required"`
+}
+
+type authPayload struct {
+	Token string      `json:"token"`
+	User  models.User `json:"user"`
+}
+
+type resetLink struct {
+	ResetURL string `json:"reset_url"`
+}
+
+type shareLink struct {
+	ShareURL string `json:"shareUrl"`
+}
+
+type graphqlRequest struct {
+	Query         string         `json:"query" validate:"required"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+type graphqlResponse struct {
+	Data   map[string]any `json:"data,omitempty"`
+	Errors []graphqlError `json:"errors,omitempty"`
+}
+
+type graphqlError struct {
+	Message    string         `json:"message"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+type createdWebhook struct {
+	models.WebhookSubscription
+	Secret string `json:"secret"`
+}
+
+var includeSecret = Parameter{
+	Name:        "includeSecret",
+	In:          "query",
+	Description: "Include secret tours. Only honoured for admins.",
+	Schema:      &Schema{Type: "boolean", Default: false},
+}
+
+var accessToken = Parameter{
+	Name:        "accessToken",
+	In:          "query",
+	Description: "Share-link token that grants access to a secret tour.",
+	Schema:      &Schema{Type: "string"},
+}
+
+var auditQuery = []Parameter{
+	{Name: "actorId", In: "query", Description: "Only entries by this user", Schema: &Schema{Type: "string"}},
+	{Name: "action", In: "query", Description: "Only entries with this action, e.g. auth.login", Schema: &Schema{Type: "string"}},
+	{Name: "targetType", In: "query", Description: "Only entries about this kind of resource, e.g. tour", Schema: &Schema{Type: "string"}},
+	{Name: "targetId", In: "query", Description: "Only entries about this resource", Schema: &Schema{Type: "string"}},
+	{Name: "outcome", In: "query", Description: "Only entries with this outcome: success or failure", Schema: &Schema{Type: "string"}},
+	{Name: "from", In: "query", Description: "Only entries at or after this RFC 3339 time", Schema: &Schema{Type: "string", Format: "date-time"}},
+	{Name: "to", In: "query", Description: "Only entries before this RFC 3339 time", Schema: &Schema{Type: "string", Format: "date-time"}},
+}
+
+func routes() []route {
+	return []route{
+		{method: http.MethodGet, path: "/healthz", tag: "operations", summary: "Liveness probe", data: nil},
+		{method: http.MethodGet, path: "/readyz", tag: "operations", summary: "Readiness probe with dependency checks", description: "Responds with 503 and the failing checks when a dependency is down.", data: health.Report{}},
+		{method: http.MethodGet, path: "/metrics", tag: "operations", summary: "Prometheus metrics", contentType: "text/plain"},
+		{method: http.MethodGet, path: "/openapi.json", tag: "operations", summary: "This OpenAPI document", contentType: "application/json"},
+		{method: http.MethodGet, path: "/docs", tag: "operations", summary: "Interactive API documentation", contentType: "text/html"},
+
+		{method: http.MethodPost, path: "/api/v1/users/signup", tag: "auth", summary: "Create an account and receive a token", body: fields(models.User{}, "name", "email", "photo", "password", "passwordConfirm"), data: authPayload{}},
+		{method: http.MethodPost, path: "/api/v1/users/login", tag: "auth", summary: "Log in with email and password", body: fields(models.User{}, "email", "password"), data: authPayload{}},
+		{method: http.MethodPost, path: "/api/v1/users/logout", tag: "auth", summary: "Clear the JWT cookie", data: nil},
+		{method: http.MethodPost, path: "/api/v1/users/forgot-password", tag: "auth", summary: "Request a password reset link", body: fields(models.User{}, "email"), data: resetLink{}},
+		{method: http.MethodPost, path: "/api/v1/users/reset-password", tag: "auth", summary: "Reset a password with a reset token", body: fields(models.User{}, "password"), data: authPayload{}},
+		{method: http.MethodPatch, path: "/api/v1/users/update-password", tag: "auth", summary: "Change the current user's password", access: protected, body: updatePasswordRequest{}, data: authPayload{}},
+
+		{method: http.MethodGet, path: "/api/v1/users/:id/tours", tag: "tours", summary: "List the tours a guide leads", access: optionalAuth, query: []Parameter{includeSecret}, data: []models.Tour{}},
+		{method: http.MethodPatch, path: "/api/v1/users/update-me", tag: "users", summary: "Update the current user", description: "Not implemented yet.", access: protected, empty: true},
+		{method: http.MethodDelete, path: "/api/v1/users/delete-me", tag: "users", summary: "Deactivate the current user", description: "Not implemented yet.", access: protected, empty: true},
+		{method: http.MethodGet, path: "/api/v1/users/me", tag: "users", summary: "Get the current user", description: "Not implemented yet.", access: protected, empty: true},
+		{method: http.MethodPost, path: "/api/v1/users/", tag: "users", summary: "Create a user", access: protected, roles: []string{"admin"}, body: models.User{}, data: models.User{}},
+		{method: http.MethodGet, path: "/api/v1/users/", tag: "users", summary: "List users", access: protected, roles: []string{"admin"}, data: []models.User{}},
+		{method: http.MethodDelete, path: "/api/v1/users/", tag: "users", summary: "Delete every user", access: protected, roles: []string{"admin"}, data: nil},
+		{method: http.MethodGet, path: "/api/v1/users/:id", tag: "users", summary: "Get a user", access: protected, roles: []string{"admin"}, data: models.User{}},
+		{method: http.MethodPatch, path: "/api/v1/users/:id", tag: "users", summary: "Update a user's name and photo", access: protected, roles: []string{"admin"}, multipart: updateUserForm{}, data: models.User{}},
+		{method: http.MethodDelete, path: "/api/v1/users/:id", tag: "users", summary: "Delete a user", access: protected, roles: []string{"admin"}, data: nil},
+
+		{method: http.MethodPost, path: "/api/v1/tours/", tag: "tours", summary: "Create a tour", access: protected, roles: []string{"admin", "lead-guide"}, body: models.Tour{}, data: models.Tour{}},
+		{method: http.MethodGet, path: "/api/v1/tours/", tag: "tours", summary: "List tours", access: optionalAuth, query: []Parameter{includeSecret}, data: []models.Tour{}},
+		{method: http.MethodGet, path: "/api/v1/tours/:id", tag: "tours", summary: "Get a tour, including secret tours", access: protected, roles: []string{"admin", "lead-guide"}, data: models.Tour{}},
+		{method: http.MethodPatch, path: "/api/v1/tours/:id", tag: "tours", summary: "Update a tour", description: "Renaming a tour keeps its old slug as a redirect.", access: protected, roles: []string{"admin", "lead-guide"}, body: models.Tour{}, data: models.Tour{}},
+		{method: http.MethodDelete, path: "/api/v1/tours/:id", tag: "tours", summary: "Delete a tour", access: protected, roles: []string{"admin", "lead-guide"}, empty: true},
+		{method: http.MethodPost, path: "/api/v1/tours/:id/share-link", tag: "tours", summary: "Create an access link for a secret tour", access: protected, roles: []string{"admin", "lead-guide"}, data: shareLink{}},
+		{method: http.MethodDelete, path: "/api/v1/tours/:id/share-link", tag: "tours", summary: "Revoke a secret tour's access link", access: protected, roles: []string{"admin", "lead-guide"}, data: nil},
+		{method: http.MethodGet, path: "/api/v1/tours/shared/:token", tag: "tours", summary: "Get a secret tour through its access link", data: models.Tour{}},
+		{method: http.MethodGet, path: "/api/v1/tours/top-5-cheap", tag: "tours", summary: "List the best rated cheap tours", access: optionalAuth, data: []models.Tour{}},
+		{method: http.MethodGet, path: "/api/v1/tours/:id/events", tag: "tours", summary: "Stream live updates for a tour", description: "Server-Sent Events stream. The first event is the current availability; availability, price and review events follow as they happen. Comment lines are sent as heartbeats.", access: optionalAuth, query: []Parameter{includeSecret, accessToken}, contentType: "text/event-stream"},
+		{method: http.MethodGet, path: "/api/v1/tours/live", tag: "tours", summary: "Follow live tour updates over a WebSocket", description: "Upgrades to a WebSocket. Send {\"type\": \"subscribe\", \"tourId\": \"...\"}, with an optional \"accessToken\" for a shared secret tour, or {\"type\": \"unsubscribe\", \"tourId\": \"...\"}; the server replies with subscribed, unsubscribed or error messages and pushes availability, price and review events for followed tours.", access: optionalAuth, query: []Parameter{includeSecret}, empty: true},
+		{method: http.MethodGet, path: "/api/v1/tours/slug/:slug", tag: "tours", summary: "Get a tour by slug", description: "Old slugs redirect to the tour's current slug.", access: optionalAuth, data: models.Tour{}, redirect: true},
+
+		{method: http.MethodGet, path: "/api/v1/reviews/", tag: "reviews", summary: "List published reviews", query: []Parameter{{Name: "sort", In: "query", Description: "Sort order, for example helpful", Schema: &Schema{Type: "string"}}}, data: []models.Review{}},
+		{method: http.MethodGet, path: "/api/v1/reviews/:id", tag: "reviews", summary: "Get a published review", data: models.Review{}},
+		{method: http.MethodPost, path: "/api/v1/reviews/", tag: "reviews", summary: "Write a review", description: "Reviews may be held for moderation before they are published.", access: protected, query: []Parameter{accessToken}, body: fields(models.Review{}, "review", "rating", "tour.id").requiring("tour"), data: models.Review{}},
+		{method: http.MethodPatch, path: "/api/v1/reviews/:id", tag: "reviews", summary: "Edit a review", access: protected, body: fields(models.Review{}, "review", "rating").optional(), data: models.Review{}},
+		{method: http.MethodDelete, path: "/api/v1/reviews/:id", tag: "reviews", summary: "Delete a review", access: protected, data: nil},
+		{method: http.MethodPost, path: "/api/v1/reviews/:id/report", tag: "reviews", summary: "Report a review", access: protected, body: fields(models.ReviewReport{}, "reason"), data: nil},
+		{method: http.MethodPost, path: "/api/v1/reviews/:id/vote", tag: "reviews", summary: "Vote on whether a review was helpful", access: protected, body: fields(models.ReviewVote{}, "helpful").requiring("helpful"), data: models.Review{}},
+		{method: http.MethodDelete, path: "/api/v1/reviews/:id/vote", tag: "reviews", summary: "Remove a vote", access: protected, data: models.Review{}},
+		{method: http.MethodPut, path: "/api/v1/reviews/:id/reply", tag: "reviews", summary: "Reply to a review of your tour", access: protected, roles: []string{"guide", "lead-guide"}, body: fields(models.ReviewReply{}, "reply"), data: models.Review{}},
+		{method: http.MethodDelete, path: "/api/v1/reviews/:id/reply", tag: "reviews", summary: "Delete a reply", access: protected, roles: []string{"guide", "lead-guide", "admin"}, data: nil},
+		{method: http.MethodGet, path: "/api/v1/reviews/moderation", tag: "reviews", summary: "List reviews awaiting moderation", access: protected, roles: []string{"admin", "lead-guide"}, data: []models.Review{}},
+		{method: http.MethodPatch, path: "/api/v1/reviews/:id/moderate", tag: "reviews", summary: "Publish or reject a review", access: protected, roles: []string{"admin", "lead-guide"}, body: fields(models.Review{}, "status").requiring("status").with("reason", &Schema{Type: "string", Description: "Shown to the author when the review is rejected"}), data: models.Review{}},
+
+		{method: http.MethodGet, path: "/api/v1/bookings/checkout-session/:id", tag: "bookings", summary: "Create a Stripe checkout session for a tour", access: protected, query: []Parameter{accessToken}, data: map[string]any{}},
+
+		{method: http.MethodPost, path: "/api/v1/webhooks/", tag: "webhooks", summary: "Subscribe an endpoint to booking and tour events", description: "Payloads are signed with HMAC-SHA256. The X-Webhook-Signature header has the form t=<unix seconds>,v1=<hex digest of \"<t>.<body>\">. A secret is generated when none is given and is only returned by this call.", access: protected, roles: []string{"admin"}, body: fields(models.WebhookSubscription{}, "url", "events").with("secret", &Schema{Type: "string", Description: "Signing secret, generated when empty"}), data: createdWebhook{}},
+		{method: http.MethodGet, path: "/api/v1/webhooks/", tag: "webhooks", summary: "List webhook subscriptions", access: protected, roles: []string{"admin"}, data: []models.WebhookSubscription{}},
+		{method: http.MethodGet, path: "/api/v1/webhooks/:id", tag: "webhooks", summary: "Get a webhook subscription", access: protected, roles: []string{"admin"}, data: models.WebhookSubscription{}},
+		{method: http.MethodPatch, path: "/api/v1/webhooks/:id", tag: "webhooks", summary: "Update a webhook subscription", description: "Setting active to true re-enables a subscription that was disabled after repeated failures.", access: protected, roles: []string{"admin"}, body: fields(models.WebhookSubscription{}, "url", "events", "active").optional(), data: models.WebhookSubscription{}},
+		{method: http.MethodDelete, path: "/api/v1/webhooks/:id", tag: "webhooks", summary: "Delete a webhook subscription", access: protected, roles: []string{"admin"}, data: nil},
+		{method: http.MethodGet, path: "/api/v1/webhooks/:id/deliveries", tag: "webhooks", summary: "List recent deliveries for a subscription", access: protected, roles: []string{"admin"}, query: []Parameter{{Name: "status", In: "query", Description: "Only deliveries with this status: pending, succeeded or failed", Schema: &Schema{Type: "string"}}}, data: []models.WebhookDelivery{}},
+		{method: http.MethodPost, path: "/api/v1/webhooks/:id/ping", tag: "webhooks", summary: "Send a webhook.ping event to the endpoint", access: protected, roles: []string{"admin"}, data: models.WebhookDelivery{}},
+		{method: http.MethodPost, path: "/api/v1/webhooks/deliveries/:deliveryId/replay", tag: "webhooks", summary: "Deliver a past payload again", description: "Queues a new delivery with the original payload and event id.", access: protected, roles: []string{"admin"}, data: models.WebhookDelivery{}},
+
+		{method: http.MethodGet, path: "/api/v1/jobs/", tag: "jobs", summary: "List background jobs", description: "Returns the 100 most recent jobs. Dead jobs failed every attempt and stay until they are retried.", access: protected, roles: []string{"admin"}, query: []Parameter{
+			{Name: "status", In: "query", Description: "Only jobs with this status: queued, running, succeeded or dead", Schema: &Schema{Type: "string"}},
+			{Name: "type", In: "query", Description: "Only jobs of this type", Schema: &Schema{Type: "string"}},
+		}, data: []models.Job{}},
+		{method: http.MethodGet, path: "/api/v1/jobs/:id", tag: "jobs", summary: "Get a background job", access: protected, roles: []string{"admin"}, data: models.Job{}},
+		{method: http.MethodPost, path: "/api/v1/jobs/:id/retry", tag: "jobs", summary: "Run a queued or dead job again now", description: "Resets the attempt count. Fails with 409 when the job is running, already succeeded, or another job with the same unique key is active.", access: protected, roles: []string{"admin"}, data: models.Job{}},
+
+		{method: http.MethodGet, path: "/api/v1/audit/", tag: "audit", summary: "Search the audit log", description: "Returns matching entries newest first. Sensitive fields in before/after changes are redacted.", access: protected, roles: []string{"admin"}, query: append(auditQuery, Parameter{Name: "limit", In: "query", Description: "Maximum number of entries, 1 to 1000 (default 100)", Schema: &Schema{Type: "integer"}}), data: []models.AuditEntry{}},
+		{method: http.MethodGet, path: "/api/v1/audit/export", tag: "audit", summary: "Export the audit log as NDJSON", description: "Streams every matching entry, newest first, one JSON object per line.", access: protected, roles: []string{"admin"}, query: auditQuery, contentType: "application/x-ndjson"},
+
+		{method: http.MethodPost, path: "/graphql", tag: "graphql", summary: "Run a GraphQL query", description: "Responds with a standard GraphQL result instead of the CustomResponse envelope. Queries over the configured depth or complexity limits are rejected with the query_too_complex error code.", access: optionalAuth, body: graphqlRequest{}, data: graphqlResponse{}, unwrapped: true},
+	}
+}
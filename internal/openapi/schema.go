@@ -0,0 +1,327 @@
+package openapi
+
+import (
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type schemaRegistry struct {
+	schemas map[string]*Schema
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{schemas: map[string]*Schema{}}
+}
+
+func (r *schemaRegistry) of(value any) *Schema {
+	if value == nil {
+		return &Schema{Nullable: true}
+	}
+	if f, ok := value.(modelFields); ok {
+		return r.pick(f)
+	}
+	return r.schemaFor(reflect.TypeOf(value))
+}
+
+func (r *schemaRegistry) schemaFor(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == reflect.TypeOf(time.Time{}) {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+	if t == reflect.TypeOf(json.RawMessage{}) {
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "binary"}
+		}
+		return &Schema{Type: "array", Items: r.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schemaFor(t.Elem())}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.structSchema(t)
+		}
+		name := strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+		if _, ok := r.schemas[name]; !ok {
+			r.schemas[name] = &Schema{}
+			*r.schemas[name] = *r.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+
+	return &Schema{}
+}
+
+func (r *schemaRegistry) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	r.addFields(schema, t)
+	return schema
+}
+
+func (r *schemaRegistry) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, omitEmpty := jsonName(field)
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				r.addFields(schema, embedded)
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := r.schemaFor(field.Type)
+		if applyConstraints(property, field) && !omitEmpty {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = property
+	}
+}
+
+func jsonName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	name, options, _ := strings.Cut(tag, ",")
+	return name, strings.Contains(options, "omitempty")
+}
+
+func applyConstraints(schema *Schema, field reflect.StructField) bool {
+	required := false
+
+	for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+		name, value, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+		case "email":
+			schema.Format = "email"
+		case "oneof":
+			for _, option := range strings.Fields(value) {
+				schema.Enum = append(schema.Enum, option)
+			}
+		case "min", "gte":
+			setLowerBound(schema, value)
+		case "max", "lte":
+			setUpperBound(schema, value)
+		}
+	}
+
+	if value, ok := field.Tag.Lookup("min"); ok {
+		setLowerBound(schema, value)
+	}
+	if value, ok := field.Tag.Lookup("max"); ok {
+		setUpperBound(schema, value)
+	}
+	if value, ok := field.Tag.Lookup("default"); ok {
+		schema.Default = parseDefault(schema, value)
+	}
+
+	return required
+}
+
+func setLowerBound(schema *Schema, value string) {
+	bound, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return
+	}
+	switch schema.Type {
+	case "string":
+		length := int(bound)
+		schema.MinLength = &length
+	case "integer", "number":
+		schema.Minimum = &bound
+	}
+}
+
+func setUpperBound(schema *Schema, value string) {
+	bound, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return
+	}
+	switch schema.Type {
+	case "string":
+		length := int(bound)
+		schema.MaxLength = &length
+	case "integer", "number":
+		schema.Maximum = &bound
+	}
+}
+
+func parseDefault(schema *Schema, value string) any {
+	switch schema.Type {
+	case "boolean":
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	case "integer":
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return parsed
+		}
+	case "number":
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	case "string":
+		if schema.Format == "" {
+			return value
+		}
+	}
+	return nil
+}
+
+type modelFields struct {
+	model    any
+	names    []string
+	required []string
+	partial  bool
+	extra    map[string]*Schema
+}
+
+func fields(model any, names ...string) modelFields {
+	return modelFields{model: model, names: names}
+}
+
+func (f modelFields) requiring(names ...string) modelFields {
+	f.required = append(slices.Clone(f.required), names...)
+	return f
+}
+
+func (f modelFields) optional() modelFields {
+	f.partial = true
+	return f
+}
+
+func (f modelFields) with(name string, schema *Schema) modelFields {
+	extra := maps.Clone(f.extra)
+	if extra == nil {
+		extra = map[string]*Schema{}
+	}
+	extra[name] = schema
+	f.extra = extra
+	return f
+}
+
+func (f modelFields) missing() []string {
+	var missing []string
+	for _, name := range append(slices.Clone(f.names), f.required...) {
+		if _, ok := fieldByPath(reflect.TypeOf(f.model), name); !ok {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+func (r *schemaRegistry) pick(f modelFields) *Schema {
+	schema := r.pickFields(reflect.TypeOf(f.model), f.names)
+	if f.partial {
+		schema.Required = nil
+	}
+	for _, name := range f.required {
+		if !slices.Contains(schema.Required, name) {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	for name, property := range f.extra {
+		schema.Properties[name] = property
+	}
+	return schema
+}
+
+func (r *schemaRegistry) pickFields(t reflect.Type, names []string) *Schema {
+	t = indirect(t)
+	full := r.structSchema(t)
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	nested := map[string][]string{}
+	var parents []string
+	for _, name := range names {
+		if parent, child, ok := strings.Cut(name, "."); ok {
+			if _, seen := nested[parent]; !seen {
+				parents = append(parents, parent)
+			}
+			nested[parent] = append(nested[parent], child)
+			continue
+		}
+		if property, ok := full.Properties[name]; ok {
+			schema.Properties[name] = property
+			if slices.Contains(full.Required, name) {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+	}
+
+	for _, parent := range parents {
+		field, ok := fieldByPath(t, parent)
+		if !ok {
+			continue
+		}
+		schema.Properties[parent] = r.pickFields(field.Type, nested[parent])
+		if slices.Contains(full.Required, parent) {
+			schema.Required = append(schema.Required, parent)
+		}
+	}
+
+	return schema
+}
+
+func fieldByPath(t reflect.Type, path string) (reflect.StructField, bool) {
+	t = indirect(t)
+	if t.Kind() != reflect.Struct {
+		return reflect.StructField{}, false
+	}
+
+	name, rest, nested := strings.Cut(path, ".")
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if jsonField, _ := jsonName(field); !field.IsExported() || jsonField != name {
+			continue
+		}
+		if nested {
+			return fieldByPath(field.Type, rest)
+		}
+		return field, true
+	}
+	return reflect.StructField{}, false
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,271 @@
+package openapi
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hamid-nazari/tours-in-go/internal/apperrors"
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+)
+
+const (
+	public = iota
+	optionalAuth
+	protected
+)
+
+const bearerAuth = "bearerAuth"
+
+type route struct {
+	method      string
+	path        string
+	tag         string
+	summary     string
+	description string
+	access      int
+	roles       []string
+	query       []Parameter
+	body        any
+	multipart   any
+	data        any
+	contentType string
+	empty       bool
+	redirect    bool
+	unwrapped   bool
+}
+
+func Spec() *Document {
+	registry := newSchemaRegistry()
+
+	document := &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:       "Natours API",
+			Description: "Tours, reviews, users and bookings. Every JSON response is wrapped in the CustomResponse envelope.",
+			Version:     "1.0.0",
+		},
+		Servers: []Server{{URL: "/"}},
+		Tags: []Tag{
+			{Name: "auth", Description: "Sign up, log in and manage passwords"},
+			{Name: "users", Description: "User accounts"},
+			{Name: "tours", Description: "Tours and share links"},
+			{Name: "reviews", Description: "Reviews, votes, replies and moderation"},
+			{Name: "bookings", Description: "Checkout and bookings"},
+			{Name: "webhooks", Description: "Partner webhook subscriptions and their delivery log"},
+			{Name: "jobs", Description: "Background job queue, retries and the dead letter queue"},
+			{Name: "audit", Description: "Audit trail of logins, password changes and admin changes"},
+			{Name: "graphql", Description: "GraphQL queries over tours, users, reviews and bookings"},
+			{Name: "operations", Description: "Health, metrics and documentation"},
+		},
+		Paths: map[string]*PathItem{},
+		Components: Components{
+			Schemas: registry.schemas,
+			SecuritySchemes: map[string]*SecurityScheme{
+				bearerAuth: {
+					Type:         "http",
+					Scheme:       "bearer",
+					BearerFormat: "JWT",
+					Description:  "Token returned by signup, login and password endpoints",
+				},
+			},
+		},
+	}
+
+	registry.of(models.CustomResponse{})
+	document.Components.Schemas["ErrorResponse"] = &Schema{
+		AllOf: []*Schema{
+			{Ref: "#/components/schemas/CustomResponse"},
+			{
+				Type: "object",
+				Properties: map[string]*Schema{
+					"status": {Type: "string", Enum: []any{"Failed"}},
+					"code": {Type: "string", Enum: []any{
+						apperrors.CodeBadRequest, apperrors.CodeValidation, apperrors.CodeUnauthorized,
+						apperrors.CodeForbidden, apperrors.CodeNotFound, apperrors.CodeConflict, apperrors.CodeInternal,
+					}},
+					"details": {Type: "array", Items: registry.of(apperrors.FieldError{}), Description: "Field errors, present when code is validation_failed"},
+					"data":    {Nullable: true},
+				},
+				Required: []string{"status", "code", "message"},
+			},
+		},
+	}
+
+	for _, r := range routes() {
+		path, parameters := pathParameters(r.path)
+
+		item, ok := document.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			document.Paths[path] = item
+		}
+		item.set(r.method, r.operation(registry, parameters))
+	}
+
+	return document
+}
+
+func (r route) operation(registry *schemaRegistry, parameters []Parameter) *Operation {
+	operation := &Operation{
+		Tags:        []string{r.tag},
+		Summary:     r.summary,
+		Description: r.description,
+		OperationID: operationID(r.method, r.path),
+		Parameters:  append(parameters, r.query...),
+		Responses:   map[string]*Response{},
+	}
+
+	switch r.access {
+	case optionalAuth:
+		operation.Security = []map[string][]string{{}, {bearerAuth: {}}}
+	case protected:
+		operation.Security = []map[string][]string{{bearerAuth: {}}}
+		operation.Responses["401"] = errorResponse(http.StatusUnauthorized)
+	}
+	if len(r.roles) > 0 {
+		operation.Description = strings.TrimSpace(operation.Description + "\n\nRequires one of the roles: " + strings.Join(r.roles, ", ") + ".")
+		operation.Responses["403"] = errorResponse(http.StatusForbidden)
+	}
+
+	if r.body != nil {
+		operation.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{"application/json": {Schema: registry.of(r.body)}},
+		}
+		operation.Responses["400"] = errorResponse(http.StatusBadRequest)
+	}
+	if r.multipart != nil {
+		schema := registry.of(r.multipart)
+		if resolved, ok := registry.schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]; ok {
+			schema = resolved
+		}
+		operation.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{"multipart/form-data": {Schema: schema}},
+		}
+		operation.Responses["400"] = errorResponse(http.StatusBadRequest)
+	}
+	if len(parameters) > 0 {
+		operation.Responses["404"] = errorResponse(http.StatusNotFound)
+	}
+	operation.Responses["500"] = errorResponse(http.StatusInternalServerError)
+
+	switch {
+	case r.empty:
+		operation.Responses["200"] = &Response{Description: "Empty response"}
+	case r.contentType != "":
+		operation.Responses["200"] = &Response{
+			Description: "Successful response",
+			Content:     map[string]*MediaType{r.contentType: {Schema: &Schema{Type: "string"}}},
+		}
+	case r.unwrapped:
+		operation.Responses["200"] = &Response{
+			Description: "Successful response",
+			Content:     map[string]*MediaType{"application/json": {Schema: registry.of(r.data)}},
+		}
+	default:
+		operation.Responses["200"] = &Response{
+			Description: "Successful response",
+			Content:     map[string]*MediaType{"application/json": {Schema: envelope(registry.of(r.data))}},
+		}
+	}
+
+	if r.redirect {
+		operation.Responses["301"] = &Response{
+			Description: "Moved to the resource's current location",
+			Headers:     map[string]*Header{"Location": {Schema: &Schema{Type: "string"}}},
+		}
+	}
+
+	return operation
+}
+
+func (p *PathItem) set(method string, operation *Operation) {
+	switch method {
+	case http.MethodGet:
+		p.Get = operation
+	case http.MethodPut:
+		p.Put = operation
+	case http.MethodPost:
+		p.Post = operation
+	case http.MethodPatch:
+		p.Patch = operation
+	case http.MethodDelete:
+		p.Delete = operation
+	}
+}
+
+func (p *PathItem) get(method string) *Operation {
+	switch method {
+	case http.MethodGet:
+		return p.Get
+	case http.MethodPut:
+		return p.Put
+	case http.MethodPost:
+		return p.Post
+	case http.MethodPatch:
+		return p.Patch
+	case http.MethodDelete:
+		return p.Delete
+	}
+	return nil
+}
+
+func envelope(data *Schema) *Schema {
+	return &Schema{
+		AllOf: []*Schema{
+			{Ref: "#/components/schemas/CustomResponse"},
+			{
+				Type:       "object",
+				Properties: map[string]*Schema{"status": {Type: "string", Enum: []any{"Success"}}, "data": data},
+				Required:   []string{"status", "message", "data"},
+			},
+		},
+	}
+}
+
+func errorResponse(status int) *Response {
+	return &Response{
+		Description: http.StatusText(status),
+		Content: map[string]*MediaType{
+			"application/json": {Schema: &Schema{Ref: "#/components/schemas/ErrorResponse"}},
+		},
+	}
+}
+
+func pathParameters(ginPath string) (string, []Parameter) {
+	var parameters []Parameter
+
+	segments := strings.Split(ginPath, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			name := segment[1:]
+			segments[i] = "{" + name + "}"
+			parameters = append(parameters, Parameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Schema:   &Schema{Type: "string"},
+			})
+		}
+	}
+
+	return strings.Join(segments, "/"), parameters
+}
+
+func operationID(method, ginPath string) string {
+	var id strings.Builder
+	id.WriteString(strings.ToLower(method))
+
+	for _, segment := range strings.Split(ginPath, "/") {
+		segment = strings.TrimLeft(segment, ":*")
+		if segment == "" || segment == "api" || segment == "v1" {
+			continue
+		}
+		for _, word := range strings.FieldsFunc(segment, func(r rune) bool { return r == '-' || r == '_' || r == '.' }) {
+			id.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+
+	return id.String()
+}
@@ -0,0 +1,40 @@
+package openapi
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRequestBodiesMatchModels(t *testing.T) {
+	for _, r := range routes() {
+		f, ok := r.body.(modelFields)
+		if !ok {
+			continue
+		}
+		for _, name := range f.missing() {
+			t.Errorf("%s %s: request field %q does not exist on %T", r.method, r.path, name, f.model)
+		}
+	}
+}
+
+func TestRequestBodyRequiredFieldsFollowModels(t *testing.T) {
+	document := Spec()
+
+	review := document.Paths["/api/v1/reviews/"].Post.RequestBody.Content["application/json"].Schema
+	if _, ok := review.Properties["tour"].Properties["id"]; !ok {
+		t.Fatalf("expected the review body to describe tour.id, got %+v", review.Properties["tour"])
+	}
+	if got, want := review.Required, []string{"review", "tour"}; !slices.Equal(got, want) {
+		t.Errorf("review body required = %v, want %v", got, want)
+	}
+
+	update := document.Paths["/api/v1/reviews/{id}"].Patch.RequestBody.Content["application/json"].Schema
+	if len(update.Required) != 0 {
+		t.Errorf("partial updates should not require fields, got %v", update.Required)
+	}
+
+	signup := document.Paths["/api/v1/users/signup"].Post.RequestBody.Content["application/json"].Schema
+	if password := signup.Properties["password"]; password.MinLength == nil || *password.MinLength != 8 {
+		t.Errorf("signup password should inherit min=8 from models.User, got %+v", password)
+	}
+}
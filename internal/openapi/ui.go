@@ -0,0 +1,28 @@
+package openapi
+
+import (
+	"fmt"
+	"html"
+)
+
+func SwaggerUI(specURL string) string {
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>Natours API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5.17.14/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5.17.14/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = function () {
+      window.ui = SwaggerUIBundle({ url: "%s", dom_id: "#swagger-ui", deepLinking: true, persistAuthorization: true });
+    };
+  </script>
+</body>
+</html>
+`, html.EscapeString(specURL))
+}
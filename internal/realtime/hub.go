@@ -0,0 +1,158 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	EventAvailability = "availability"
+	EventReview       = "review"
+	EventPrice        = "price"
+)
+
+type Event struct {
+	Id        string          `json:"id"`
+	Type      string          `json:"type"`
+	TourId    string          `json:"tourId"`
+	Data      json.RawMessage `json:"data"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+func NewEvent(eventType string, tourId string, data any) (Event, error) {
+	content, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to encode %s event: %v", eventType, err)
+	}
+	return Event{
+		Id:        uuid.New().String(),
+		Type:      eventType,
+		TourId:    tourId,
+		Data:      content,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+type Transport interface {
+	Publish(ctx context.Context, event Event) error
+	Listen(ctx context.Context, deliver func(Event)) error
+}
+
+type subscriber struct {
+	events chan Event
+	once   sync.Once
+}
+
+type Hub struct {
+	mutex       sync.RWMutex
+	subscribers map[string]map[*subscriber]struct{}
+	bufferSize  int
+	transport   Transport
+	done        chan struct{}
+	closed      bool
+}
+
+func NewHub(bufferSize int, transport Transport) *Hub {
+	return &Hub{
+		subscribers: map[string]map[*subscriber]struct{}{},
+		bufferSize:  max(bufferSize, 1),
+		transport:   transport,
+		done:        make(chan struct{}),
+	}
+}
+
+func (h *Hub) Subscribe(tourId string) (<-chan Event, func()) {
+	sub := &subscriber{events: make(chan Event, h.bufferSize)}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.closed {
+		close(sub.events)
+		return sub.events, func() {}
+	}
+	if h.subscribers[tourId] == nil {
+		h.subscribers[tourId] = map[*subscriber]struct{}{}
+	}
+	h.subscribers[tourId][sub] = struct{}{}
+
+	return sub.events, func() {
+		h.mutex.Lock()
+		defer h.mutex.Unlock()
+
+		delete(h.subscribers[tourId], sub)
+		if len(h.subscribers[tourId]) == 0 {
+			delete(h.subscribers, tourId)
+		}
+		sub.once.Do(func() { close(sub.events) })
+	}
+}
+
+func (h *Hub) Listening(tourId string) bool {
+	if h.transport != nil {
+		return true
+	}
+
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return len(h.subscribers[tourId]) > 0
+}
+
+func (h *Hub) Publish(ctx context.Context, event Event) error {
+	h.deliver(event)
+	if h.transport == nil {
+		return nil
+	}
+	return h.transport.Publish(ctx, event)
+}
+
+func (h *Hub) deliver(event Event) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	for sub := range h.subscribers[event.TourId] {
+		select {
+		case sub.events <- event:
+		default:
+		}
+	}
+}
+
+func (h *Hub) Done() <-chan struct{} {
+	return h.done
+}
+
+func (h *Hub) Close() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.closed {
+		return
+	}
+	h.closed = true
+	close(h.done)
+
+	for tourId, subs := range h.subscribers {
+		for sub := range subs {
+			sub.once.Do(func() { close(sub.events) })
+		}
+		delete(h.subscribers, tourId)
+	}
+}
+
+func (h *Hub) Name() string {
+	return "realtime"
+}
+
+func (h *Hub) Run(ctx context.Context) error {
+	if h.transport == nil {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return h.transport.Listen(ctx, h.deliver)
+}
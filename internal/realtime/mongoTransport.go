@@ -0,0 +1,108 @@
+package realtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	Collection = "realtime_events"
+
+	collectionSize      = 16 << 20
+	collectionDocuments = 10000
+	namespaceExistsCode = 48
+
+	minRetryDelay = 500 * time.Millisecond
+	maxRetryDelay = 10 * time.Second
+)
+
+var _ Transport = (*MongoTransport)(nil)
+
+type envelope struct {
+	Id       primitive.ObjectID `bson:"_id,omitempty"`
+	Instance string
+	Event    Event
+}
+
+type MongoTransport struct {
+	collection *mongo.Collection
+	instance   string
+	logger     *slog.Logger
+}
+
+func NewMongoTransport(ctx context.Context, database *mongo.Database, logger *slog.Logger) (*MongoTransport, error) {
+	err := database.CreateCollection(ctx, Collection, options.CreateCollection().SetCapped(true).SetSizeInBytes(collectionSize).SetMaxDocuments(collectionDocuments))
+	var serverErr mongo.ServerError
+	if err != nil && !(errors.As(err, &serverErr) && serverErr.HasErrorCode(namespaceExistsCode)) {
+		return nil, fmt.Errorf("failed to create %s: %v", Collection, err)
+	}
+
+	return &MongoTransport{
+		collection: database.Collection(Collection),
+		instance:   uuid.New().String(),
+		logger:     logger,
+	}, nil
+}
+
+func (t *MongoTransport) Publish(ctx context.Context, event Event) error {
+	if _, err := t.collection.InsertOne(ctx, envelope{Instance: t.instance, Event: event}); err != nil {
+		return fmt.Errorf("failed to publish %s event: %v", event.Type, err)
+	}
+	return nil
+}
+
+func (t *MongoTransport) Listen(ctx context.Context, deliver func(Event)) error {
+	last := primitive.NewObjectIDFromTimestamp(time.Now())
+	delay := minRetryDelay
+
+	for {
+		err := t.tail(ctx, &last, deliver)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err == nil {
+			delay = minRetryDelay
+		} else {
+			t.logger.Warn("realtime event stream interrupted", "error", err, "retryIn", delay.String())
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		if err != nil {
+			delay = min(delay*2, maxRetryDelay)
+		}
+	}
+}
+
+func (t *MongoTransport) tail(ctx context.Context, last *primitive.ObjectID, deliver func(Event)) error {
+	cursor, err := t.collection.Find(ctx,
+		bson.M{"_id": bson.M{"$gt": *last}, "instance": bson.M{"$ne": t.instance}},
+		options.Find().SetCursorType(options.TailableAwait).SetMaxAwaitTime(time.Second),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to tail %s: %v", Collection, err)
+	}
+	defer cursor.Close(context.Background())
+
+	for cursor.Next(ctx) {
+		var document envelope
+		if err := cursor.Decode(&document); err != nil {
+			return fmt.Errorf("failed to decode realtime event: %v", err)
+		}
+		*last = document.Id
+		deliver(document.Event)
+	}
+	return cursor.Err()
+}
@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+)
+
+var _ AuditRepository = (*MemoryAuditRepository)(nil)
+
+type MemoryAuditRepository struct {
+	mutex   sync.RWMutex
+	entries []models.AuditEntry
+}
+
+func NewMemoryAuditRepository() *MemoryAuditRepository {
+	return &MemoryAuditRepository{}
+}
+
+func (r *MemoryAuditRepository) Append(ctx context.Context, entry *models.AuditEntry) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func (r *MemoryAuditRepository) Find(ctx context.Context, filter AuditFilter) ([]models.AuditEntry, error) {
+	var entries []models.AuditEntry
+	err := r.Each(ctx, filter, func(entry models.AuditEntry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	return entries, err
+}
+
+func (r *MemoryAuditRepository) Each(ctx context.Context, filter AuditFilter, fn func(models.AuditEntry) error) error {
+	r.mutex.RLock()
+	var matching []models.AuditEntry
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		if filter.Limit > 0 && len(matching) == filter.Limit {
+			break
+		}
+		if entry := r.entries[i]; auditMatches(entry, filter) {
+			matching = append(matching, entry)
+		}
+	}
+	r.mutex.RUnlock()
+
+	for _, entry := range matching {
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func auditMatches(entry models.AuditEntry, filter AuditFilter) bool {
+	switch {
+	case filter.ActorId != "" && entry.ActorId != filter.ActorId:
+		return false
+	case filter.Action != "" && entry.Action != filter.Action:
+		return false
+	case filter.TargetType != "" && entry.TargetType != filter.TargetType:
+		return false
+	case filter.TargetId != "" && entry.TargetId != filter.TargetId:
+		return false
+	case filter.Outcome != "" && entry.Outcome != filter.Outcome:
+		return false
+	case !filter.From.IsZero() && entry.CreatedAt.Before(filter.From):
+		return false
+	case !filter.To.IsZero() && !entry.CreatedAt.Before(filter.To):
+		return false
+	}
+	return true
+}
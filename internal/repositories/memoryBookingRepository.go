@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+)
+
+var _ BookingRepository = (*MemoryBookingRepository)(nil)
+
+type MemoryBookingRepository struct {
+	mutex    sync.RWMutex
+	bookings map[string]models.Booking
+}
+
+func NewMemoryBookingRepository() *MemoryBookingRepository {
+	return &MemoryBookingRepository{
+		bookings: map[string]models.Booking{},
+	}
+}
+
+func (r *MemoryBookingRepository) Create(ctx context.Context, booking *models.Booking) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.bookings[booking.Id] = *booking
+	return nil
+}
+
+func (r *MemoryBookingRepository) FindAll(ctx context.Context) ([]models.Booking, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var bookings []models.Booking
+	for _, booking := range r.bookings {
+		bookings = append(bookings, booking)
+	}
+	slices.SortFunc(bookings, func(a, b models.Booking) int { return compareCreated(a.CreatedAt, a.Id, b.CreatedAt, b.Id) })
+	return bookings, nil
+}
+
+func (r *MemoryBookingRepository) Find(ctx context.Context, filter BookingFilter) ([]models.Booking, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var bookings []models.Booking
+	for _, booking := range r.bookings {
+		if filter.TourIds != nil && !slices.Contains(filter.TourIds, booking.Tour.Id) {
+			continue
+		}
+		if filter.UserId != "" && booking.User.Id != filter.UserId {
+			continue
+		}
+		if !filter.CheckoutExpiredBefore.IsZero() && (booking.Paid || booking.CheckoutExpiresAt.IsZero() || !booking.CheckoutExpiresAt.Before(filter.CheckoutExpiredBefore)) {
+			continue
+		}
+		bookings = append(bookings, booking)
+	}
+	slices.SortFunc(bookings, func(a, b models.Booking) int { return compareCreated(b.CreatedAt, b.Id, a.CreatedAt, a.Id) })
+	bookings = bookings[min(filter.Offset, len(bookings)):]
+	if filter.Limit > 0 {
+		bookings = bookings[:min(filter.Limit, len(bookings))]
+	}
+	return bookings, nil
+}
+
+func (r *MemoryBookingRepository) FindById(ctx context.Context, id string) (*models.Booking, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	booking, ok := r.bookings[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &booking, nil
+}
+
+func (r *MemoryBookingRepository) Update(ctx context.Context, booking *models.Booking) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.bookings[booking.Id]; ok {
+		r.bookings[booking.Id] = *booking
+	}
+	return nil
+}
+
+func (r *MemoryBookingRepository) Delete(ctx context.Context, id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.bookings, id)
+	return nil
+}
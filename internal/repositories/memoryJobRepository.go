@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+)
+
+var _ JobRepository = (*MemoryJobRepository)(nil)
+
+type MemoryJobRepository struct {
+	mutex     sync.RWMutex
+	jobs      map[string]models.Job
+	schedules map[string]time.Time
+}
+
+func NewMemoryJobRepository() *MemoryJobRepository {
+	return &MemoryJobRepository{
+		jobs:      map[string]models.Job{},
+		schedules: map[string]time.Time{},
+	}
+}
+
+func (r *MemoryJobRepository) activeKeyTaken(key string, id string) bool {
+	if key == "" {
+		return false
+	}
+	for _, job := range r.jobs {
+		if job.ActiveKey == key && job.Id != id {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *MemoryJobRepository) Enqueue(ctx context.Context, job *models.Job) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.jobs[job.Id]; ok || r.activeKeyTaken(job.ActiveKey, job.Id) {
+		return ErrDuplicate
+	}
+	r.jobs[job.Id] = *job
+	return nil
+}
+
+func (r *MemoryJobRepository) Find(ctx context.Context, filter JobFilter) ([]models.Job, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var jobs []models.Job
+	for _, job := range r.jobs {
+		if filter.Type != "" && job.Type != filter.Type {
+			continue
+		}
+		if filter.Status != "" && job.Status != filter.Status {
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.After(jobs[j].CreatedAt) })
+	if filter.Limit > 0 && len(jobs) > filter.Limit {
+		jobs = jobs[:filter.Limit]
+	}
+	return jobs, nil
+}
+
+func (r *MemoryJobRepository) FindById(ctx context.Context, id string) (*models.Job, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &job, nil
+}
+
+func (r *MemoryJobRepository) ClaimDue(ctx context.Context, types []string, now time.Time, lease time.Duration, limit int) ([]models.Job, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var due []models.Job
+	for _, job := range r.jobs {
+		if !slices.Contains(types, job.Type) {
+			continue
+		}
+		if job.Status == models.JobQueued && !job.RunAt.After(now) || job.Status == models.JobRunning && !job.LockedUntil.After(now) {
+			due = append(due, job)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].RunAt.Before(due[j].RunAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	for i := range due {
+		due[i].Status = models.JobRunning
+		due[i].Attempts++
+		due[i].StartedAt = now
+		due[i].LockedUntil = now.Add(lease)
+		r.jobs[due[i].Id] = due[i]
+	}
+	return due, nil
+}
+
+func (r *MemoryJobRepository) Update(ctx context.Context, job *models.Job) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.jobs[job.Id]; !ok {
+		return nil
+	}
+	if r.activeKeyTaken(job.ActiveKey, job.Id) {
+		return ErrDuplicate
+	}
+	r.jobs[job.Id] = *job
+	return nil
+}
+
+func (r *MemoryJobRepository) ClaimSchedule(ctx context.Context, name string, now time.Time, next time.Time) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	nextRunAt, ok := r.schedules[name]
+	if ok && nextRunAt.After(now) {
+		return false, nil
+	}
+	r.schedules[name] = next
+	return ok, nil
+}
@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+)
+
+func TestMemoryFindOrdersLikeMongo(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tours := NewMemoryTourRepository(NewMemoryUserRepository())
+	for _, tour := range []models.Tour{
+		{Id: "c", Name: "C", CreatedAt: created},
+		{Id: "a", Name: "A", CreatedAt: created.Add(time.Hour)},
+		{Id: "b", Name: "B", CreatedAt: created},
+	} {
+		if err := tours.Create(ctx, &tour); err != nil {
+			t.Fatalf("failed to create tour: %v", err)
+		}
+	}
+	found, err := tours.Find(ctx, TourFilter{IncludeSecret: true})
+	if err != nil {
+		t.Fatalf("failed to find tours: %v", err)
+	}
+	if found[0].Id != "b" || found[1].Id != "c" || found[2].Id != "a" {
+		t.Errorf("tours found in order %s %s %s, want oldest first with ids breaking ties", found[0].Id, found[1].Id, found[2].Id)
+	}
+
+	bookings := NewMemoryBookingRepository()
+	for _, booking := range []models.Booking{
+		{Id: "c", CreatedAt: created},
+		{Id: "a", CreatedAt: created.Add(time.Hour)},
+		{Id: "b", CreatedAt: created},
+	} {
+		if err := bookings.Create(ctx, &booking); err != nil {
+			t.Fatalf("failed to create booking: %v", err)
+		}
+	}
+	recent, err := bookings.Find(ctx, BookingFilter{})
+	if err != nil {
+		t.Fatalf("failed to find bookings: %v", err)
+	}
+	if recent[0].Id != "a" || recent[1].Id != "c" || recent[2].Id != "b" {
+		t.Errorf("bookings found in order %s %s %s, want newest first with ids breaking ties", recent[0].Id, recent[1].Id, recent[2].Id)
+	}
+}
+
+func TestMemoryFindPages(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	reviews := NewMemoryReviewRepository()
+	bookings := NewMemoryBookingRepository()
+	for i, id := range []string{"a", "b", "c", "d"} {
+		if err := reviews.Create(ctx, &models.Review{Id: id, Status: models.ReviewStatusPublished, CreatedAt: created.Add(time.Duration(i) * time.Hour)}); err != nil {
+			t.Fatalf("failed to create review: %v", err)
+		}
+		if err := bookings.Create(ctx, &models.Booking{Id: id, CreatedAt: created.Add(time.Duration(i) * time.Hour)}); err != nil {
+			t.Fatalf("failed to create booking: %v", err)
+		}
+	}
+
+	reviewPage, err := reviews.Find(ctx, ReviewFilter{Sort: "oldest", Limit: 2, Offset: 1})
+	if err != nil {
+		t.Fatalf("failed to find reviews: %v", err)
+	}
+	if len(reviewPage) != 2 || reviewPage[0].Id != "b" || reviewPage[1].Id != "c" {
+		t.Errorf("unexpected review page %+v", reviewPage)
+	}
+
+	bookingPage, err := bookings.Find(ctx, BookingFilter{Limit: 2, Offset: 3})
+	if err != nil {
+		t.Fatalf("failed to find bookings: %v", err)
+	}
+	if len(bookingPage) != 1 || bookingPage[0].Id != "a" {
+		t.Errorf("unexpected booking page %+v", bookingPage)
+	}
+
+	if past, err := bookings.Find(ctx, BookingFilter{Offset: 10}); err != nil || len(past) != 0 {
+		t.Errorf("expected no bookings past the end, got %+v (%v)", past, err)
+	}
+}
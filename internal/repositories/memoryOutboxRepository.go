@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+)
+
+var (
+	_ OutboxRepository = (*MemoryOutboxRepository)(nil)
+	_ Transactor       = MemoryTransactor{}
+)
+
+type MemoryTransactor struct{}
+
+func (MemoryTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type MemoryOutboxRepository struct {
+	mutex    sync.Mutex
+	messages map[string]models.OutboxMessage
+}
+
+func NewMemoryOutboxRepository() *MemoryOutboxRepository {
+	return &MemoryOutboxRepository{
+		messages: map[string]models.OutboxMessage{},
+	}
+}
+
+func (r *MemoryOutboxRepository) Append(ctx context.Context, messages []models.OutboxMessage) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, message := range messages {
+		r.messages[message.Id] = message
+	}
+	return nil
+}
+
+func (r *MemoryOutboxRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxMessage, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var due []models.OutboxMessage
+	for _, message := range r.messages {
+		if message.Status == models.OutboxPending && !message.NextAttemptAt.After(now) {
+			due = append(due, message)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].OccurredAt.Before(due[j].OccurredAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	for i := range due {
+		due[i].NextAttemptAt = now.Add(lease)
+		r.messages[due[i].Id] = due[i]
+	}
+	return due, nil
+}
+
+func (r *MemoryOutboxRepository) Update(ctx context.Context, message *models.OutboxMessage) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.messages[message.Id]; ok {
+		r.messages[message.Id] = *message
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package repositories
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sync"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+)
+
+var _ ReviewRepository = (*MemoryReviewRepository)(nil)
+
+type MemoryReviewRepository struct {
+	mutex   sync.RWMutex
+	reviews map[string]models.Review
+}
+
+func NewMemoryReviewRepository() *MemoryReviewRepository {
+	return &MemoryReviewRepository{
+		reviews: map[string]models.Review{},
+	}
+}
+
+func (r *MemoryReviewRepository) Create(ctx context.Context, review *models.Review) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.reviews[review.Id] = cloneReview(*review)
+	return nil
+}
+
+func (r *MemoryReviewRepository) Find(ctx context.Context, filter ReviewFilter) ([]models.Review, error) {
+	r.mutex.RLock()
+	var reviews []models.Review
+	for _, review := range r.reviews {
+		if filter.TourId != "" && review.Tour.Id != filter.TourId {
+			continue
+		}
+		if filter.TourIds != nil && !slices.Contains(filter.TourIds, review.Tour.Id) {
+			continue
+		}
+		if filter.Status != "" && review.Status != filter.Status {
+			continue
+		}
+		reviews = append(reviews, cloneReview(review))
+	}
+	r.mutex.RUnlock()
+
+	slices.SortFunc(reviews, func(a, b models.Review) int {
+		switch filter.Sort {
+		case "most-helpful":
+			if a.HelpfulCount != b.HelpfulCount {
+				return b.HelpfulCount - a.HelpfulCount
+			}
+			return compareCreated(b.CreatedAt, b.Id, a.CreatedAt, a.Id)
+		case "oldest":
+			return compareCreated(a.CreatedAt, a.Id, b.CreatedAt, b.Id)
+		default:
+			return compareCreated(b.CreatedAt, b.Id, a.CreatedAt, a.Id)
+		}
+	})
+	reviews = reviews[min(filter.Offset, len(reviews)):]
+	if filter.Limit > 0 {
+		reviews = reviews[:min(filter.Limit, len(reviews))]
+	}
+	return reviews, nil
+}
+
+func (r *MemoryReviewRepository) FindById(ctx context.Context, id string) (*models.Review, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	review, ok := r.reviews[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	review = cloneReview(review)
+	return &review, nil
+}
+
+func (r *MemoryReviewRepository) RatingStats(ctx context.Context, tourId string) (RatingStats, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	total, quantity := 0, 0
+	for _, review := range r.reviews {
+		if review.Tour.Id == tourId && review.Status == models.ReviewStatusPublished {
+			total += review.Rating
+			quantity++
+		}
+	}
+
+	if quantity == 0 {
+		return RatingStats{}, nil
+	}
+	return RatingStats{Average: math.Round(float64(total)/float64(quantity)*10) / 10, Quantity: quantity}, nil
+}
+
+func (r *MemoryReviewRepository) Update(ctx context.Context, review *models.Review) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if stored, ok := r.reviews[review.Id]; ok {
+		updated := cloneReview(*review)
+		updated.Votes, updated.HelpfulCount, updated.UnhelpfulCount = stored.Votes, stored.HelpfulCount, stored.UnhelpfulCount
+		updated.Reports = stored.Reports
+		r.reviews[review.Id] = updated
+	}
+	return nil
+}
+
+func (r *MemoryReviewRepository) Vote(ctx context.Context, id string, vote models.ReviewVote) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	review, ok := r.reviews[id]
+	if !ok {
+		return nil
+	}
+
+	index := slices.IndexFunc(review.Votes, func(v models.ReviewVote) bool { return v.UserId == vote.UserId })
+	if index < 0 {
+		review.Votes = append(slices.Clone(review.Votes), vote)
+	} else if review.Votes[index].Helpful != vote.Helpful {
+		review.Votes = slices.Clone(review.Votes)
+		review.Votes[index].Helpful = vote.Helpful
+		adjustVoteCount(&review, !vote.Helpful, -1)
+	} else {
+		return nil
+	}
+	adjustVoteCount(&review, vote.Helpful, 1)
+	r.reviews[id] = review
+	return nil
+}
+
+func (r *MemoryReviewRepository) RemoveVote(ctx context.Context, id string, userId string) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	review, ok := r.reviews[id]
+	if !ok {
+		return false, nil
+	}
+
+	index := slices.IndexFunc(review.Votes, func(v models.ReviewVote) bool { return v.UserId == userId })
+	if index < 0 {
+		return false, nil
+	}
+	adjustVoteCount(&review, review.Votes[index].Helpful, -1)
+	review.Votes = slices.Delete(slices.Clone(review.Votes), index, index+1)
+	r.reviews[id] = review
+	return true, nil
+}
+
+func (r *MemoryReviewRepository) Report(ctx context.Context, id string, report models.ReviewReport) (*models.Review, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	review, ok := r.reviews[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if slices.ContainsFunc(review.Reports, func(existing models.ReviewReport) bool { return existing.UserId == report.UserId }) {
+		return nil, ErrDuplicate
+	}
+
+	review.Reports = append(slices.Clone(review.Reports), report)
+	r.reviews[id] = review
+	review = cloneReview(review)
+	return &review, nil
+}
+
+func (r *MemoryReviewRepository) Delete(ctx context.Context, id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.reviews, id)
+	return nil
+}
+
+func adjustVoteCount(review *models.Review, helpful bool, delta int) {
+	if helpful {
+		review.HelpfulCount += delta
+	} else {
+		review.UnhelpfulCount += delta
+	}
+}
+
+func cloneReview(review models.Review) models.Review {
+	review.Reports = slices.Clone(review.Reports)
+	review.Votes = slices.Clone(review.Votes)
+	if review.Reply != nil {
+		reply := *review.Reply
+		review.Reply = &reply
+	}
+	review.Tour = cloneTour(review.Tour)
+	return review
+}
@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+)
+
+var _ TourRepository = (*MemoryTourRepository)(nil)
+
+type MemoryTourRepository struct {
+	mutex sync.RWMutex
+	tours map[string]models.Tour
+	users UserRepository
+}
+
+func NewMemoryTourRepository(users UserRepository) *MemoryTourRepository {
+	return &MemoryTourRepository{
+		tours: map[string]models.Tour{},
+		users: users,
+	}
+}
+
+func (r *MemoryTourRepository) Create(ctx context.Context, tour *models.Tour) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.tours[tour.Id] = cloneTour(*tour)
+	return nil
+}
+
+func (r *MemoryTourRepository) Find(ctx context.Context, filter TourFilter) ([]models.Tour, error) {
+	r.mutex.RLock()
+	var tours []models.Tour
+	for _, tour := range r.tours {
+		if tourMatches(tour, filter) {
+			tours = append(tours, cloneTour(tour))
+		}
+	}
+	r.mutex.RUnlock()
+
+	slices.SortFunc(tours, func(a, b models.Tour) int { return compareCreated(a.CreatedAt, a.Id, b.CreatedAt, b.Id) })
+	tours = tours[min(filter.Offset, len(tours)):]
+	if filter.Limit > 0 {
+		tours = tours[:min(filter.Limit, len(tours))]
+	}
+
+	for i := range tours {
+		if len(tours[i].Guides) == 0 {
+			continue
+		}
+		guides, err := r.users.FindByIds(ctx, tours[i].Guides)
+		if err != nil {
+			return nil, err
+		}
+		for _, guide := range guides {
+			tours[i].GuideProfiles = append(tours[i].GuideProfiles, models.Guide{
+				Id:    guide.Id,
+				Name:  guide.Name,
+				Photo: guide.Photo,
+				Role:  guide.Role,
+			})
+		}
+	}
+	return tours, nil
+}
+
+func (r *MemoryTourRepository) FindByGuides(ctx context.Context, filter TourFilter) (map[string][]models.Tour, error) {
+	guideIds := filter.GuideIds
+	filter.GuideIds = nil
+
+	pages := map[string][]models.Tour{}
+	for _, guideId := range guideIds {
+		filter.GuideId = guideId
+		tours, err := r.Find(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		if len(tours) > 0 {
+			pages[guideId] = tours
+		}
+	}
+	return pages, nil
+}
+
+func (r *MemoryTourRepository) SlugTaken(ctx context.Context, slug string, excludeId string) (bool, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, tour := range r.tours {
+		if tour.Id != excludeId && (tour.Slug == slug || slices.Contains(tour.PreviousSlugs, slug)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *MemoryTourRepository) Update(ctx context.Context, tour *models.Tour) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.tours[tour.Id]; ok {
+		r.tours[tour.Id] = cloneTour(*tour)
+	}
+	return nil
+}
+
+func (r *MemoryTourRepository) SetAccessToken(ctx context.Context, id string, accessToken string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if tour, ok := r.tours[id]; ok {
+		tour.AccessToken = accessToken
+		r.tours[id] = tour
+	}
+	return nil
+}
+
+func (r *MemoryTourRepository) SetRatings(ctx context.Context, id string, stats RatingStats) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if tour, ok := r.tours[id]; ok {
+		tour.RatingsAvg = stats.Average
+		tour.RatingQuantity = stats.Quantity
+		r.tours[id] = tour
+	}
+	return nil
+}
+
+func (r *MemoryTourRepository) Delete(ctx context.Context, id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.tours, id)
+	return nil
+}
+
+func tourMatches(tour models.Tour, filter TourFilter) bool {
+	if filter.Id != "" && tour.Id != filter.Id {
+		return false
+	}
+	if filter.Ids != nil && !slices.Contains(filter.Ids, tour.Id) {
+		return false
+	}
+	if filter.Slug != "" && tour.Slug != filter.Slug {
+		return false
+	}
+	if filter.PreviousSlug != "" && !slices.Contains(tour.PreviousSlugs, filter.PreviousSlug) {
+		return false
+	}
+	if filter.GuideId != "" && !slices.Contains(tour.Guides, filter.GuideId) {
+		return false
+	}
+	if filter.GuideIds != nil && !slices.ContainsFunc(tour.Guides, func(guide string) bool { return slices.Contains(filter.GuideIds, guide) }) {
+		return false
+	}
+	if filter.AccessToken != "" {
+		return tour.AccessToken == filter.AccessToken
+	}
+	return filter.IncludeSecret || !tour.SecretTour
+}
+
+func cloneTour(tour models.Tour) models.Tour {
+	tour.Images = slices.Clone(tour.Images)
+	tour.StartDates = slices.Clone(tour.StartDates)
+	tour.Locations = slices.Clone(tour.Locations)
+	tour.Guides = slices.Clone(tour.Guides)
+	tour.PreviousSlugs = slices.Clone(tour.PreviousSlugs)
+	tour.GuideProfiles = nil
+	return tour
+}
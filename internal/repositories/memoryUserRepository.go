@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+)
+
+var _ UserRepository = (*MemoryUserRepository)(nil)
+
+type MemoryUserRepository struct {
+	mutex sync.RWMutex
+	users map[string]models.User
+}
+
+func NewMemoryUserRepository() *MemoryUserRepository {
+	return &MemoryUserRepository{
+		users: map[string]models.User{},
+	}
+}
+
+func (r *MemoryUserRepository) Create(ctx context.Context, user *models.User) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.users[user.Id]; ok || r.emailTaken(user.Email, user.Id) {
+		return ErrDuplicate
+	}
+	r.users[user.Id] = *user
+	return nil
+}
+
+func (r *MemoryUserRepository) FindAll(ctx context.Context) ([]models.User, error) {
+	return r.find(func(user models.User) bool { return true }), nil
+}
+
+func (r *MemoryUserRepository) FindById(ctx context.Context, id string) (*models.User, error) {
+	return r.findOne(func(user models.User) bool { return user.Id == id })
+}
+
+func (r *MemoryUserRepository) FindByIds(ctx context.Context, ids []string) ([]models.User, error) {
+	wanted := map[string]bool{}
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	return r.find(func(user models.User) bool { return wanted[user.Id] }), nil
+}
+
+func (r *MemoryUserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	return r.findOne(func(user models.User) bool { return user.Email == email })
+}
+
+func (r *MemoryUserRepository) FindByPasswordResetToken(ctx context.Context, token string) (*models.User, error) {
+	return r.findOne(func(user models.User) bool { return token != "" && user.PasswordResetToken == token })
+}
+
+func (r *MemoryUserRepository) Update(ctx context.Context, user *models.User) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.users[user.Id]; ok {
+		if r.emailTaken(user.Email, user.Id) {
+			return ErrDuplicate
+		}
+		r.users[user.Id] = *user
+	}
+	return nil
+}
+
+func (r *MemoryUserRepository) ClearExpiredPasswordResetTokens(ctx context.Context, now time.Time) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	cleared := 0
+	for id, user := range r.users {
+		if user.PasswordResetToken != "" && !user.PasswordResetTokenExpiry.After(now) {
+			user.PasswordResetToken = ""
+			user.PasswordResetTokenExpiry = time.Time{}
+			r.users[id] = user
+			cleared++
+		}
+	}
+	return cleared, nil
+}
+
+func (r *MemoryUserRepository) Delete(ctx context.Context, id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.users, id)
+	return nil
+}
+
+func (r *MemoryUserRepository) DeleteAll(ctx context.Context) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.users = map[string]models.User{}
+	return nil
+}
+
+func (r *MemoryUserRepository) find(match func(models.User) bool) []models.User {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var users []models.User
+	for _, user := range r.users {
+		if match(user) {
+			users = append(users, user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Id < users[j].Id })
+	return users
+}
+
+func (r *MemoryUserRepository) findOne(match func(models.User) bool) (*models.User, error) {
+	users := r.find(match)
+	if len(users) == 0 {
+		return nil, ErrNotFound
+	}
+	return &users[0], nil
+}
+
+func (r *MemoryUserRepository) emailTaken(email string, excludeId string) bool {
+	for id, user := range r.users {
+		if id != excludeId && user.Email == email {
+			return true
+		}
+	}
+	return false
+}
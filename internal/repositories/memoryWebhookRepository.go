@@ -0,0 +1,170 @@
+package repositories
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+)
+
+var _ WebhookRepository = (*MemoryWebhookRepository)(nil)
+
+type MemoryWebhookRepository struct {
+	mutex         sync.RWMutex
+	subscriptions map[string]models.WebhookSubscription
+	deliveries    map[string]models.WebhookDelivery
+}
+
+func NewMemoryWebhookRepository() *MemoryWebhookRepository {
+	return &MemoryWebhookRepository{
+		subscriptions: map[string]models.WebhookSubscription{},
+		deliveries:    map[string]models.WebhookDelivery{},
+	}
+}
+
+func (r *MemoryWebhookRepository) CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.subscriptions[subscription.Id] = *subscription
+	return nil
+}
+
+func (r *MemoryWebhookRepository) FindSubscriptions(ctx context.Context, event string) ([]models.WebhookSubscription, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var subscriptions []models.WebhookSubscription
+	for _, subscription := range r.subscriptions {
+		if event != "" && (!subscription.Active || !slices.Contains(subscription.Events, event)) {
+			continue
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	slices.SortFunc(subscriptions, func(a, b models.WebhookSubscription) int { return compareCreated(a.CreatedAt, a.Id, b.CreatedAt, b.Id) })
+	return subscriptions, nil
+}
+
+func (r *MemoryWebhookRepository) FindSubscriptionById(ctx context.Context, id string) (*models.WebhookSubscription, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	subscription, ok := r.subscriptions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &subscription, nil
+}
+
+func (r *MemoryWebhookRepository) UpdateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.subscriptions[subscription.Id]; ok {
+		r.subscriptions[subscription.Id] = *subscription
+	}
+	return nil
+}
+
+func (r *MemoryWebhookRepository) RecordAttempt(ctx context.Context, id string, succeeded bool) (*models.WebhookSubscription, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	subscription, ok := r.subscriptions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if succeeded {
+		subscription.ConsecutiveFailures = 0
+	} else {
+		subscription.ConsecutiveFailures++
+	}
+	r.subscriptions[id] = subscription
+	return &subscription, nil
+}
+
+func (r *MemoryWebhookRepository) DeleteSubscription(ctx context.Context, id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.subscriptions, id)
+	return nil
+}
+
+func (r *MemoryWebhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.deliveries[delivery.Id]; ok {
+		return ErrDuplicate
+	}
+	r.deliveries[delivery.Id] = *delivery
+	return nil
+}
+
+func (r *MemoryWebhookRepository) FindDeliveries(ctx context.Context, filter WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var deliveries []models.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if filter.SubscriptionId != "" && delivery.SubscriptionId != filter.SubscriptionId {
+			continue
+		}
+		if filter.Status != "" && delivery.Status != filter.Status {
+			continue
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	slices.SortFunc(deliveries, func(a, b models.WebhookDelivery) int { return compareCreated(b.CreatedAt, b.Id, a.CreatedAt, a.Id) })
+	if filter.Limit > 0 && len(deliveries) > filter.Limit {
+		deliveries = deliveries[:filter.Limit]
+	}
+	return deliveries, nil
+}
+
+func (r *MemoryWebhookRepository) FindDeliveryById(ctx context.Context, id string) (*models.WebhookDelivery, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	delivery, ok := r.deliveries[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &delivery, nil
+}
+
+func (r *MemoryWebhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var due []models.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.Status == models.WebhookDeliveryPending && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].NextAttemptAt.Before(due[j].NextAttemptAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	for i := range due {
+		due[i].NextAttemptAt = now.Add(lease)
+		r.deliveries[due[i].Id] = due[i]
+	}
+	return due, nil
+}
+
+func (r *MemoryWebhookRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.deliveries[delivery.Id]; ok {
+		r.deliveries[delivery.Id] = *delivery
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var _ AuditRepository = (*MongoAuditRepository)(nil)
+
+type MongoAuditRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoAuditRepository(database *mongo.Database) *MongoAuditRepository {
+	return &MongoAuditRepository{
+		collection: database.Collection("audit_log"),
+	}
+}
+
+func (r *MongoAuditRepository) Append(ctx context.Context, entry *models.AuditEntry) error {
+	_, err := r.collection.InsertOne(ctx, entry)
+	if err != nil {
+		return fmt.Errorf("failed to append audit entry: %v", err)
+	}
+	return nil
+}
+
+func (r *MongoAuditRepository) Find(ctx context.Context, filter AuditFilter) ([]models.AuditEntry, error) {
+	var entries []models.AuditEntry
+	err := r.Each(ctx, filter, func(entry models.AuditEntry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	return entries, err
+}
+
+func (r *MongoAuditRepository) Each(ctx context.Context, filter AuditFilter, fn func(models.AuditEntry) error) error {
+	query := bson.M{}
+	if filter.ActorId != "" {
+		query["actorid"] = filter.ActorId
+	}
+	if filter.Action != "" {
+		query["action"] = filter.Action
+	}
+	if filter.TargetType != "" {
+		query["targettype"] = filter.TargetType
+	}
+	if filter.TargetId != "" {
+		query["targetid"] = filter.TargetId
+	}
+	if filter.Outcome != "" {
+		query["outcome"] = filter.Outcome
+	}
+	createdAt := bson.M{}
+	if !filter.From.IsZero() {
+		createdAt["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		createdAt["$lt"] = filter.To
+	}
+	if len(createdAt) > 0 {
+		query["createdat"] = createdAt
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "createdat", Value: -1}, {Key: "_id", Value: -1}})
+	if filter.Limit > 0 {
+		findOptions.SetLimit(int64(filter.Limit))
+	}
+
+	cursor, err := r.collection.Find(ctx, query, findOptions)
+	if err != nil {
+		return fmt.Errorf("failed to find audit entries: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var entry models.AuditEntry
+		if err := cursor.Decode(&entry); err != nil {
+			return fmt.Errorf("failed to decode audit entry: %v", err)
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to read audit entries: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var _ BookingRepository = (*MongoBookingRepository)(nil)
+
+type MongoBookingRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoBookingRepository(database *mongo.Database) *MongoBookingRepository {
+	return &MongoBookingRepository{
+		collection: database.Collection("bookings"),
+	}
+}
+
+func (r *MongoBookingRepository) Create(ctx context.Context, booking *models.Booking) error {
+	_, err := r.collection.InsertOne(ctx, booking)
+	if err != nil {
+		return fmt.Errorf("failed to create booking: %v", err)
+	}
+	return nil
+}
+
+func (r *MongoBookingRepository) FindAll(ctx context.Context) ([]models.Booking, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "createdat", Value: 1}, {Key: "id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find bookings: %v", err)
+	}
+
+	var bookings []models.Booking
+	if err := cursor.All(ctx, &bookings); err != nil {
+		return nil, fmt.Errorf("failed to decode bookings: %v", err)
+	}
+	return bookings, nil
+}
+
+func (r *MongoBookingRepository) Find(ctx context.Context, filter BookingFilter) ([]models.Booking, error) {
+	query := bson.M{}
+	if filter.TourIds != nil {
+		query["tour.id"] = bson.M{"$in": filter.TourIds}
+	}
+	if filter.UserId != "" {
+		query["user.id"] = filter.UserId
+	}
+	if !filter.CheckoutExpiredBefore.IsZero() {
+		query["paid"] = false
+		query["checkoutexpiresat"] = bson.M{"$gt": time.Time{}, "$lt": filter.CheckoutExpiredBefore}
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "createdat", Value: -1}, {Key: "id", Value: -1}})
+	if filter.Offset > 0 {
+		findOptions.SetSkip(int64(filter.Offset))
+	}
+	if filter.Limit > 0 {
+		findOptions.SetLimit(int64(filter.Limit))
+	}
+
+	cursor, err := r.collection.Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find bookings: %v", err)
+	}
+
+	var bookings []models.Booking
+	if err := cursor.All(ctx, &bookings); err != nil {
+		return nil, fmt.Errorf("failed to decode bookings: %v", err)
+	}
+	return bookings, nil
+}
+
+func (r *MongoBookingRepository) FindById(ctx context.Context, id string) (*models.Booking, error) {
+	var booking models.Booking
+
+	err := r.collection.FindOne(ctx, bson.M{"id": id}).Decode(&booking)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find booking: %v", err)
+	}
+	return &booking, nil
+}
+
+func (r *MongoBookingRepository) Update(ctx context.Context, booking *models.Booking) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"id": booking.Id}, bson.M{"$set": booking})
+	if err != nil {
+		return fmt.Errorf("failed to update booking: %v", err)
+	}
+	return nil
+}
+
+func (r *MongoBookingRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete booking: %v", err)
+	}
+	return nil
+}
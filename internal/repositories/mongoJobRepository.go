@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var _ JobRepository = (*MongoJobRepository)(nil)
+
+type MongoJobRepository struct {
+	jobs      *mongo.Collection
+	schedules *mongo.Collection
+}
+
+func NewMongoJobRepository(database *mongo.Database) *MongoJobRepository {
+	return &MongoJobRepository{
+		jobs:      database.Collection("jobs"),
+		schedules: database.Collection("job_schedules"),
+	}
+}
+
+func (r *MongoJobRepository) Enqueue(ctx context.Context, job *models.Job) error {
+	_, err := r.jobs.InsertOne(ctx, job)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return fmt.Errorf("failed to enqueue job: %v", err)
+	}
+	return nil
+}
+
+func (r *MongoJobRepository) Find(ctx context.Context, filter JobFilter) ([]models.Job, error) {
+	query := bson.M{}
+	if filter.Type != "" {
+		query["type"] = filter.Type
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "createdat", Value: -1}})
+	if filter.Limit > 0 {
+		findOptions.SetLimit(int64(filter.Limit))
+	}
+
+	cursor, err := r.jobs.Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find jobs: %v", err)
+	}
+
+	var jobs []models.Job
+	if err := cursor.All(ctx, &jobs); err != nil {
+		return nil, fmt.Errorf("failed to decode jobs: %v", err)
+	}
+	return jobs, nil
+}
+
+func (r *MongoJobRepository) FindById(ctx context.Context, id string) (*models.Job, error) {
+	var job models.Job
+
+	err := r.jobs.FindOne(ctx, bson.M{"id": id}).Decode(&job)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find job: %v", err)
+	}
+	return &job, nil
+}
+
+func (r *MongoJobRepository) ClaimDue(ctx context.Context, types []string, now time.Time, lease time.Duration, limit int) ([]models.Job, error) {
+	var claimed []models.Job
+
+	for len(claimed) < limit {
+		var job models.Job
+		err := r.jobs.FindOneAndUpdate(ctx,
+			bson.M{
+				"type": bson.M{"$in": types},
+				"$or": bson.A{
+					bson.M{"status": models.JobQueued, "runat": bson.M{"$lte": now}},
+					bson.M{"status": models.JobRunning, "lockeduntil": bson.M{"$lte": now}},
+				},
+			},
+			bson.M{
+				"$set": bson.M{"status": models.JobRunning, "startedat": now, "lockeduntil": now.Add(lease)},
+				"$inc": bson.M{"attempts": 1},
+			},
+			options.FindOneAndUpdate().
+				SetSort(bson.D{{Key: "runat", Value: 1}}).
+				SetReturnDocument(options.After),
+		).Decode(&job)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			return claimed, fmt.Errorf("failed to claim jobs: %v", err)
+		}
+		claimed = append(claimed, job)
+	}
+	return claimed, nil
+}
+
+func (r *MongoJobRepository) Update(ctx context.Context, job *models.Job) error {
+	_, err := r.jobs.UpdateOne(ctx, bson.M{"id": job.Id}, bson.M{"$set": job})
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update job: %v", err)
+	}
+	return nil
+}
+
+func (r *MongoJobRepository) ClaimSchedule(ctx context.Context, name string, now time.Time, next time.Time) (bool, error) {
+	result, err := r.schedules.UpdateOne(ctx,
+		bson.M{"name": name, "nextrunat": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"nextrunat": next}},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim job schedule: %v", err)
+	}
+	if result.MatchedCount > 0 {
+		return true, nil
+	}
+
+	_, err = r.schedules.InsertOne(ctx, bson.M{"name": name, "nextrunat": next})
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return false, fmt.Errorf("failed to create job schedule: %v", err)
+	}
+	return false, nil
+}
@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	_ OutboxRepository = (*MongoOutboxRepository)(nil)
+	_ Transactor       = (*MongoTransactor)(nil)
+)
+
+type MongoTransactor struct {
+	client                *mongo.Client
+	allowNonTransactional bool
+
+	mutex     sync.Mutex
+	detected  bool
+	supported bool
+}
+
+func NewMongoTransactor(client *mongo.Client, allowNonTransactional bool) *MongoTransactor {
+	return &MongoTransactor{
+		client:                client,
+		allowNonTransactional: allowNonTransactional,
+	}
+}
+
+func (t *MongoTransactor) Supported(ctx context.Context) (bool, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.detected {
+		return t.supported, nil
+	}
+
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := t.client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return false, fmt.Errorf("failed to detect transaction support: %v", err)
+	}
+
+	t.detected = true
+	t.supported = hello.SetName != "" || hello.Msg == "isdbgrid"
+	return t.supported, nil
+}
+
+func (t *MongoTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	supported, err := t.Supported(ctx)
+	if err != nil {
+		return err
+	}
+	if !supported {
+		if !t.allowNonTransactional {
+			return ErrTransactionsUnsupported
+		}
+		return fn(ctx)
+	}
+
+	session, err := t.client.StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start session: %v", err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(ctx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(ctx)
+	})
+	return err
+}
+
+type MongoOutboxRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoOutboxRepository(database *mongo.Database) *MongoOutboxRepository {
+	return &MongoOutboxRepository{
+		collection: database.Collection("outbox"),
+	}
+}
+
+func (r *MongoOutboxRepository) Append(ctx context.Context, messages []models.OutboxMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	documents := make([]interface{}, len(messages))
+	for i := range messages {
+		documents[i] = messages[i]
+	}
+
+	_, err := r.collection.InsertMany(ctx, documents)
+	if err != nil {
+		return fmt.Errorf("failed to append outbox messages: %v", err)
+	}
+	return nil
+}
+
+func (r *MongoOutboxRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxMessage, error) {
+	var claimed []models.OutboxMessage
+
+	for len(claimed) < limit {
+		var message models.OutboxMessage
+		err := r.collection.FindOneAndUpdate(ctx,
+			bson.M{"status": models.OutboxPending, "nextattemptat": bson.M{"$lte": now}},
+			bson.M{"$set": bson.M{"nextattemptat": now.Add(lease)}},
+			options.FindOneAndUpdate().
+				SetSort(bson.D{{Key: "occurredat", Value: 1}}).
+				SetReturnDocument(options.After),
+		).Decode(&message)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			return claimed, fmt.Errorf("failed to claim outbox messages: %v", err)
+		}
+		claimed = append(claimed, message)
+	}
+	return claimed, nil
+}
+
+func (r *MongoOutboxRepository) Update(ctx context.Context, message *models.OutboxMessage) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"id": message.Id}, bson.M{"$set": message})
+	if err != nil {
+		return fmt.Errorf("failed to update outbox message: %v", err)
+	}
+	return nil
+}
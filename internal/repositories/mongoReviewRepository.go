@@ -0,0 +1,217 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var _ ReviewRepository = (*MongoReviewRepository)(nil)
+
+type MongoReviewRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoReviewRepository(database *mongo.Database) *MongoReviewRepository {
+	return &MongoReviewRepository{
+		collection: database.Collection("reviews"),
+	}
+}
+
+func (r *MongoReviewRepository) Create(ctx context.Context, review *models.Review) error {
+	_, err := r.collection.InsertOne(ctx, review)
+	if err != nil {
+		return fmt.Errorf("failed to create review: %v", err)
+	}
+	return nil
+}
+
+func (r *MongoReviewRepository) Find(ctx context.Context, filter ReviewFilter) ([]models.Review, error) {
+	query := bson.M{}
+	if filter.TourId != "" {
+		query["tour.id"] = filter.TourId
+	}
+	if filter.TourIds != nil {
+		query["tour.id"] = bson.M{"$in": filter.TourIds}
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+
+	findOptions := options.Find()
+	switch filter.Sort {
+	case "most-helpful":
+		findOptions.SetSort(bson.D{{Key: "helpfulcount", Value: -1}, {Key: "createdat", Value: -1}, {Key: "id", Value: -1}})
+	case "oldest":
+		findOptions.SetSort(bson.D{{Key: "createdat", Value: 1}, {Key: "id", Value: 1}})
+	default:
+		findOptions.SetSort(bson.D{{Key: "createdat", Value: -1}, {Key: "id", Value: -1}})
+	}
+	if filter.Offset > 0 {
+		findOptions.SetSkip(int64(filter.Offset))
+	}
+	if filter.Limit > 0 {
+		findOptions.SetLimit(int64(filter.Limit))
+	}
+
+	cursor, err := r.collection.Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find reviews: %v", err)
+	}
+
+	var reviews []models.Review
+	if err := cursor.All(ctx, &reviews); err != nil {
+		return nil, fmt.Errorf("failed to decode reviews: %v", err)
+	}
+	return reviews, nil
+}
+
+func (r *MongoReviewRepository) FindById(ctx context.Context, id string) (*models.Review, error) {
+	var review models.Review
+
+	err := r.collection.FindOne(ctx, bson.M{"id": id}).Decode(&review)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find review: %v", err)
+	}
+	return &review, nil
+}
+
+func (r *MongoReviewRepository) RatingStats(ctx context.Context, tourId string) (RatingStats, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"tour.id": tourId, "status": models.ReviewStatusPublished}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      "$tour.id",
+			"quantity": bson.M{"$sum": 1},
+			"average":  bson.M{"$avg": "$rating"},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return RatingStats{}, fmt.Errorf("failed to calculate tour ratings: %v", err)
+	}
+
+	var stats []struct {
+		Quantity int     `bson:"quantity"`
+		Average  float64 `bson:"average"`
+	}
+	if err := cursor.All(ctx, &stats); err != nil {
+		return RatingStats{}, fmt.Errorf("failed to calculate tour ratings: %v", err)
+	}
+
+	if len(stats) == 0 {
+		return RatingStats{}, nil
+	}
+	return RatingStats{Average: math.Round(stats[0].Average*10) / 10, Quantity: stats[0].Quantity}, nil
+}
+
+func (r *MongoReviewRepository) Update(ctx context.Context, review *models.Review) error {
+	document, err := reviewDocument(review)
+	if err != nil {
+		return fmt.Errorf("failed to update review: %v", err)
+	}
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"id": review.Id}, bson.M{"$set": document})
+	if err != nil {
+		return fmt.Errorf("failed to update review: %v", err)
+	}
+	return nil
+}
+
+func (r *MongoReviewRepository) Vote(ctx context.Context, id string, vote models.ReviewVote) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"id": id, "votes.userid": bson.M{"$ne": vote.UserId}},
+		bson.M{"$push": bson.M{"votes": vote}, "$inc": bson.M{voteCounter(vote.Helpful): 1}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add review vote: %v", err)
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	_, err = r.collection.UpdateOne(ctx,
+		bson.M{"id": id, "votes": bson.M{"$elemMatch": bson.M{"userid": vote.UserId, "helpful": !vote.Helpful}}},
+		bson.M{"$set": bson.M{"votes.$.helpful": vote.Helpful}, "$inc": bson.M{voteCounter(vote.Helpful): 1, voteCounter(!vote.Helpful): -1}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to change review vote: %v", err)
+	}
+	return nil
+}
+
+func (r *MongoReviewRepository) RemoveVote(ctx context.Context, id string, userId string) (bool, error) {
+	for _, helpful := range []bool{true, false} {
+		result, err := r.collection.UpdateOne(ctx,
+			bson.M{"id": id, "votes": bson.M{"$elemMatch": bson.M{"userid": userId, "helpful": helpful}}},
+			bson.M{"$pull": bson.M{"votes": bson.M{"userid": userId}}, "$inc": bson.M{voteCounter(helpful): -1}},
+		)
+		if err != nil {
+			return false, fmt.Errorf("failed to remove review vote: %v", err)
+		}
+		if result.MatchedCount > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *MongoReviewRepository) Report(ctx context.Context, id string, report models.ReviewReport) (*models.Review, error) {
+	var review models.Review
+
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"id": id, "reports.userid": bson.M{"$ne": report.UserId}},
+		bson.M{"$push": bson.M{"reports": report}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&review)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		if _, err := r.FindById(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, ErrDuplicate
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to report review: %v", err)
+	}
+	return &review, nil
+}
+
+func (r *MongoReviewRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete review: %v", err)
+	}
+	return nil
+}
+
+func voteCounter(helpful bool) string {
+	if helpful {
+		return "helpfulcount"
+	}
+	return "unhelpfulcount"
+}
+
+func reviewDocument(review *models.Review) (bson.M, error) {
+	data, err := bson.Marshal(review)
+	if err != nil {
+		return nil, err
+	}
+
+	var document bson.M
+	if err := bson.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	for _, field := range []string{"votes", "helpfulcount", "unhelpfulcount", "reports"} {
+		delete(document, field)
+	}
+	return document, nil
+}
@@ -0,0 +1,196 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var populateGuidesStage = bson.D{{Key: "$lookup", Value: bson.M{
+	"from": "users",
+	"let":  bson.M{"guides": bson.M{"$ifNull": bson.A{"$guides", bson.A{}}}},
+	"pipeline": bson.A{
+		bson.M{"$match": bson.M{"$expr": bson.M{"$in": bson.A{"$id", "$$guides"}}}},
+		bson.M{"$project": bson.M{"_id": 0, "id": 1, "name": 1, "photo": 1, "role": 1}},
+	},
+	"as": "guideprofiles",
+}}}
+
+var _ TourRepository = (*MongoTourRepository)(nil)
+
+type MongoTourRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoTourRepository(database *mongo.Database) *MongoTourRepository {
+	return &MongoTourRepository{
+		collection: database.Collection("tours"),
+	}
+}
+
+func (r *MongoTourRepository) Create(ctx context.Context, tour *models.Tour) error {
+	document := *tour
+	document.GuideProfiles = nil
+
+	_, err := r.collection.InsertOne(ctx, document)
+	if err != nil {
+		return fmt.Errorf("failed to create tour: %v", err)
+	}
+	return nil
+}
+
+func (r *MongoTourRepository) Find(ctx context.Context, filter TourFilter) ([]models.Tour, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: tourFilterToBson(filter)}},
+		{{Key: "$sort", Value: bson.D{{Key: "createdat", Value: 1}, {Key: "id", Value: 1}}}},
+	}
+	if filter.Offset > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$skip", Value: filter.Offset}})
+	}
+	if filter.Limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: filter.Limit}})
+	}
+	pipeline = append(pipeline, populateGuidesStage)
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tours: %v", err)
+	}
+
+	var tours []models.Tour
+	if err := cursor.All(ctx, &tours); err != nil {
+		return nil, fmt.Errorf("failed to decode tours: %v", err)
+	}
+	return tours, nil
+}
+
+func (r *MongoTourRepository) FindByGuides(ctx context.Context, filter TourFilter) (map[string][]models.Tour, error) {
+	page := bson.A{"$tours", filter.Offset}
+	if filter.Limit > 0 {
+		page = append(page, filter.Limit)
+	} else {
+		page = append(page, bson.M{"$max": bson.A{bson.M{"$size": "$tours"}, 1}})
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: tourFilterToBson(filter)}},
+		{{Key: "$sort", Value: bson.D{{Key: "createdat", Value: 1}, {Key: "id", Value: 1}}}},
+		{{Key: "$addFields", Value: bson.M{"pageguide": "$guides"}}},
+		{{Key: "$unwind", Value: "$pageguide"}},
+		{{Key: "$match", Value: bson.M{"pageguide": bson.M{"$in": filter.GuideIds}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$pageguide", "tours": bson.M{"$push": "$$ROOT"}}}},
+		{{Key: "$project", Value: bson.M{"tours": bson.M{"$slice": page}}}},
+		{{Key: "$unwind", Value: "$tours"}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$tours"}}},
+		populateGuidesStage,
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tours for guides: %v", err)
+	}
+
+	var tours []struct {
+		models.Tour `bson:",inline"`
+		PageGuide   string
+	}
+	if err := cursor.All(ctx, &tours); err != nil {
+		return nil, fmt.Errorf("failed to decode tours: %v", err)
+	}
+
+	pages := map[string][]models.Tour{}
+	for _, tour := range tours {
+		pages[tour.PageGuide] = append(pages[tour.PageGuide], tour.Tour)
+	}
+	return pages, nil
+}
+
+func (r *MongoTourRepository) SlugTaken(ctx context.Context, slug string, excludeId string) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{
+		"id":  bson.M{"$ne": excludeId},
+		"$or": bson.A{bson.M{"slug": slug}, bson.M{"previousslugs": slug}},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to check slug: %v", err)
+	}
+	return count > 0, nil
+}
+
+func (r *MongoTourRepository) Update(ctx context.Context, tour *models.Tour) error {
+	document := *tour
+	document.GuideProfiles = nil
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"id": tour.Id}, bson.M{"$set": document})
+	if err != nil {
+		return fmt.Errorf("failed to update tour: %v", err)
+	}
+	return nil
+}
+
+func (r *MongoTourRepository) SetAccessToken(ctx context.Context, id string, accessToken string) error {
+	update := bson.M{"$set": bson.M{"accesstoken": accessToken}}
+	if accessToken == "" {
+		update = bson.M{"$unset": bson.M{"accesstoken": ""}}
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"id": id}, update)
+	if err != nil {
+		return fmt.Errorf("failed to save access token: %v", err)
+	}
+	return nil
+}
+
+func (r *MongoTourRepository) SetRatings(ctx context.Context, id string, stats RatingStats) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"id": id}, bson.M{"$set": bson.M{
+		"ratingsavg":     stats.Average,
+		"ratingquantity": stats.Quantity,
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to update tour ratings: %v", err)
+	}
+	return nil
+}
+
+func (r *MongoTourRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete tour: %v", err)
+	}
+	return nil
+}
+
+func tourFilterToBson(filter TourFilter) bson.M {
+	conditions := bson.A{}
+
+	if filter.Id != "" {
+		conditions = append(conditions, bson.M{"id": filter.Id})
+	}
+	if filter.Ids != nil {
+		conditions = append(conditions, bson.M{"id": bson.M{"$in": filter.Ids}})
+	}
+	if filter.Slug != "" {
+		conditions = append(conditions, bson.M{"slug": filter.Slug})
+	}
+	if filter.PreviousSlug != "" {
+		conditions = append(conditions, bson.M{"previousslugs": filter.PreviousSlug})
+	}
+	if filter.GuideId != "" {
+		conditions = append(conditions, bson.M{"guides": filter.GuideId})
+	}
+	if filter.GuideIds != nil {
+		conditions = append(conditions, bson.M{"guides": bson.M{"$in": filter.GuideIds}})
+	}
+	if filter.AccessToken != "" {
+		conditions = append(conditions, bson.M{"accesstoken": filter.AccessToken})
+	} else if !filter.IncludeSecret {
+		conditions = append(conditions, bson.M{"secrettour": bson.M{"$ne": true}})
+	}
+
+	if len(conditions) == 0 {
+		return bson.M{}
+	}
+	return bson.M{"$and": conditions}
+}
@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var _ UserRepository = (*MongoUserRepository)(nil)
+
+type MongoUserRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoUserRepository(database *mongo.Database) *MongoUserRepository {
+	return &MongoUserRepository{
+		collection: database.Collection("users"),
+	}
+}
+
+func (r *MongoUserRepository) Create(ctx context.Context, user *models.User) error {
+	_, err := r.collection.InsertOne(ctx, user)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create user: %v", err)
+	}
+	return nil
+}
+
+func (r *MongoUserRepository) FindAll(ctx context.Context) ([]models.User, error) {
+	return r.find(ctx, bson.M{})
+}
+
+func (r *MongoUserRepository) FindById(ctx context.Context, id string) (*models.User, error) {
+	return r.findOne(ctx, bson.M{"id": id})
+}
+
+func (r *MongoUserRepository) FindByIds(ctx context.Context, ids []string) ([]models.User, error) {
+	return r.find(ctx, bson.M{"id": bson.M{"$in": ids}})
+}
+
+func (r *MongoUserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	return r.findOne(ctx, bson.M{"email": email})
+}
+
+func (r *MongoUserRepository) FindByPasswordResetToken(ctx context.Context, token string) (*models.User, error) {
+	if token == "" {
+		return nil, ErrNotFound
+	}
+	return r.findOne(ctx, bson.M{"passwordresettoken": token})
+}
+
+func (r *MongoUserRepository) Update(ctx context.Context, user *models.User) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"id": user.Id}, bson.M{"$set": user})
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update user: %v", err)
+	}
+	return nil
+}
+
+func (r *MongoUserRepository) ClearExpiredPasswordResetTokens(ctx context.Context, now time.Time) (int, error) {
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"passwordresettoken": bson.M{"$gt": ""}, "passwordresettokenexpiry": bson.M{"$lte": now}},
+		bson.M{"$unset": bson.M{"passwordresettoken": "", "passwordresettokenexpiry": ""}},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear expired password reset tokens: %v", err)
+	}
+	return int(result.ModifiedCount), nil
+}
+
+func (r *MongoUserRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %v", err)
+	}
+	return nil
+}
+
+func (r *MongoUserRepository) DeleteAll(ctx context.Context) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{})
+	if err != nil {
+		return fmt.Errorf("failed to delete all users: %v", err)
+	}
+	return nil
+}
+
+func (r *MongoUserRepository) find(ctx context.Context, filter bson.M) ([]models.User, error) {
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find users: %v", err)
+	}
+
+	var users []models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, fmt.Errorf("failed to decode users: %v", err)
+	}
+	return users, nil
+}
+
+func (r *MongoUserRepository) findOne(ctx context.Context, filter bson.M) (*models.User, error) {
+	var user models.User
+
+	err := r.collection.FindOne(ctx, filter).Decode(&user)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %v", err)
+	}
+	return &user, nil
+}
@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var _ WebhookRepository = (*MongoWebhookRepository)(nil)
+
+type MongoWebhookRepository struct {
+	subscriptions *mongo.Collection
+	deliveries    *mongo.Collection
+}
+
+func NewMongoWebhookRepository(database *mongo.Database) *MongoWebhookRepository {
+	return &MongoWebhookRepository{
+		subscriptions: database.Collection("webhook_subscriptions"),
+		deliveries:    database.Collection("webhook_deliveries"),
+	}
+}
+
+func (r *MongoWebhookRepository) CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	_, err := r.subscriptions.InsertOne(ctx, subscription)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook subscription: %v", err)
+	}
+	return nil
+}
+
+func (r *MongoWebhookRepository) FindSubscriptions(ctx context.Context, event string) ([]models.WebhookSubscription, error) {
+	query := bson.M{}
+	if event != "" {
+		query["active"] = true
+		query["events"] = event
+	}
+
+	cursor, err := r.subscriptions.Find(ctx, query, options.Find().SetSort(bson.D{{Key: "createdat", Value: 1}, {Key: "id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find webhook subscriptions: %v", err)
+	}
+
+	var subscriptions []models.WebhookSubscription
+	if err := cursor.All(ctx, &subscriptions); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook subscriptions: %v", err)
+	}
+	return subscriptions, nil
+}
+
+func (r *MongoWebhookRepository) FindSubscriptionById(ctx context.Context, id string) (*models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+
+	err := r.subscriptions.FindOne(ctx, bson.M{"id": id}).Decode(&subscription)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find webhook subscription: %v", err)
+	}
+	return &subscription, nil
+}
+
+func (r *MongoWebhookRepository) UpdateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	_, err := r.subscriptions.UpdateOne(ctx, bson.M{"id": subscription.Id}, bson.M{"$set": subscription})
+	if err != nil {
+		return fmt.Errorf("failed to update webhook subscription: %v", err)
+	}
+	return nil
+}
+
+func (r *MongoWebhookRepository) RecordAttempt(ctx context.Context, id string, succeeded bool) (*models.WebhookSubscription, error) {
+	update := bson.M{"$inc": bson.M{"consecutivefailures": 1}}
+	if succeeded {
+		update = bson.M{"$set": bson.M{"consecutivefailures": 0}}
+	}
+
+	var subscription models.WebhookSubscription
+	err := r.subscriptions.FindOneAndUpdate(ctx, bson.M{"id": id}, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&subscription)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to record webhook attempt: %v", err)
+	}
+	return &subscription, nil
+}
+
+func (r *MongoWebhookRepository) DeleteSubscription(ctx context.Context, id string) error {
+	_, err := r.subscriptions.DeleteOne(ctx, bson.M{"id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %v", err)
+	}
+	return nil
+}
+
+func (r *MongoWebhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	_, err := r.deliveries.InsertOne(ctx, delivery)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create webhook delivery: %v", err)
+	}
+	return nil
+}
+
+func (r *MongoWebhookRepository) FindDeliveries(ctx context.Context, filter WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	query := bson.M{}
+	if filter.SubscriptionId != "" {
+		query["subscriptionid"] = filter.SubscriptionId
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "createdat", Value: -1}, {Key: "id", Value: -1}})
+	if filter.Limit > 0 {
+		findOptions.SetLimit(int64(filter.Limit))
+	}
+
+	cursor, err := r.deliveries.Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find webhook deliveries: %v", err)
+	}
+
+	var deliveries []models.WebhookDelivery
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook deliveries: %v", err)
+	}
+	return deliveries, nil
+}
+
+func (r *MongoWebhookRepository) FindDeliveryById(ctx context.Context, id string) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+
+	err := r.deliveries.FindOne(ctx, bson.M{"id": id}).Decode(&delivery)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find webhook delivery: %v", err)
+	}
+	return &delivery, nil
+}
+
+func (r *MongoWebhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	var claimed []models.WebhookDelivery
+
+	for len(claimed) < limit {
+		var delivery models.WebhookDelivery
+		err := r.deliveries.FindOneAndUpdate(ctx,
+			bson.M{"status": models.WebhookDeliveryPending, "nextattemptat": bson.M{"$lte": now}},
+			bson.M{"$set": bson.M{"nextattemptat": now.Add(lease)}},
+			options.FindOneAndUpdate().
+				SetSort(bson.D{{Key: "nextattemptat", Value: 1}}).
+				SetReturnDocument(options.After),
+		).Decode(&delivery)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			return claimed, fmt.Errorf("failed to claim webhook deliveries: %v", err)
+		}
+		claimed = append(claimed, delivery)
+	}
+	return claimed, nil
+}
+
+func (r *MongoWebhookRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	_, err := r.deliveries.UpdateOne(ctx, bson.M{"id": delivery.Id}, bson.M{"$set": delivery})
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+)
+
+var (
+	ErrNotFound  = errors.New("document not found")
+	ErrDuplicate = errors.New("duplicate document")
+
+	ErrTransactionsUnsupported = errors.New("MongoDB is not a replica set and does not support transactions")
+)
+
+type TourFilter struct {
+	Id            string
+	Ids           []string
+	Slug          string
+	PreviousSlug  string
+	GuideId       string
+	GuideIds      []string
+	AccessToken   string
+	IncludeSecret bool
+	Limit         int
+	Offset        int
+}
+
+type ReviewFilter struct {
+	TourId  string
+	TourIds []string
+	Status  string
+	Sort    string
+	Limit   int
+	Offset  int
+}
+
+type BookingFilter struct {
+	TourIds               []string
+	UserId                string
+	CheckoutExpiredBefore time.Time
+	Limit                 int
+	Offset                int
+}
+
+type AuditFilter struct {
+	ActorId    string
+	Action     string
+	TargetType string
+	TargetId   string
+	Outcome    string
+	From       time.Time
+	To         time.Time
+	Limit      int
+}
+
+type JobFilter struct {
+	Type   string
+	Status string
+	Limit  int
+}
+
+type WebhookDeliveryFilter struct {
+	SubscriptionId string
+	Status         string
+	Limit          int
+}
+
+type RatingStats struct {
+	Average  float64
+	Quantity int
+}
+
+type UserRepository interface {
+	Create(ctx context.Context, user *models.User) error
+	FindAll(ctx context.Context) ([]models.User, error)
+	FindById(ctx context.Context, id string) (*models.User, error)
+	FindByIds(ctx context.Context, ids []string) ([]models.User, error)
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	FindByPasswordResetToken(ctx context.Context, token string) (*models.User, error)
+	Update(ctx context.Context, user *models.User) error
+	ClearExpiredPasswordResetTokens(ctx context.Context, now time.Time) (int, error)
+	Delete(ctx context.Context, id string) error
+	DeleteAll(ctx context.Context) error
+}
+
+type TourRepository interface {
+	Create(ctx context.Context, tour *models.Tour) error
+	Find(ctx context.Context, filter TourFilter) ([]models.Tour, error)
+	FindByGuides(ctx context.Context, filter TourFilter) (map[string][]models.Tour, error)
+	SlugTaken(ctx context.Context, slug string, excludeId string) (bool, error)
+	Update(ctx context.Context, tour *models.Tour) error
+	SetAccessToken(ctx context.Context, id string, accessToken string) error
+	SetRatings(ctx context.Context, id string, stats RatingStats) error
+	Delete(ctx context.Context, id string) error
+}
+
+type ReviewRepository interface {
+	Create(ctx context.Context, review *models.Review) error
+	Find(ctx context.Context, filter ReviewFilter) ([]models.Review, error)
+	FindById(ctx context.Context, id string) (*models.Review, error)
+	RatingStats(ctx context.Context, tourId string) (RatingStats, error)
+	Update(ctx context.Context, review *models.Review) error
+	Vote(ctx context.Context, id string, vote models.ReviewVote) error
+	RemoveVote(ctx context.Context, id string, userId string) (bool, error)
+	Report(ctx context.Context, id string, report models.ReviewReport) (*models.Review, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type BookingRepository interface {
+	Create(ctx context.Context, booking *models.Booking) error
+	FindAll(ctx context.Context) ([]models.Booking, error)
+	Find(ctx context.Context, filter BookingFilter) ([]models.Booking, error)
+	FindById(ctx context.Context, id string) (*models.Booking, error)
+	Update(ctx context.Context, booking *models.Booking) error
+	Delete(ctx context.Context, id string) error
+}
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error
+	FindSubscriptions(ctx context.Context, event string) ([]models.WebhookSubscription, error)
+	FindSubscriptionById(ctx context.Context, id string) (*models.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error
+	RecordAttempt(ctx context.Context, id string, succeeded bool) (*models.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id string) error
+	CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	FindDeliveries(ctx context.Context, filter WebhookDeliveryFilter) ([]models.WebhookDelivery, error)
+	FindDeliveryById(ctx context.Context, id string) (*models.WebhookDelivery, error)
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+}
+
+type Transactor interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type OutboxRepository interface {
+	Append(ctx context.Context, messages []models.OutboxMessage) error
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxMessage, error)
+	Update(ctx context.Context, message *models.OutboxMessage) error
+}
+
+type JobRepository interface {
+	Enqueue(ctx context.Context, job *models.Job) error
+	Find(ctx context.Context, filter JobFilter) ([]models.Job, error)
+	FindById(ctx context.Context, id string) (*models.Job, error)
+	ClaimDue(ctx context.Context, types []string, now time.Time, lease time.Duration, limit int) ([]models.Job, error)
+	Update(ctx context.Context, job *models.Job) error
+	ClaimSchedule(ctx context.Context, name string, now time.Time, next time.Time) (bool, error)
+}
+
+type AuditRepository interface {
+	Append(ctx context.Context, entry *models.AuditEntry) error
+	Find(ctx context.Context, filter AuditFilter) ([]models.AuditEntry, error)
+	Each(ctx context.Context, filter AuditFilter, fn func(models.AuditEntry) error) error
+}
+
+func compareCreated(aCreatedAt time.Time, aId string, bCreatedAt time.Time, bId string) int {
+	if c := aCreatedAt.Compare(bCreatedAt); c != 0 {
+		return c
+	}
+	return strings.Compare(aId, bId)
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hamid-nazari/tours-in-go/internal/controllers"
+)
+
+func SetupAuditRoutes(router *gin.RouterGroup, auth *controllers.AuthController, audit *controllers.AuditController) {
+
+	router.Use(auth.ProtectHandler, controllers.RestrictTo("admin"))
+
+	router.GET("/", audit.GetAuditEntriesHandler)
+	router.GET("/export", audit.ExportAuditEntriesHandler)
+}
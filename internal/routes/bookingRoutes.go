@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hamid-nazari/tours-in-go/internal/controllers"
+)
+
+func SetupBookingRoutes(router *gin.RouterGroup, auth *controllers.AuthController, bookings *controllers.BookingController) {
+
+	router.Use(auth.ProtectHandler)
+
+	router.GET("/checkout-session/:id", bookings.GetCheckoutSessionHandler)
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hamid-nazari/tours-in-go/internal/controllers"
+)
+
+func SetupDocsRoutes(router *gin.RouterGroup, docs *controllers.DocsController) {
+
+	router.GET("/openapi.json", docs.SpecHandler)
+	router.GET("/docs", docs.UIHandler)
+}
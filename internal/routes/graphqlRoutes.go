@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hamid-nazari/tours-in-go/internal/controllers"
+	"github.com/hamid-nazari/tours-in-go/internal/middleware"
+)
+
+func SetupGraphQLRoutes(router *gin.RouterGroup, auth *controllers.AuthController, graphql *controllers.GraphQLController) {
+
+	router.POST("/graphql", auth.OptionalProtectHandler, middleware.ScopeTours, graphql.QueryHandler)
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hamid-nazari/tours-in-go/internal/controllers"
+)
+
+func SetupHealthRoutes(router *gin.RouterGroup, health *controllers.HealthController) {
+
+	router.GET("/healthz", health.LivenessHandler)
+	router.GET("/readyz", health.ReadinessHandler)
+}
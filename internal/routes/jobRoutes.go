@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hamid-nazari/tours-in-go/internal/controllers"
+)
+
+func SetupJobRoutes(router *gin.RouterGroup, auth *controllers.AuthController, jobs *controllers.JobController) {
+
+	router.Use(auth.ProtectHandler, controllers.RestrictTo("admin"))
+
+	router.GET("/", jobs.GetAllJobsHandler)
+	router.GET("/:id", jobs.GetJobHandler)
+	router.POST("/:id/retry", jobs.RetryJobHandler)
+}
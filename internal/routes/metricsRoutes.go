@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupMetricsRoutes(router *gin.RouterGroup, handler http.Handler) {
+	router.GET("/metrics", gin.WrapH(handler))
+}
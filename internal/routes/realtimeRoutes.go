@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hamid-nazari/tours-in-go/internal/controllers"
+	"github.com/hamid-nazari/tours-in-go/internal/middleware"
+)
+
+func SetupRealtimeRoutes(router *gin.RouterGroup, auth *controllers.AuthController, realtime *controllers.RealtimeController) {
+
+	router.GET("/live", auth.OptionalProtectHandler, middleware.ScopeTours, realtime.WebSocketHandler)
+	router.GET("/:id/events", auth.OptionalProtectHandler, middleware.ScopeTours, realtime.EventStreamHandler)
+}
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hamid-nazari/tours-in-go/internal/controllers"
+)
+
+func SetupReviewRoutes(router *gin.RouterGroup, auth *controllers.AuthController, reviews *controllers.ReviewController) {
+
+	router.GET("/", reviews.GetAllReviewsHandler)
+	router.GET("/:id", reviews.GetReviewHandler)
+
+	router.Use(auth.ProtectHandler)
+
+	router.POST("/", reviews.CreateReviewHandler)
+	router.PATCH("/:id", reviews.UpdateReviewHandler)
+	router.DELETE("/:id", reviews.DeleteReviewHandler)
+	router.POST("/:id/report", reviews.ReportReviewHandler)
+	router.POST("/:id/vote", reviews.VoteOnReviewHandler)
+	router.DELETE("/:id/vote", reviews.RemoveReviewVoteHandler)
+
+	router.PUT("/:id/reply", controllers.RestrictTo("guide", "lead-guide"), reviews.ReplyToReviewHandler)
+	router.DELETE("/:id/reply", controllers.RestrictTo("guide", "lead-guide", "admin"), reviews.DeleteReviewReplyHandler)
+
+	router.GET("/moderation", controllers.RestrictTo("admin", "lead-guide"), reviews.GetModerationQueueHandler)
+	router.PATCH("/:id/moderate", controllers.RestrictTo("admin", "lead-guide"), reviews.ModerateReviewHandler)
+}
@@ -6,16 +6,21 @@ import (
 	"github.com/hamid-nazari/tours-in-go/internal/middleware"
 )
 
-func SetupTourRoutes(router *gin.RouterGroup) {
+func SetupTourRoutes(router *gin.RouterGroup, auth *controllers.AuthController, tours *controllers.TourController) {
 
-	router.POST("/", controllers.ProtectHandler, controllers.RestrictTo("admin", "lead-guide"), controllers.CreateTourHandler)
-	router.GET("/", controllers.GetAllToursHandler)
+	router.POST("/", auth.ProtectHandler, controllers.RestrictTo("admin", "lead-guide"), tours.CreateTourHandler)
+	router.GET("/", auth.OptionalProtectHandler, middleware.ScopeTours, tours.GetAllToursHandler)
 
-	router.GET("/id", controllers.ProtectHandler, controllers.RestrictTo("admin", "lead-guide"), controllers.GetTourHandler)
-	router.PATCH("/id", controllers.ProtectHandler, controllers.RestrictTo("admin", "lead-guide"), controllers.UpdateTourHandler)
-	router.DELETE("/id", controllers.ProtectHandler, controllers.RestrictTo("admin", "lead-guide"), controllers.DeleteTourHandler)
+	router.GET("/:id", auth.ProtectHandler, controllers.RestrictTo("admin", "lead-guide"), middleware.IncludeSecretTours, tours.GetTourHandler)
+	router.PATCH("/:id", auth.ProtectHandler, controllers.RestrictTo("admin", "lead-guide"), middleware.IncludeSecretTours, tours.UpdateTourHandler)
+	router.DELETE("/:id", auth.ProtectHandler, controllers.RestrictTo("admin", "lead-guide"), middleware.IncludeSecretTours, tours.DeleteTourHandler)
 
-	router.GET("/top-5-cheap", middleware.AliasTopTours, controllers.GetAllToursHandler)
+	router.POST("/:id/share-link", auth.ProtectHandler, controllers.RestrictTo("admin", "lead-guide"), middleware.IncludeSecretTours, tours.CreateTourShareLinkHandler)
+	router.DELETE("/:id/share-link", auth.ProtectHandler, controllers.RestrictTo("admin", "lead-guide"), middleware.IncludeSecretTours, tours.RevokeTourShareLinkHandler)
+	router.GET("/shared/:token", tours.GetSharedTourHandler)
+
+	router.GET("/top-5-cheap", auth.OptionalProtectHandler, middleware.ScopeTours, middleware.AliasTopTours, tours.GetAllToursHandler)
+	router.GET("/slug/:slug", auth.OptionalProtectHandler, middleware.ScopeTours, tours.GetTourBySlugHandler)
 
 	// router.GET("/tours-within/:distance/center/:latlng/unit/:unit", controllers.GetToursWithinHandler)
 	// router.GET("/distances/:latlng/unit/:unit", controllers.GetDistancesHandler)
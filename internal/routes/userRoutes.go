@@ -3,30 +3,32 @@ package routes
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/hamid-nazari/tours-in-go/internal/controllers"
+	"github.com/hamid-nazari/tours-in-go/internal/middleware"
 )
 
-func SetupUserRoutes(router *gin.RouterGroup) {
+func SetupUserRoutes(router *gin.RouterGroup, auth *controllers.AuthController, users *controllers.UserController, tours *controllers.TourController) {
 
-	router.POST("/signup", controllers.SignupHandler)
-	router.POST("/login", controllers.LoginHandler)
-	router.POST("/logout", controllers.LogoutHandler)
-	router.POST("/forgot-password", controllers.ForgotPasswordHandler)
-	router.POST("/reset-password", controllers.ResetPasswordHandler)
+	router.POST("/signup", auth.SignupHandler)
+	router.POST("/login", auth.LoginHandler)
+	router.POST("/logout", auth.LogoutHandler)
+	router.POST("/forgot-password", auth.ForgotPasswordHandler)
+	router.POST("/reset-password", auth.ResetPasswordHandler)
+	router.GET("/:id/tours", auth.OptionalProtectHandler, middleware.ScopeTours, tours.GetGuideToursHandler)
 
-	router.Use(controllers.ProtectHandler)
+	router.Use(auth.ProtectHandler)
 
-	router.PATCH("/update-password", controllers.UpdatePasswordHandler)
-	router.PATCH("/update-me", controllers.UpdateMeHandler)
-	router.DELETE("/delete-me", controllers.DeleteMeHandler)
-	router.GET("/me", controllers.GetMeHandler)
+	router.PATCH("/update-password", auth.UpdatePasswordHandler)
+	router.PATCH("/update-me", users.UpdateMeHandler)
+	router.DELETE("/delete-me", users.DeleteMeHandler)
+	router.GET("/me", users.GetMeHandler)
 
 	router.Use(controllers.RestrictTo("admin"))
 
-	router.POST("/", controllers.CreateUserHandler)
-	router.GET("/", controllers.GetAllUsersHandler)
-	router.DELETE("/", controllers.DeleteAllUsersHandler)
-	router.GET("/:id", controllers.GetUserHandler)
-	router.PATCH("/:id", controllers.UpdateUserHandler)
-	router.DELETE("/:id", controllers.DeleteUserdHandler)
+	router.POST("/", users.CreateUserHandler)
+	router.GET("/", users.GetAllUsersHandler)
+	router.DELETE("/", users.DeleteAllUsersHandler)
+	router.GET("/:id", users.GetUserHandler)
+	router.PATCH("/:id", users.UpdateUserHandler)
+	router.DELETE("/:id", users.DeleteUserdHandler)
 
 }
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hamid-nazari/tours-in-go/internal/controllers"
+)
+
+func SetupWebhookRoutes(router *gin.RouterGroup, auth *controllers.AuthController, webhooks *controllers.WebhookController) {
+
+	router.Use(auth.ProtectHandler, controllers.RestrictTo("admin"))
+
+	router.POST("/", webhooks.CreateWebhookHandler)
+	router.GET("/", webhooks.GetAllWebhooksHandler)
+	router.GET("/:id", webhooks.GetWebhookHandler)
+	router.PATCH("/:id", webhooks.UpdateWebhookHandler)
+	router.DELETE("/:id", webhooks.DeleteWebhookHandler)
+
+	router.GET("/:id/deliveries", webhooks.GetWebhookDeliveriesHandler)
+	router.POST("/:id/ping", webhooks.PingWebhookHandler)
+	router.POST("/deliveries/:deliveryId/replay", webhooks.ReplayDeliveryHandler)
+}
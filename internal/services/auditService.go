@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/hamid-nazari/tours-in-go/internal/logging"
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"github.com/hamid-nazari/tours-in-go/internal/repositories"
+)
+
+type AuditSource struct {
+	Channel   string
+	IP        string
+	UserAgent string
+	RequestId string
+}
+
+type auditSourceKey struct{}
+
+func WithAuditSource(ctx context.Context, source AuditSource) context.Context {
+	return context.WithValue(ctx, auditSourceKey{}, source)
+}
+
+func getAuditSource(ctx context.Context) AuditSource {
+	if source, ok := ctx.Value(auditSourceKey{}).(AuditSource); ok {
+		return source
+	}
+	if c, ok := ctx.(*gin.Context); ok && c.Request != nil {
+		return AuditSource{
+			Channel:   "http",
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+			RequestId: c.GetString("requestId"),
+		}
+	}
+	return AuditSource{Channel: "system"}
+}
+
+type AuditRecord struct {
+	Action     string
+	TargetType string
+	TargetId   string
+	Before     any
+	After      any
+	Failure    string
+	Actor      *models.User
+	ActorEmail string
+}
+
+type AuditService struct {
+	audit repositories.AuditRepository
+}
+
+func NewAuditService(audit repositories.AuditRepository) *AuditService {
+	return &AuditService{
+		audit: audit,
+	}
+}
+
+func (s *AuditService) Record(ctx context.Context, record AuditRecord) {
+	source := getAuditSource(ctx)
+
+	entry := &models.AuditEntry{
+		Id:         uuid.New().String(),
+		Action:     record.Action,
+		Outcome:    models.AuditSuccess,
+		Reason:     record.Failure,
+		ActorEmail: record.ActorEmail,
+		TargetType: record.TargetType,
+		TargetId:   record.TargetId,
+		Channel:    source.Channel,
+		IP:         source.IP,
+		UserAgent:  source.UserAgent,
+		RequestId:  source.RequestId,
+		CreatedAt:  time.Now(),
+	}
+	if record.Failure != "" {
+		entry.Outcome = models.AuditFailure
+	}
+
+	actor := record.Actor
+	if actor == nil {
+		actor, _ = ctx.Value("user").(*models.User)
+	}
+	if actor != nil {
+		entry.ActorId = actor.Id
+		entry.ActorEmail = actor.Email
+		entry.ActorRole = actor.Role
+	}
+
+	changes, err := AuditDiff(record.Before, record.After)
+	if err != nil {
+		logger(ctx).ErrorContext(ctx, "failed to diff audited change", "action", record.Action, "error", err)
+	}
+	entry.Changes = changes
+
+	if err := s.audit.Append(context.WithoutCancel(ctx), entry); err != nil {
+		logger(ctx).ErrorContext(ctx, "failed to write audit entry", "action", record.Action, "targetId", record.TargetId, "error", err)
+	}
+}
+
+func (s *AuditService) GetEntries(ctx context.Context, filter repositories.AuditFilter) ([]models.AuditEntry, error) {
+	return s.audit.Find(ctx, filter)
+}
+
+func (s *AuditService) ExportEntries(ctx context.Context, filter repositories.AuditFilter, fn func(models.AuditEntry) error) error {
+	return s.audit.Each(ctx, filter, fn)
+}
+
+func AuditDiff(before any, after any) ([]models.AuditChange, error) {
+	previous, err := flattenAudited(before)
+	if err != nil {
+		return nil, err
+	}
+	current, err := flattenAudited(after)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(previous)+len(current))
+	for field := range previous {
+		fields = append(fields, field)
+	}
+	for field := range current {
+		if _, ok := previous[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	var changes []models.AuditChange
+	for _, field := range fields {
+		oldValue, newValue := previous[field], current[field]
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		if logging.IsSensitive(field[strings.LastIndex(field, ".")+1:]) {
+			oldValue, newValue = redactAudited(oldValue), redactAudited(newValue)
+		}
+		changes = append(changes, models.AuditChange{Field: field, Before: oldValue, After: newValue})
+	}
+	return changes, nil
+}
+
+func redactAudited(value any) any {
+	if value == nil {
+		return nil
+	}
+	return logging.Redacted
+}
+
+func flattenAudited(value any) (map[string]any, error) {
+	fields := map[string]any{}
+	if value == nil {
+		return fields, nil
+	}
+	if reflected := reflect.ValueOf(value); reflected.Kind() == reflect.Pointer && reflected.IsNil() {
+		return fields, nil
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audited value: %v", err)
+	}
+	var decoded any
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode audited value: %v", err)
+	}
+
+	var walk func(prefix string, value any)
+	walk = func(prefix string, value any) {
+		object, ok := value.(map[string]any)
+		if !ok || len(object) == 0 {
+			fields[prefix] = value
+			return
+		}
+		for key, nested := range object {
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			walk(key, nested)
+		}
+	}
+	walk("", decoded)
+	return fields, nil
+}
+
+func AuditSnapshot(value any) any {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	return json.RawMessage(encoded)
+}
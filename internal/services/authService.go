@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hamid-nazari/tours-in-go/internal/apperrors"
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+)
+
+var errInvalidToken = errors.New("invalid token")
+
+type AuthService struct {
+	users     *UserService
+	jwtSecret string
+}
+
+func NewAuthService(users *UserService, jwtSecret string) *AuthService {
+	return &AuthService{
+		users:     users,
+		jwtSecret: jwtSecret,
+	}
+}
+
+func (s *AuthService) ParseBearerToken(authHeader string) (*models.CustomClaims, error) {
+	scheme, bearerToken, ok := strings.Cut(authHeader, " ")
+	if !ok || scheme != "Bearer" || bearerToken == "" || strings.Contains(bearerToken, " ") {
+		return nil, errInvalidToken
+	}
+
+	token, err := jwt.ParseWithClaims(bearerToken, &models.CustomClaims{}, func(t *jwt.Token) (interface{}, error) {
+		return []byte(s.jwtSecret), nil
+	})
+	if err != nil {
+		return nil, errInvalidToken
+	}
+
+	claims, ok := token.Claims.(*models.CustomClaims)
+	if !ok || !token.Valid {
+		return nil, errInvalidToken
+	}
+
+	return claims, nil
+}
+
+func (s *AuthService) Authenticate(ctx context.Context, authHeader string) (*models.User, error) {
+	if authHeader == "" {
+		return nil, apperrors.Unauthorized("Unauthorized")
+	}
+
+	claims, err := s.ParseBearerToken(authHeader)
+	if err != nil {
+		return nil, apperrors.Unauthorized("Invalid token").Wrap(err)
+	}
+
+	currentUser := s.users.FindUserById(ctx, claims.UserId)
+	if currentUser == nil {
+		return nil, apperrors.Unauthorized("User assigned to token not found")
+	}
+
+	if claims.IssuedAt == nil || currentUser.PasswordChangedAt.UTC().Truncate(time.Second).After(claims.IssuedAt.Time) {
+		return nil, apperrors.Unauthorized("User recently changed password. Please login again")
+	}
+
+	return currentUser, nil
+}
+
+func ValidAPIKey(key string, keys []string) bool {
+	valid := false
+	for _, candidate := range keys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(candidate)) == 1 {
+			valid = true
+		}
+	}
+	return key != "" && valid
+}
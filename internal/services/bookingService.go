@@ -1,24 +1,164 @@
 package services
 
 import (
-	"github.com/gin-gonic/gin"
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	"github.com/hamid-nazari/tours-in-go/internal/events"
+	"github.com/hamid-nazari/tours-in-go/internal/metrics"
 	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"github.com/hamid-nazari/tours-in-go/internal/repositories"
+	"github.com/hamid-nazari/tours-in-go/internal/tracing"
 )
 
-func CreateBooking(ctx *gin.Context, booking *models.Booking) (*models.Booking, error) {
-	return nil, nil
+const JobCancelAbandonedCheckouts = "bookings.cancel_abandoned_checkouts"
+
+type BookingService struct {
+	bookings    repositories.BookingRepository
+	metrics     *metrics.Metrics
+	events      *events.Outbox
+	checkoutTTL time.Duration
+}
+
+func NewBookingService(bookings repositories.BookingRepository, metrics *metrics.Metrics, outbox *events.Outbox, checkoutTTL time.Duration) *BookingService {
+	return &BookingService{
+		bookings:    bookings,
+		metrics:     metrics,
+		events:      outbox,
+		checkoutTTL: checkoutTTL,
+	}
+}
+
+func (s *BookingService) CreateBooking(ctx context.Context, booking *models.Booking) (*models.Booking, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "BookingService.CreateBooking")
+	defer span.End()
+	span.SetAttributes(attribute.Float64("booking.price", booking.Price))
+
+	err := s.events.Transaction(ctx, func(ctx context.Context, tx *events.Tx) error {
+		if err := s.bookings.Create(ctx, booking); err != nil {
+			return err
+		}
+		if err := tx.Record(events.BookingCreated, booking.Id, events.NewBookingData(booking)); err != nil {
+			return err
+		}
+		if booking.Paid {
+			return tx.Record(events.BookingPaid, booking.Id, events.NewBookingData(booking))
+		}
+		return nil
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to create booking")
+		return nil, err
+	}
+	s.metrics.RecordBooking()
+	if booking.Paid {
+		s.metrics.RecordBookingPaid(booking.Price)
+	}
+	return booking, nil
+}
+
+func (s *BookingService) StartCheckout(ctx context.Context, tour *models.Tour, user *models.User) (*models.Booking, error) {
+	booking := models.NewBooking()
+	booking.Tour = *tour
+	booking.User = *user
+	booking.Price = tour.Price
+	booking.CreatedAt = time.Now()
+	booking.Paid = false
+	booking.CheckoutExpiresAt = booking.CreatedAt.Add(s.checkoutTTL)
+	return s.CreateBooking(ctx, booking)
+}
+
+func (s *BookingService) GetAllBookings(ctx context.Context) ([]models.Booking, error) {
+	return s.bookings.FindAll(ctx)
 }
-func GetAllBookings(ctx *gin.Context) ([]models.Booking, error) {
-	return nil, nil
+func (s *BookingService) GetBookingsForTours(ctx context.Context, tourIds []string) ([]models.Booking, error) {
+	return s.bookings.Find(ctx, repositories.BookingFilter{TourIds: tourIds})
 }
-func GetBooking(ctx *gin.Context, booking *models.Booking) (*models.Booking, error) {
-	return nil, nil
+
+func (s *BookingService) GetBookingsForUser(ctx context.Context, userId string) ([]models.Booking, error) {
+	return s.bookings.Find(ctx, repositories.BookingFilter{UserId: userId})
+}
+
+func (s *BookingService) GetBookingPage(ctx context.Context, tourId string, userId string, limit int, offset int) ([]models.Booking, error) {
+	filter := repositories.BookingFilter{UserId: userId, Limit: limit, Offset: offset}
+	if tourId != "" {
+		filter.TourIds = []string{tourId}
+	}
+	return s.bookings.Find(ctx, filter)
+}
+
+func (s *BookingService) GetBooking(ctx context.Context, booking *models.Booking) (*models.Booking, error) {
+	return s.bookings.FindById(ctx, booking.Id)
+}
+
+func (s *BookingService) UpdateBooking(ctx context.Context, booking *models.Booking) (*models.Booking, error) {
+	if booking.Paid {
+		booking.CheckoutExpiresAt = time.Time{}
+	}
+
+	becamePaid := false
+	err := s.events.Transaction(ctx, func(ctx context.Context, tx *events.Tx) error {
+		previous, err := s.bookings.FindById(ctx, booking.Id)
+		if err != nil {
+			return err
+		}
+
+		if err := s.bookings.Update(ctx, booking); err != nil {
+			return err
+		}
+		if err := tx.Record(events.BookingUpdated, booking.Id, events.NewBookingData(booking)); err != nil {
+			return err
+		}
+		becamePaid = booking.Paid && !previous.Paid
+		if becamePaid {
+			return tx.Record(events.BookingPaid, booking.Id, events.NewBookingData(booking))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if becamePaid {
+		s.metrics.RecordBookingPaid(booking.Price)
+	}
+	return booking, nil
 }
 
-func UpdateBooking(ctx *gin.Context, booking *models.Booking) (*models.Booking, error) {
-	return nil, nil
+func (s *BookingService) DeleteBooking(ctx context.Context, booking *models.Booking) (*models.Booking, error) {
+	err := s.events.Transaction(ctx, func(ctx context.Context, tx *events.Tx) error {
+		if err := s.bookings.Delete(ctx, booking.Id); err != nil {
+			return err
+		}
+		return tx.Record(events.BookingCancelled, booking.Id, events.NewBookingData(booking))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return booking, nil
+}
+
+func (s *BookingService) CancelAbandonedCheckouts(ctx context.Context, job *models.Job) error {
+	bookings, err := s.bookings.Find(ctx, repositories.BookingFilter{CheckoutExpiredBefore: time.Now()})
+	if err != nil {
+		return fmt.Errorf("failed to find expired checkouts: %v", err)
+	}
+
+	for i := range bookings {
+		if _, err := s.DeleteBooking(ctx, &bookings[i]); err != nil {
+			return fmt.Errorf("failed to cancel booking %s: %v", bookings[i].Id, err)
+		}
+	}
+	if len(bookings) > 0 {
+		logger(ctx).InfoContext(ctx, "cancelled abandoned checkouts", "count", len(bookings))
+	}
+	return nil
 }
 
-func DeleteBooking(ctx *gin.Context, booking *models.Booking) (*models.Booking, error) {
-	return nil, nil
+func ValidateBooking(booking models.Booking) error {
+	return validate.StructExcept(booking, "Tour", "User")
 }
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hamid-nazari/tours-in-go/internal/events"
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"github.com/hamid-nazari/tours-in-go/internal/realtime"
+	"github.com/hamid-nazari/tours-in-go/internal/repositories"
+)
+
+type Availability struct {
+	StartDates   []time.Time `json:"startDates"`
+	MaxGroupSize int         `json:"maxGroupSize"`
+	Booked       int         `json:"booked"`
+	Remaining    int         `json:"remaining"`
+}
+
+func ComputeAvailability(tour *models.Tour, bookings []models.Booking) *Availability {
+	booked := 0
+	for _, booking := range bookings {
+		if booking.Paid {
+			booked++
+		}
+	}
+
+	capacity := tour.MaxGroupSize * max(len(tour.StartDates), 1)
+
+	return &Availability{
+		StartDates:   tour.StartDates,
+		MaxGroupSize: tour.MaxGroupSize,
+		Booked:       booked,
+		Remaining:    max(capacity-booked, 0),
+	}
+}
+
+type priceChange struct {
+	Price         float64 `json:"price"`
+	PreviousPrice float64 `json:"previousPrice"`
+}
+
+type reviewAuthor struct {
+	Name  string `json:"name"`
+	Photo string `json:"photo"`
+}
+
+type publishedReview struct {
+	Id        string       `json:"id"`
+	Review    string       `json:"review"`
+	Rating    int          `json:"rating"`
+	User      reviewAuthor `json:"user"`
+	CreatedAt time.Time    `json:"createdAt"`
+}
+
+type LiveUpdates struct {
+	hub      *realtime.Hub
+	tours    repositories.TourRepository
+	bookings repositories.BookingRepository
+}
+
+func NewLiveUpdates(hub *realtime.Hub, tours repositories.TourRepository, bookings repositories.BookingRepository) *LiveUpdates {
+	return &LiveUpdates{
+		hub:      hub,
+		tours:    tours,
+		bookings: bookings,
+	}
+}
+
+func (l *LiveUpdates) Subscribe(tourId string) (<-chan realtime.Event, func()) {
+	return l.hub.Subscribe(tourId)
+}
+
+func (l *LiveUpdates) Done() <-chan struct{} {
+	return l.hub.Done()
+}
+
+func (l *LiveUpdates) Snapshot(ctx context.Context, tour *models.Tour) (realtime.Event, error) {
+	bookings, err := l.bookings.Find(ctx, repositories.BookingFilter{TourIds: []string{tour.Id}})
+	if err != nil {
+		return realtime.Event{}, fmt.Errorf("failed to find bookings for tour: %v", err)
+	}
+	return realtime.NewEvent(realtime.EventAvailability, tour.Id, ComputeAvailability(tour, bookings))
+}
+
+func (l *LiveUpdates) HandleEvent(ctx context.Context, event events.Event) error {
+	switch event.Type {
+	case events.BookingCreated, events.BookingUpdated, events.BookingCancelled:
+		var booking events.BookingData
+		if err := event.Decode(&booking); err != nil {
+			return err
+		}
+		l.availabilityChanged(ctx, booking.Tour.Id)
+	case events.TourUpdated:
+		var data events.TourData
+		if err := event.Decode(&data); err != nil {
+			return err
+		}
+		if data.PreviousPrice != data.Tour.Price {
+			l.priceChanged(ctx, data.Tour.Id, data.Tour.Price, data.PreviousPrice)
+		}
+		if data.AvailabilityChanged {
+			l.availabilityChanged(ctx, data.Tour.Id)
+		}
+	case events.ReviewCreated, events.ReviewUpdated:
+		var review events.ReviewData
+		if err := event.Decode(&review); err != nil {
+			return err
+		}
+		if review.Status == models.ReviewStatusPublished {
+			l.reviewPublished(ctx, review)
+		}
+	}
+	return nil
+}
+
+func (l *LiveUpdates) availabilityChanged(ctx context.Context, tourId string) {
+	if !l.hub.Listening(tourId) {
+		return
+	}
+
+	tours, err := l.tours.Find(ctx, repositories.TourFilter{Id: tourId, IncludeSecret: true})
+	if err != nil || len(tours) == 0 {
+		logLookupError(ctx, "failed to find tour for availability update", err)
+		return
+	}
+
+	event, err := l.Snapshot(ctx, &tours[0])
+	l.publish(ctx, event, err)
+}
+
+func (l *LiveUpdates) priceChanged(ctx context.Context, tourId string, price float64, previousPrice float64) {
+	if !l.hub.Listening(tourId) {
+		return
+	}
+
+	event, err := realtime.NewEvent(realtime.EventPrice, tourId, priceChange{Price: price, PreviousPrice: previousPrice})
+	l.publish(ctx, event, err)
+}
+
+func (l *LiveUpdates) reviewPublished(ctx context.Context, review events.ReviewData) {
+	if !l.hub.Listening(review.TourId) {
+		return
+	}
+
+	event, err := realtime.NewEvent(realtime.EventReview, review.TourId, publishedReview{
+		Id:        review.Id,
+		Review:    review.Review,
+		Rating:    review.Rating,
+		User:      reviewAuthor{Name: review.UserName, Photo: review.UserPhoto},
+		CreatedAt: review.CreatedAt,
+	})
+	l.publish(ctx, event, err)
+}
+
+func (l *LiveUpdates) publish(ctx context.Context, event realtime.Event, err error) {
+	if err == nil {
+		err = l.hub.Publish(context.WithoutCancel(ctx), event)
+	}
+	if err != nil {
+		logger(ctx).WarnContext(ctx, "failed to publish live update", "tourId", event.TourId, "type", event.Type, "error", err)
+	}
+}
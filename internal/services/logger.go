@@ -0,0 +1,20 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/hamid-nazari/tours-in-go/internal/logging"
+	"github.com/hamid-nazari/tours-in-go/internal/repositories"
+)
+
+func logger(ctx context.Context) *slog.Logger {
+	return logging.Package(logging.FromContext(ctx), "services")
+}
+
+func logLookupError(ctx context.Context, message string, err error) {
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		logger(ctx).ErrorContext(ctx, message, "error", err)
+	}
+}
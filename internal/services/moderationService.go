@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hamid-nazari/tours-in-go/internal/apperrors"
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"github.com/hamid-nazari/tours-in-go/internal/repositories"
+)
+
+type ModerationRules struct {
+	BannedWords      []string
+	MaxLinks         int
+	MaxRepeatedChars int
+	MaxUppercaseRate float64
+	RequireApproval  bool
+	ReportThreshold  int
+}
+
+var linkPattern = regexp.MustCompile(`(?i)(https?://|www\.)\S+`)
+
+func (rules ModerationRules) Check(text string) []string {
+	var violations []string
+
+	lowered := strings.ToLower(text)
+	for _, word := range strings.FieldsFunc(lowered, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	}) {
+		for _, banned := range rules.BannedWords {
+			if word == banned {
+				violations = append(violations, fmt.Sprintf("contains banned word %q", banned))
+			}
+		}
+	}
+
+	if links := linkPattern.FindAllString(text, -1); len(links) > rules.MaxLinks {
+		violations = append(violations, fmt.Sprintf("contains %d links", len(links)))
+	}
+
+	if rules.MaxRepeatedChars > 0 {
+		var previous rune
+		repeated := 0
+		for _, r := range text {
+			if r == previous {
+				repeated++
+			} else {
+				previous, repeated = r, 1
+			}
+			if repeated > rules.MaxRepeatedChars {
+				violations = append(violations, "contains repeated characters")
+				break
+			}
+		}
+	}
+
+	letters, uppercase := 0, 0
+	for _, r := range text {
+		if r >= 'a' && r <= 'z' {
+			letters++
+		} else if r >= 'A' && r <= 'Z' {
+			letters++
+			uppercase++
+		}
+	}
+	if rules.MaxUppercaseRate > 0 && letters >= 10 && float64(uppercase)/float64(letters) > rules.MaxUppercaseRate {
+		violations = append(violations, "contains too many uppercase letters")
+	}
+
+	return violations
+}
+
+func (s *ReviewService) ModerateNewReview(review *models.Review) {
+	violations := s.rules.Check(review.Review)
+
+	review.ModeratedBy = ""
+	review.ModeratedAt = time.Time{}
+
+	if len(violations) > 0 {
+		review.Status = models.ReviewStatusPending
+		review.ModerationReason = strings.Join(violations, "; ")
+		return
+	}
+
+	if s.reportedTooOften(review) {
+		review.Status = models.ReviewStatusPending
+		review.ModerationReason = reportedReason(review)
+		return
+	}
+
+	review.ModerationReason = ""
+	if s.rules.RequireApproval {
+		review.Status = models.ReviewStatusPending
+	} else {
+		review.Status = models.ReviewStatusPublished
+	}
+}
+
+func (s *ReviewService) GetModerationQueue(ctx context.Context) ([]models.Review, error) {
+	reviews, err := s.reviews.Find(ctx, repositories.ReviewFilter{Status: models.ReviewStatusPending, Sort: "oldest"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pending reviews: %v", err)
+	}
+	return reviews, nil
+}
+
+func (s *ReviewService) ModerateReview(ctx context.Context, review *models.Review, moderator *models.User, status string, reason string) error {
+	if status != models.ReviewStatusPublished && status != models.ReviewStatusRejected {
+		return apperrors.InvalidField("status", "oneof", fmt.Sprintf("must be one of: %s, %s", models.ReviewStatusPublished, models.ReviewStatusRejected))
+	}
+	if status == models.ReviewStatusRejected && reason == "" {
+		return apperrors.InvalidField("reason", "required", "is required when rejecting a review")
+	}
+
+	previousStatus := review.Status
+
+	review.Status = status
+	review.ModerationReason = reason
+	review.ModeratedBy = moderator.Id
+	review.ModeratedAt = time.Now()
+
+	return s.updateReviewStatus(ctx, review, previousStatus)
+}
+
+func (s *ReviewService) ReportReview(ctx context.Context, review *models.Review, reporter *models.User, reason string) error {
+	reported, err := s.reviews.Report(ctx, review.Id, models.ReviewReport{
+		UserId:    reporter.Id,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	})
+	if errors.Is(err, repositories.ErrDuplicate) {
+		return apperrors.Conflict("You have already reported this review")
+	}
+	if err != nil {
+		return err
+	}
+	*review = *reported
+
+	previousStatus := review.Status
+	if previousStatus != models.ReviewStatusPublished || !s.reportedTooOften(review) {
+		return nil
+	}
+
+	review.Status = models.ReviewStatusPending
+	review.ModerationReason = reportedReason(review)
+	return s.updateReviewStatus(ctx, review, previousStatus)
+}
+
+func (s *ReviewService) reportedTooOften(review *models.Review) bool {
+	return s.rules.ReportThreshold > 0 && len(review.Reports) >= s.rules.ReportThreshold
+}
+
+func reportedReason(review *models.Review) string {
+	return fmt.Sprintf("reported by %d users", len(review.Reports))
+}
@@ -1,67 +1,259 @@
 package services
 
 import (
-	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hamid-nazari/tours-in-go/internal/apperrors"
+	"github.com/hamid-nazari/tours-in-go/internal/events"
 	"github.com/hamid-nazari/tours-in-go/internal/models"
-	"github.com/hamid-nazari/tours-in-go/internal/utils"
-	"go.mongodb.org/mongo-driver/bson"
+	"github.com/hamid-nazari/tours-in-go/internal/repositories"
 )
 
-func CreateReview(ctx *gin.Context, review *models.Review) error {
-	collection := utils.GetCollection(TourDatabaseClient, "reviews")
+type ReviewService struct {
+	reviews repositories.ReviewRepository
+	tours   repositories.TourRepository
+	rules   ModerationRules
+	events  *events.Outbox
+}
 
-	_, err := collection.InsertOne(ctx, review)
-	if err != nil {
+func NewReviewService(reviews repositories.ReviewRepository, tours repositories.TourRepository, rules ModerationRules, outbox *events.Outbox) *ReviewService {
+	return &ReviewService{
+		reviews: reviews,
+		tours:   tours,
+		rules:   rules,
+		events:  outbox,
+	}
+}
+
+func (s *ReviewService) CreateReview(ctx context.Context, review *models.Review) error {
+	s.ModerateNewReview(review)
+
+	return s.events.Transaction(ctx, func(ctx context.Context, tx *events.Tx) error {
+		if err := s.reviews.Create(ctx, review); err != nil {
+			return err
+		}
+		return tx.Record(events.ReviewCreated, review.Id, events.NewReviewData(review, ""))
+	})
+}
+
+func (s *ReviewService) updateReviewStatus(ctx context.Context, review *models.Review, previousStatus string) error {
+	return s.events.Transaction(ctx, func(ctx context.Context, tx *events.Tx) error {
+		if err := s.reviews.Update(ctx, review); err != nil {
+			return err
+		}
+		return tx.Record(events.ReviewUpdated, review.Id, events.NewReviewData(review, previousStatus))
+	})
+}
+
+func (s *ReviewService) HandleEvent(ctx context.Context, event events.Event) error {
+	var review events.ReviewData
+	if err := event.Decode(&review); err != nil {
 		return err
 	}
+
+	if review.Status == models.ReviewStatusPublished || review.PreviousStatus == models.ReviewStatusPublished {
+		return s.CalculateTourRatings(ctx, review.TourId)
+	}
 	return nil
 }
 
-func GetAllReviews(ctx *gin.Context) []models.Review {
-	collection := utils.GetCollection(TourDatabaseClient, "reviews")
+func (s *ReviewService) ImportReview(ctx context.Context, review *models.Review, trustStatus bool) error {
+	if err := ValidateReview(*review); err != nil {
+		return err
+	}
 
-	var reviews []models.Review
+	tours, err := s.tours.Find(ctx, repositories.TourFilter{Id: review.Tour.Id, IncludeSecret: true})
+	if err != nil {
+		return err
+	}
+	if len(tours) == 0 {
+		return apperrors.NotFound(fmt.Sprintf("Tour %s does not exist", review.Tour.Id))
+	}
+	if review.Votes == nil {
+		review.Votes = []models.ReviewVote{}
+	}
+	if review.Reports == nil {
+		review.Reports = []models.ReviewReport{}
+	}
+	if !trustStatus && review.Status == models.ReviewStatusPublished {
+		s.ModerateNewReview(review)
+	}
 
-	cursor, err := collection.Find(ctx, bson.M{})
+	if err := s.reviews.Create(ctx, review); err != nil {
+		return err
+	}
+
+	if review.Status == models.ReviewStatusPublished {
+		return s.CalculateTourRatings(ctx, review.Tour.Id)
+	}
+	return nil
+}
+
+func (s *ReviewService) GetAllReviews(ctx context.Context, sort string) []models.Review {
+	reviews, err := s.reviews.Find(ctx, repositories.ReviewFilter{Status: models.ReviewStatusPublished, Sort: sort})
 	if err != nil {
+		logLookupError(ctx, "failed to find reviews", err)
 		return nil
 	}
-	for cursor.Next(ctx) {
-		var review models.Review
-		cursor.Decode(&review)
-		reviews = append(reviews, review)
-	}
 	return reviews
 }
 
-func GetReviewById(ctx *gin.Context, id string) *models.Review {
-	return nil
+func (s *ReviewService) GetReviewPage(ctx context.Context, tourId string, sort string, limit int, offset int) ([]models.Review, error) {
+	reviews, err := s.reviews.Find(ctx, repositories.ReviewFilter{TourId: tourId, Status: models.ReviewStatusPublished, Sort: sort, Limit: limit, Offset: offset})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find reviews: %v", err)
+	}
+	return reviews, nil
+}
+
+func (s *ReviewService) GetReviewsForTours(ctx context.Context, tourIds []string, sort string) ([]models.Review, error) {
+	reviews, err := s.reviews.Find(ctx, repositories.ReviewFilter{TourIds: tourIds, Status: models.ReviewStatusPublished, Sort: sort})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find reviews for tours: %v", err)
+	}
+	return reviews, nil
 }
+
+func (s *ReviewService) GetReviewById(ctx context.Context, id string) *models.Review {
+	review, err := s.reviews.FindById(ctx, id)
+	if err != nil {
+		logLookupError(ctx, "failed to find review", err)
+		return nil
+	}
+	return review
+}
+
+func (s *ReviewService) CalculateTourRatings(ctx context.Context, tourId string) error {
+	stats, err := s.reviews.RatingStats(ctx, tourId)
+	if err != nil {
+		return err
+	}
+
+	if stats.Quantity == 0 {
+		stats.Average = 4.5
+	}
+
+	return s.tours.SetRatings(ctx, tourId, stats)
+}
+
 func ValidateReview(review models.Review) error {
-	validator := validator.New()
-	err := validator.Struct(review)
+	return validate.StructExcept(review, "Tour", "User")
+}
+
+func (s *ReviewService) UpdateReview(ctx context.Context, review *models.Review) error {
+	return s.reviews.Update(ctx, review)
+}
+
+func (s *ReviewService) EditReview(ctx context.Context, review *models.Review) error {
+	previousStatus := review.Status
+
+	s.ModerateNewReview(review)
+
+	return s.updateReviewStatus(ctx, review, previousStatus)
+}
+
+func (s *ReviewService) DeleteReview(ctx context.Context, review *models.Review) error {
+	return s.events.Transaction(ctx, func(ctx context.Context, tx *events.Tx) error {
+		if err := s.reviews.Delete(ctx, review.Id); err != nil {
+			return err
+		}
+
+		data := events.NewReviewData(review, review.Status)
+		data.Status = ""
+		return tx.Record(events.ReviewDeleted, review.Id, data)
+	})
+}
+
+func IsTourGuide(tour *models.Tour, user *models.User) bool {
+	if user.Role != "guide" && user.Role != "lead-guide" {
+		return false
+	}
+	for _, guideId := range tour.Guides {
+		if guideId == user.Id {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *ReviewService) ReplyToReview(ctx context.Context, review *models.Review, guide *models.User, text string) error {
+	tours, err := s.tours.Find(ctx, repositories.TourFilter{Id: review.Tour.Id, IncludeSecret: true})
 	if err != nil {
 		return err
 	}
-	return nil
+	if len(tours) == 0 {
+		return apperrors.NotFound("Tour not found")
+	}
+	tour := &tours[0]
+	if !IsTourGuide(tour, guide) {
+		return apperrors.Forbidden("Only guides assigned to this tour can reply to its reviews")
+	}
+
+	if review.Reply != nil && review.Reply.GuideId != guide.Id {
+		return apperrors.Conflict("This review already has a reply from another guide")
+	}
+
+	now := time.Now()
+	reply := &models.ReviewReply{
+		GuideId:   guide.Id,
+		GuideName: guide.Name,
+		Reply:     text,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if review.Reply != nil {
+		reply.CreatedAt = review.Reply.CreatedAt
+	}
+
+	if err := validate.Struct(reply); err != nil {
+		return apperrors.Validation(err)
+	}
+
+	review.Reply = reply
+	return s.UpdateReview(ctx, review)
+}
+
+func (s *ReviewService) DeleteReviewReply(ctx context.Context, review *models.Review, user *models.User) error {
+	if review.Reply == nil {
+		return apperrors.NotFound("Review has no reply")
+	}
+	if review.Reply.GuideId != user.Id && user.Role != "admin" {
+		return apperrors.Forbidden("Only the author of the reply can delete it")
+	}
+
+	review.Reply = nil
+	return s.UpdateReview(ctx, review)
 }
 
-func UpdateReview(ctx *gin.Context, review *models.Review) error {
-	collection := utils.GetCollection(TourDatabaseClient, "reviews")
+func (s *ReviewService) VoteOnReview(ctx context.Context, review *models.Review, user *models.User, helpful bool) error {
+	if review.User.Id == user.Id {
+		return apperrors.BadRequest("You cannot vote on your own review")
+	}
 
-	_, err := collection.UpdateOne(ctx, bson.M{"id": review.Id}, bson.M{"$set": review})
+	if err := s.reviews.Vote(ctx, review.Id, models.ReviewVote{UserId: user.Id, Helpful: helpful}); err != nil {
+		return err
+	}
+	return s.reloadReview(ctx, review)
+}
+
+func (s *ReviewService) RemoveReviewVote(ctx context.Context, review *models.Review, user *models.User) error {
+	removed, err := s.reviews.RemoveVote(ctx, review.Id, user.Id)
 	if err != nil {
 		return err
 	}
-	return nil
+	if !removed {
+		return apperrors.BadRequest("You have not voted on this review")
+	}
+	return s.reloadReview(ctx, review)
 }
-func DeleteReview(ctx *gin.Context, reviewId string) error {
-	collection := utils.GetCollection(TourDatabaseClient, "reviews")
 
-	_, err := collection.DeleteOne(ctx, bson.M{"id": reviewId})
+func (s *ReviewService) reloadReview(ctx context.Context, review *models.Review) error {
+	updated, err := s.reviews.FindById(ctx, review.Id)
 	if err != nil {
 		return err
 	}
+	*review = *updated
 	return nil
 }
@@ -1,73 +1,296 @@
 package services
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
+	"github.com/gosimple/slug"
+	"github.com/hamid-nazari/tours-in-go/internal/apperrors"
+	"github.com/hamid-nazari/tours-in-go/internal/events"
 	"github.com/hamid-nazari/tours-in-go/internal/models"
-	"github.com/hamid-nazari/tours-in-go/internal/utils"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
+	"github.com/hamid-nazari/tours-in-go/internal/repositories"
 )
 
-var TourDatabaseClient *mongo.Client
+type TourScope struct {
+	IncludeSecret bool
+}
 
-func CreateTour(ctx *gin.Context, tour *models.Tour) error {
-	collection := utils.GetCollection(TourDatabaseClient, "tours")
-	_, err := collection.InsertOne(ctx, tour)
-	if err != nil {
-		return err
+type tourScopeKey struct{}
+
+func SetTourScope(c *gin.Context, scope TourScope) {
+	c.Request = c.Request.WithContext(WithTourScope(c.Request.Context(), scope))
+}
+
+func WithTourScope(ctx context.Context, scope TourScope) context.Context {
+	return context.WithValue(ctx, tourScopeKey{}, scope)
+}
+
+func GetTourScope(ctx context.Context) TourScope {
+	if scope, ok := ctx.Value(tourScopeKey{}).(TourScope); ok {
+		return scope
 	}
-	return nil
+	return TourScope{}
 }
 
-func GetAllTours(ctx *gin.Context) []models.Tour {
-	collection := utils.GetCollection(TourDatabaseClient, "tours")
-	var tours []models.Tour
-	cursor, err := collection.Find(ctx, bson.M{})
-	if err != nil {
+type TourService struct {
+	tours  repositories.TourRepository
+	users  repositories.UserRepository
+	events *events.Outbox
+}
+
+func NewTourService(tours repositories.TourRepository, users repositories.UserRepository, outbox *events.Outbox) *TourService {
+	return &TourService{
+		tours:  tours,
+		users:  users,
+		events: outbox,
+	}
+}
+
+func (s *TourService) findTours(ctx context.Context, filter repositories.TourFilter) ([]models.Tour, error) {
+	filter.IncludeSecret = filter.IncludeSecret || GetTourScope(ctx).IncludeSecret
+	return s.tours.Find(ctx, filter)
+}
+
+func (s *TourService) findTour(ctx context.Context, filter repositories.TourFilter) *models.Tour {
+	tours, err := s.findTours(ctx, filter)
+	logLookupError(ctx, "failed to find tour", err)
+	if err != nil || len(tours) == 0 {
 		return nil
 	}
-	for cursor.Next(ctx) {
-		var tour models.Tour
-		cursor.Decode(&tour)
-		tours = append(tours, tour)
+	return &tours[0]
+}
+
+func (s *TourService) CreateTour(ctx context.Context, tour *models.Tour) error {
+	return s.events.Transaction(ctx, func(ctx context.Context, tx *events.Tx) error {
+		if err := s.tours.Create(ctx, tour); err != nil {
+			return err
+		}
+		return tx.Record(events.TourCreated, tour.Id, events.TourData{Tour: *tour})
+	})
+}
+
+func (s *TourService) GetAllTours(ctx context.Context) []models.Tour {
+	tours, err := s.findTours(ctx, repositories.TourFilter{})
+	if err != nil {
+		logLookupError(ctx, "failed to find tours", err)
+		return nil
 	}
 	return tours
 }
 
-func FindTourById(ctx *gin.Context, id string) *models.Tour {
-	collection := utils.GetCollection(TourDatabaseClient, "tours")
+func (s *TourService) GetTourPage(ctx context.Context, guideId string, limit int, offset int) ([]models.Tour, error) {
+	tours, err := s.findTours(ctx, repositories.TourFilter{GuideId: guideId, Limit: limit, Offset: offset})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tours: %v", err)
+	}
+	return tours, nil
+}
 
-	var tour models.Tour
+func (s *TourService) GetToursByGuide(ctx context.Context, guideId string) ([]models.Tour, error) {
+	tours, err := s.findTours(ctx, repositories.TourFilter{GuideId: guideId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tours for guide: %v", err)
+	}
+	return tours, nil
+}
+
+func (s *TourService) GetToursByGuides(ctx context.Context, guideIds []string, limit int, offset int) (map[string][]models.Tour, error) {
+	filter := repositories.TourFilter{GuideIds: guideIds, Limit: limit, Offset: offset}
+	filter.IncludeSecret = GetTourScope(ctx).IncludeSecret
 
-	err := collection.FindOne(ctx, bson.M{"id": id}).Decode(&tour)
+	tours, err := s.tours.FindByGuides(ctx, filter)
 	if err != nil {
-		return nil
+		return nil, fmt.Errorf("failed to find tours for guides: %v", err)
 	}
-	return &tour
+	return tours, nil
 }
-func UpdateTour(ctx *gin.Context, tour *models.Tour) error {
-	collection := utils.GetCollection(TourDatabaseClient, "tours")
-	_, err := collection.UpdateOne(ctx, bson.M{"id": tour.Id}, bson.M{"$set": tour})
+
+func (s *TourService) FindToursByIds(ctx context.Context, ids []string) ([]models.Tour, error) {
+	tours, err := s.findTours(ctx, repositories.TourFilter{Ids: ids})
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to find tours: %v", err)
+	}
+	return tours, nil
+}
+
+func (s *TourService) FindTourBySlug(ctx context.Context, tourSlug string) *models.Tour {
+	return s.findTour(ctx, repositories.TourFilter{Slug: tourSlug})
+}
+
+func (s *TourService) FindTourByPreviousSlug(ctx context.Context, tourSlug string) *models.Tour {
+	return s.findTour(ctx, repositories.TourFilter{PreviousSlug: tourSlug})
+}
+
+func (s *TourService) GenerateTourSlug(ctx context.Context, tour *models.Tour) error {
+	base := slug.Make(tour.Name)
+	if base == "" {
+		base = tour.Id
+	}
+
+	if tour.Slug == base || strings.HasPrefix(tour.Slug, base+"-") && isNumeric(strings.TrimPrefix(tour.Slug, base+"-")) {
+		taken, err := s.tours.SlugTaken(ctx, tour.Slug, tour.Id)
+		if err != nil {
+			return fmt.Errorf("failed to generate slug: %v", err)
+		}
+		if !taken {
+			return nil
+		}
+		tour.Slug = ""
+	}
+
+	candidate := base
+	for suffix := 2; ; suffix++ {
+		taken, err := s.tours.SlugTaken(ctx, candidate, tour.Id)
+		if err != nil {
+			return fmt.Errorf("failed to generate slug: %v", err)
+		}
+		if !taken {
+			break
+		}
+		candidate = fmt.Sprintf("%s-%d", base, suffix)
 	}
+
+	var previousSlugs []string
+	for _, previousSlug := range tour.PreviousSlugs {
+		if previousSlug != candidate {
+			previousSlugs = append(previousSlugs, previousSlug)
+		}
+	}
+	if tour.Slug != "" {
+		previousSlugs = append(previousSlugs, tour.Slug)
+	}
+
+	tour.PreviousSlugs = previousSlugs
+	tour.Slug = candidate
 	return nil
 }
 
-func DeleteTour(ctx *gin.Context, tour *models.Tour) error {
-	collection := utils.GetCollection(TourDatabaseClient, "tours")
-	_, err := collection.DeleteOne(ctx, bson.M{"id": tour.Id})
-	if err != nil {
-		return err
+func isNumeric(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *TourService) FindTourByAccessToken(ctx context.Context, accessToken string) *models.Tour {
+	if accessToken == "" {
+		return nil
+	}
+	return s.findTour(ctx, repositories.TourFilter{AccessToken: accessToken})
+}
+
+func (s *TourService) FindSharedTourById(ctx context.Context, id string, accessToken string) *models.Tour {
+	if tour := s.FindTourById(ctx, id); tour != nil || accessToken == "" {
+		return tour
+	}
+	return s.findTour(ctx, repositories.TourFilter{Id: id, AccessToken: accessToken})
+}
+
+func (s *TourService) CreateTourAccessToken(ctx context.Context, tour *models.Tour) (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("failed to generate access token: %v", err)
+	}
+
+	accessToken := hex.EncodeToString(token)
+	if err := s.tours.SetAccessToken(ctx, tour.Id, accessToken); err != nil {
+		return "", err
+	}
+
+	tour.AccessToken = accessToken
+	return accessToken, nil
+}
+
+func (s *TourService) RevokeTourAccessToken(ctx context.Context, tour *models.Tour) error {
+	if err := s.tours.SetAccessToken(ctx, tour.Id, ""); err != nil {
+		return fmt.Errorf("failed to revoke access token: %v", err)
 	}
+
+	tour.AccessToken = ""
 	return nil
 }
 
+func (s *TourService) FindTourById(ctx context.Context, id string) *models.Tour {
+	return s.findTour(ctx, repositories.TourFilter{Id: id})
+}
+
+func (s *TourService) UpdateTour(ctx context.Context, tour *models.Tour) error {
+	return s.events.Transaction(ctx, func(ctx context.Context, tx *events.Tx) error {
+		previous, err := s.tours.Find(ctx, repositories.TourFilter{Id: tour.Id, IncludeSecret: true})
+		if err != nil {
+			return err
+		}
+
+		if err := s.tours.Update(ctx, tour); err != nil {
+			return err
+		}
+
+		data := events.TourData{Tour: *tour, PreviousPrice: tour.Price}
+		if len(previous) > 0 {
+			data.PreviousPrice = previous[0].Price
+			data.AvailabilityChanged = previous[0].MaxGroupSize != tour.MaxGroupSize || !slices.EqualFunc(previous[0].StartDates, tour.StartDates, time.Time.Equal)
+		}
+		return tx.Record(events.TourUpdated, tour.Id, data)
+	})
+}
+
+func (s *TourService) DeleteTour(ctx context.Context, tour *models.Tour) error {
+	return s.events.Transaction(ctx, func(ctx context.Context, tx *events.Tx) error {
+		if err := s.tours.Delete(ctx, tour.Id); err != nil {
+			return err
+		}
+		return tx.Record(events.TourDeleted, tour.Id, events.TourData{Tour: *tour})
+	})
+}
+
 func ValidateTour(tour models.Tour) error {
-	err := validator.New().Struct(tour)
+	return validate.Struct(tour)
+}
+
+func (s *TourService) ValidateTourGuides(ctx context.Context, tour *models.Tour) error {
+	if len(tour.Guides) == 0 {
+		return nil
+	}
+
+	seen := map[string]bool{}
+	var guideIds []string
+	for _, guideId := range tour.Guides {
+		if !seen[guideId] {
+			seen[guideId] = true
+			guideIds = append(guideIds, guideId)
+		}
+	}
+	tour.Guides = guideIds
+
+	guides, err := s.users.FindByIds(ctx, guideIds)
 	if err != nil {
-		return err.(validator.ValidationErrors)
+		return fmt.Errorf("failed to find guides: %v", err)
+	}
+
+	found := map[string]string{}
+	for _, guide := range guides {
+		found[guide.Id] = guide.Role
+	}
+
+	for _, guideId := range guideIds {
+		role, ok := found[guideId]
+		if !ok {
+			return apperrors.InvalidField("guides", "exists", fmt.Sprintf("guide %s does not exist", guideId))
+		}
+		if role != "guide" && role != "lead-guide" {
+			return apperrors.InvalidField("guides", "role", fmt.Sprintf("user %s is not a guide", guideId))
+		}
 	}
 	return nil
 }
@@ -1,113 +1,112 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"time"
 
-	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
+	"github.com/hamid-nazari/tours-in-go/internal/apperrors"
+	"github.com/hamid-nazari/tours-in-go/internal/events"
 	"github.com/hamid-nazari/tours-in-go/internal/models"
-	"github.com/hamid-nazari/tours-in-go/internal/utils"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
+	"github.com/hamid-nazari/tours-in-go/internal/repositories"
 	"golang.org/x/crypto/bcrypt"
 )
 
-var UserDatabaseClient *mongo.Client
+var ErrEmailTaken = apperrors.Conflict("A user with this email already exists")
 
-func CreateUser(cxt *gin.Context, user *models.User) error {
-	collection := utils.GetCollection(UserDatabaseClient, "users")
-	_, err := collection.InsertOne(cxt, user)
-	if err != nil {
-		return err
-	}
+const JobExpireResetTokens = "users.expire_reset_tokens"
 
-	return nil
+type UserService struct {
+	users  repositories.UserRepository
+	events *events.Outbox
 }
 
-func GetAllUsers(ctx *gin.Context) ([]models.User, error) {
-	collection := utils.GetCollection(UserDatabaseClient, "users")
-
-	var users []models.User
-
-	cursor, err := collection.Find(ctx, bson.M{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to find users: %v", err)
+func NewUserService(users repositories.UserRepository, outbox *events.Outbox) *UserService {
+	return &UserService{
+		users:  users,
+		events: outbox,
 	}
+}
 
-	for cursor.Next(ctx) {
-		var user models.User
-		cursor.Decode(&user)
-		users = append(users, user)
+func (s *UserService) CreateUser(ctx context.Context, user *models.User) error {
+	err := s.users.Create(ctx, user)
+	if errors.Is(err, repositories.ErrDuplicate) {
+		return ErrEmailTaken
 	}
-
-	return users, nil
+	return err
 }
 
-func FindUserByEmail(ctx *gin.Context, email string) *models.User {
-	collection := utils.GetCollection(UserDatabaseClient, "users")
-
-	var user models.User
-
-	err := collection.FindOne(ctx, bson.M{"email": email}).Decode(&user)
+func (s *UserService) ExpirePasswordResetTokens(ctx context.Context, job *models.Job) error {
+	cleared, err := s.users.ClearExpiredPasswordResetTokens(ctx, time.Now())
 	if err != nil {
-		return nil
+		return err
 	}
-
-	return &user
+	if cleared > 0 {
+		logger(ctx).InfoContext(ctx, "cleared expired password reset tokens", "count", cleared)
+	}
+	return nil
 }
 
-func FindUserById(ctx *gin.Context, id string) *models.User {
-	collection := utils.GetCollection(UserDatabaseClient, "users")
-
-	var user models.User
+func (s *UserService) SignUp(ctx context.Context, user *models.User) error {
+	return s.events.Transaction(ctx, func(ctx context.Context, tx *events.Tx) error {
+		if err := s.CreateUser(ctx, user); err != nil {
+			return err
+		}
+		return tx.Record(events.UserSignedUp, user.Id, events.NewUserData(user))
+	})
+}
 
-	err := collection.FindOne(ctx, bson.M{"id": id}).Decode(&user)
+func (s *UserService) GetAllUsers(ctx context.Context) ([]models.User, error) {
+	users, err := s.users.FindAll(ctx)
 	if err != nil {
-		return nil
+		return nil, fmt.Errorf("failed to find users: %v", err)
 	}
 
-	return &user
+	return users, nil
 }
 
-func UpdateUser(ctx *gin.Context, user *models.User) error {
-	collection := utils.GetCollection(UserDatabaseClient, "users")
-
-	_, err := collection.UpdateOne(ctx, bson.M{"id": user.Id}, bson.M{"$set": user})
+func (s *UserService) FindUsersByIds(ctx context.Context, ids []string) ([]models.User, error) {
+	users, err := s.users.FindByIds(ctx, ids)
 	if err != nil {
-		return fmt.Errorf("failed to update user: %v", err)
+		return nil, fmt.Errorf("failed to find users: %v", err)
 	}
+	return users, nil
+}
 
-	return nil
+func (s *UserService) FindUserByEmail(ctx context.Context, email string) *models.User {
+	user, err := s.users.FindByEmail(ctx, email)
+	return userOrNil(ctx, user, err)
 }
 
-func DeleteUser(ctx *gin.Context, id string) error {
-	collection := utils.GetCollection(UserDatabaseClient, "users")
+func (s *UserService) FindUserById(ctx context.Context, id string) *models.User {
+	user, err := s.users.FindById(ctx, id)
+	return userOrNil(ctx, user, err)
+}
 
-	_, err := collection.DeleteOne(ctx, bson.M{"id": id})
-	if err != nil {
-		return fmt.Errorf("failed to delete user: %v", err)
+func (s *UserService) UpdateUser(ctx context.Context, user *models.User) error {
+	err := s.users.Update(ctx, user)
+	if errors.Is(err, repositories.ErrDuplicate) {
+		return ErrEmailTaken
 	}
-
-	return nil
+	return err
 }
 
-func DeleteAllUsers(ctx *gin.Context) error {
-	collection := utils.GetCollection(UserDatabaseClient, "users")
+func (s *UserService) DeleteUser(ctx context.Context, id string) error {
+	return s.users.Delete(ctx, id)
+}
 
-	_, err := collection.DeleteMany(ctx, bson.M{})
-	if err != nil {
-		return fmt.Errorf("failed to delete all users: %v", err)
-	}
+func (s *UserService) DeleteAllUsers(ctx context.Context) error {
+	return s.users.DeleteAll(ctx)
+}
 
-	return nil
+func (s *UserService) FindUserByPasswordResetToken(ctx context.Context, token string) *models.User {
+	user, err := s.users.FindByPasswordResetToken(ctx, token)
+	return userOrNil(ctx, user, err)
 }
 
 func ValidateUser(user models.User) error {
-	err := validator.New().Struct(user)
-	if err != nil {
-		return fmt.Errorf("invalid user: %v", err.(validator.ValidationErrors))
-	}
-	return nil
+	return validate.Struct(user)
 }
 
 func HashPassword(password string) (string, error) {
@@ -119,23 +118,10 @@ func HashPassword(password string) (string, error) {
 }
 
 func VerifyPassword(providedPassword string, hashedPassword string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(providedPassword), []byte(hashedPassword))
+	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(providedPassword))
 	return err == nil
 }
 
-func FindUserByPasswordResetToken(ctx *gin.Context, token string) *models.User {
-	collection := utils.GetCollection(UserDatabaseClient, "users")
-
-	var user models.User
-
-	err := collection.FindOne(ctx, bson.M{"passwordResetToken": token}).Decode(&user)
-	if err != nil {
-		return nil
-	}
-
-	return &user
-}
-
 func FindActiveUsers(users []models.User) []models.User {
 
 	var activeUsers []models.User
@@ -148,3 +134,11 @@ func FindActiveUsers(users []models.User) []models.User {
 
 	return activeUsers
 }
+
+func userOrNil(ctx context.Context, user *models.User, err error) *models.User {
+	if err != nil {
+		logLookupError(ctx, "failed to find user", err)
+		return nil
+	}
+	return user
+}
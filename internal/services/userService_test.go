@@ -0,0 +1,20 @@
+package services
+
+import "testing"
+
+func TestVerifyPassword(t *testing.T) {
+	hashedPassword, err := HashPassword("pass1234")
+	if err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+
+	if !VerifyPassword("pass1234", hashedPassword) {
+		t.Error("the correct password was rejected")
+	}
+	if VerifyPassword("wrong-password", hashedPassword) {
+		t.Error("a wrong password was accepted")
+	}
+	if VerifyPassword(hashedPassword, "pass1234") {
+		t.Error("a hash passed as the provided password was accepted")
+	}
+}
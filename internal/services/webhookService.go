@@ -0,0 +1,398 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hamid-nazari/tours-in-go/internal/apperrors"
+	"github.com/hamid-nazari/tours-in-go/internal/events"
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"github.com/hamid-nazari/tours-in-go/internal/repositories"
+	"github.com/hamid-nazari/tours-in-go/internal/utils"
+)
+
+const (
+	maxDeliveryLog      = 100
+	maxWebhookResponse  = 64 << 10
+	webhookSecretPrefix = "whsec_"
+)
+
+type WebhookPolicy struct {
+	Timeout        time.Duration
+	MaxAttempts    int
+	RetryBaseDelay time.Duration
+	MaxRetryDelay  time.Duration
+	DisableAfter   int
+	PollInterval   time.Duration
+	BatchSize      int
+}
+
+type webhookEnvelope struct {
+	Id        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"createdAt"`
+	Data      any       `json:"data"`
+}
+
+type WebhookService struct {
+	webhooks repositories.WebhookRepository
+	client   *http.Client
+	policy   WebhookPolicy
+	wake     chan struct{}
+}
+
+func NewWebhookService(webhooks repositories.WebhookRepository, client *http.Client, policy WebhookPolicy) *WebhookService {
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &WebhookService{
+		webhooks: webhooks,
+		client:   client,
+		policy:   policy,
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+func ValidateWebhookSubscription(subscription models.WebhookSubscription) error {
+	return validate.Struct(subscription)
+}
+
+func (s *WebhookService) CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	if err := ValidateWebhookSubscription(*subscription); err != nil {
+		return apperrors.Validation(err)
+	}
+
+	if subscription.Secret == "" {
+		secret := make([]byte, 24)
+		if _, err := rand.Read(secret); err != nil {
+			return fmt.Errorf("failed to generate webhook secret: %v", err)
+		}
+		subscription.Secret = webhookSecretPrefix + hex.EncodeToString(secret)
+	}
+
+	return s.webhooks.CreateSubscription(ctx, subscription)
+}
+
+func (s *WebhookService) GetSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	return s.webhooks.FindSubscriptions(ctx, "")
+}
+
+func (s *WebhookService) FindSubscriptionById(ctx context.Context, id string) *models.WebhookSubscription {
+	subscription, err := s.webhooks.FindSubscriptionById(ctx, id)
+	if err != nil {
+		logLookupError(ctx, "failed to find webhook subscription", err)
+		return nil
+	}
+	return subscription
+}
+
+func (s *WebhookService) UpdateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	if err := ValidateWebhookSubscription(*subscription); err != nil {
+		return apperrors.Validation(err)
+	}
+
+	if subscription.Active {
+		subscription.ConsecutiveFailures = 0
+		subscription.DisabledAt = time.Time{}
+		subscription.DisabledReason = ""
+	}
+	return s.webhooks.UpdateSubscription(ctx, subscription)
+}
+
+func (s *WebhookService) DeleteSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	return s.webhooks.DeleteSubscription(ctx, subscription.Id)
+}
+
+func (s *WebhookService) GetDeliveries(ctx context.Context, subscriptionId string, status string) ([]models.WebhookDelivery, error) {
+	return s.webhooks.FindDeliveries(ctx, repositories.WebhookDeliveryFilter{SubscriptionId: subscriptionId, Status: status, Limit: maxDeliveryLog})
+}
+
+func (s *WebhookService) FindDeliveryById(ctx context.Context, id string) *models.WebhookDelivery {
+	delivery, err := s.webhooks.FindDeliveryById(ctx, id)
+	if err != nil {
+		logLookupError(ctx, "failed to find webhook delivery", err)
+		return nil
+	}
+	return delivery
+}
+
+func (s *WebhookService) ReplayDelivery(ctx context.Context, original *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	subscription := s.FindSubscriptionById(ctx, original.SubscriptionId)
+	if subscription == nil {
+		return nil, apperrors.NotFound("Webhook subscription no longer exists")
+	}
+	if !subscription.Active {
+		return nil, apperrors.Conflict("Webhook subscription is disabled, re-enable it before replaying deliveries")
+	}
+
+	delivery := models.NewWebhookDelivery()
+	delivery.SubscriptionId = original.SubscriptionId
+	delivery.Event = original.Event
+	delivery.Payload = original.Payload
+	delivery.ReplayOf = original.Id
+
+	if err := s.webhooks.CreateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+	s.notify()
+	return delivery, nil
+}
+
+func (s *WebhookService) Ping(ctx context.Context, subscription *models.WebhookSubscription) (*models.WebhookDelivery, error) {
+	if !subscription.Active {
+		return nil, apperrors.Conflict("Webhook subscription is disabled")
+	}
+
+	payload, err := newWebhookPayload(models.WebhookEventPing, map[string]string{"subscriptionId": subscription.Id})
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := models.NewWebhookDelivery()
+	delivery.SubscriptionId = subscription.Id
+	delivery.Event = models.WebhookEventPing
+	delivery.Payload = payload
+
+	if err := s.webhooks.CreateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+	s.notify()
+	return delivery, nil
+}
+
+func (s *WebhookService) HandleEvent(ctx context.Context, event events.Event) error {
+	switch event.Type {
+	case events.BookingCreated, events.BookingCancelled:
+		var booking events.BookingData
+		if err := event.Decode(&booking); err != nil {
+			return err
+		}
+		return s.emit(ctx, event, booking)
+	case events.TourCreated, events.TourUpdated, events.TourDeleted:
+		var data events.TourData
+		if err := event.Decode(&data); err != nil {
+			return err
+		}
+		if data.Tour.SecretTour {
+			return nil
+		}
+		return s.emit(ctx, event, data.Tour)
+	}
+	return nil
+}
+
+func newWebhookPayload(event string, data any) (json.RawMessage, error) {
+	return encodeWebhook(webhookEnvelope{
+		Id:        uuid.New().String(),
+		Type:      event,
+		CreatedAt: time.Now(),
+		Data:      data,
+	})
+}
+
+func encodeWebhook(envelope webhookEnvelope) (json.RawMessage, error) {
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s webhook: %v", envelope.Type, err)
+	}
+	return payload, nil
+}
+
+func (s *WebhookService) emit(ctx context.Context, event events.Event, data any) error {
+	subscriptions, err := s.webhooks.FindSubscriptions(ctx, event.Type)
+	if err != nil {
+		return err
+	}
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	payload, err := encodeWebhook(webhookEnvelope{
+		Id:        event.Id,
+		Type:      event.Type,
+		CreatedAt: event.OccurredAt,
+		Data:      data,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, subscription := range subscriptions {
+		delivery := models.NewWebhookDelivery()
+		delivery.Id = uuid.NewSHA1(uuid.NameSpaceURL, []byte(event.Id+"/"+subscription.Id)).String()
+		delivery.SubscriptionId = subscription.Id
+		delivery.Event = event.Type
+		delivery.Payload = payload
+
+		err := s.webhooks.CreateDelivery(ctx, delivery)
+		if err != nil && !errors.Is(err, repositories.ErrDuplicate) {
+			return fmt.Errorf("failed to queue webhook for subscription %s: %v", subscription.Id, err)
+		}
+	}
+	s.notify()
+	return nil
+}
+
+func (s *WebhookService) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *WebhookService) Name() string {
+	return "webhooks"
+}
+
+func (s *WebhookService) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.policy.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.dispatch(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+func (s *WebhookService) dispatch(ctx context.Context) {
+	for ctx.Err() == nil {
+		deliveries, err := s.webhooks.ClaimDueDeliveries(ctx, time.Now(), s.policy.Timeout+time.Minute, s.policy.BatchSize)
+		if err != nil {
+			logger(ctx).ErrorContext(ctx, "failed to claim webhook deliveries", "error", err)
+		}
+
+		var wait sync.WaitGroup
+		for i := range deliveries {
+			wait.Add(1)
+			go func(delivery *models.WebhookDelivery) {
+				defer wait.Done()
+				s.deliver(ctx, delivery)
+			}(&deliveries[i])
+		}
+		wait.Wait()
+
+		if len(deliveries) < s.policy.BatchSize {
+			return
+		}
+	}
+}
+
+func (s *WebhookService) deliver(ctx context.Context, delivery *models.WebhookDelivery) {
+	subscription, err := s.webhooks.FindSubscriptionById(ctx, delivery.SubscriptionId)
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		s.abandon(ctx, delivery, "subscription deleted")
+		return
+	case err != nil:
+		logger(ctx).ErrorContext(ctx, "failed to find webhook subscription", "subscriptionId", delivery.SubscriptionId, "error", err)
+		return
+	case !subscription.Active:
+		s.abandon(ctx, delivery, "subscription disabled")
+		return
+	}
+
+	statusCode, err := s.send(ctx, subscription, delivery)
+	if ctx.Err() != nil {
+		return
+	}
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+	delivery.LastError = ""
+
+	succeeded := err == nil
+	switch {
+	case succeeded:
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.DeliveredAt = now
+	case delivery.Attempts >= s.policy.MaxAttempts:
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.LastError = err.Error()
+	default:
+		delivery.NextAttemptAt = now.Add(utils.RetryDelay(delivery.Attempts, s.policy.RetryBaseDelay, s.policy.MaxRetryDelay))
+		delivery.LastError = err.Error()
+	}
+
+	if err := s.webhooks.UpdateDelivery(ctx, delivery); err != nil {
+		logger(ctx).ErrorContext(ctx, "failed to record webhook delivery", "deliveryId", delivery.Id, "error", err)
+	}
+
+	updated, err := s.webhooks.RecordAttempt(ctx, subscription.Id, succeeded)
+	if err != nil {
+		logLookupError(ctx, "failed to record webhook attempt", err)
+		return
+	}
+	if !succeeded && s.policy.DisableAfter > 0 && updated.Active && updated.ConsecutiveFailures >= s.policy.DisableAfter {
+		updated.Active = false
+		updated.DisabledAt = now
+		updated.DisabledReason = fmt.Sprintf("disabled after %d consecutive failed attempts, last error: %s", updated.ConsecutiveFailures, delivery.LastError)
+		if err := s.webhooks.UpdateSubscription(ctx, updated); err != nil {
+			logger(ctx).ErrorContext(ctx, "failed to disable webhook subscription", "subscriptionId", updated.Id, "error", err)
+			return
+		}
+		logger(ctx).WarnContext(ctx, "disabled failing webhook subscription", "subscriptionId", updated.Id, "url", updated.URL, "failures", updated.ConsecutiveFailures)
+	}
+}
+
+func (s *WebhookService) abandon(ctx context.Context, delivery *models.WebhookDelivery, reason string) {
+	delivery.Status = models.WebhookDeliveryFailed
+	delivery.LastError = reason
+	if err := s.webhooks.UpdateDelivery(ctx, delivery); err != nil {
+		logger(ctx).ErrorContext(ctx, "failed to record webhook delivery", "deliveryId", delivery.Id, "error", err)
+	}
+}
+
+func (s *WebhookService) send(ctx context.Context, subscription *models.WebhookSubscription, delivery *models.WebhookDelivery) (int, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("invalid webhook request: %v", err)
+	}
+
+	timestamp := time.Now().Unix()
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "Natours-Webhooks/1.0")
+	request.Header.Set("X-Webhook-Id", delivery.Id)
+	request.Header.Set("X-Webhook-Event", delivery.Event)
+	request.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	request.Header.Set("X-Webhook-Signature", SignWebhook(subscription.Secret, timestamp, delivery.Payload))
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %v", err)
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, maxWebhookResponse))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("unexpected status %d", response.StatusCode)
+	}
+	return response.StatusCode, nil
+}
+
+func SignWebhook(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(payload)
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
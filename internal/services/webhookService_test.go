@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"github.com/hamid-nazari/tours-in-go/internal/repositories"
+)
+
+type webhookReceiver struct {
+	mutex    sync.Mutex
+	statuses []int
+	requests []receivedWebhook
+}
+
+type receivedWebhook struct {
+	header http.Header
+	body   []byte
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.requests = append(r.requests, receivedWebhook{header: req.Header.Clone(), body: body})
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status = r.statuses[0]
+		if len(r.statuses) > 1 {
+			r.statuses = r.statuses[1:]
+		}
+	}
+	w.WriteHeader(status)
+}
+
+func (r *webhookReceiver) received() []receivedWebhook {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]receivedWebhook(nil), r.requests...)
+}
+
+func newTestWebhookService(t *testing.T, receiver *webhookReceiver, disableAfter int) (*WebhookService, *repositories.MemoryWebhookRepository, *models.WebhookSubscription) {
+	t.Helper()
+
+	server := httptest.NewServer(receiver)
+	t.Cleanup(server.Close)
+
+	repo := repositories.NewMemoryWebhookRepository()
+	service := NewWebhookService(repo, server.Client(), WebhookPolicy{
+		Timeout:        time.Second,
+		MaxAttempts:    5,
+		RetryBaseDelay: time.Millisecond,
+		MaxRetryDelay:  time.Millisecond,
+		DisableAfter:   disableAfter,
+		PollInterval:   time.Hour,
+		BatchSize:      10,
+	})
+
+	subscription := models.NewWebhookSubscription()
+	subscription.URL = server.URL
+	subscription.Events = []string{models.WebhookEventBookingCreated}
+	subscription.Secret = "whsec_test"
+	if err := repo.CreateSubscription(context.Background(), subscription); err != nil {
+		t.Fatalf("failed to create subscription: %v", err)
+	}
+	return service, repo, subscription
+}
+
+func dispatchAfterRetryDelay(service *WebhookService) {
+	time.Sleep(5 * time.Millisecond)
+	service.dispatch(context.Background())
+}
+
+func TestWebhookDeliverySignsAndRetries(t *testing.T) {
+	receiver := &webhookReceiver{statuses: []int{http.StatusInternalServerError, http.StatusNoContent}}
+	service, repo, subscription := newTestWebhookService(t, receiver, 0)
+	ctx := context.Background()
+
+	delivery, err := service.Ping(ctx, subscription)
+	if err != nil {
+		t.Fatalf("failed to queue ping: %v", err)
+	}
+
+	service.dispatch(ctx)
+	failed, err := repo.FindDeliveryById(ctx, delivery.Id)
+	if err != nil {
+		t.Fatalf("failed to find delivery: %v", err)
+	}
+	if failed.Status != models.WebhookDeliveryPending || failed.Attempts != 1 || failed.LastStatusCode != http.StatusInternalServerError || failed.LastError == "" {
+		t.Fatalf("expected a pending retry after a 500, got %+v", failed)
+	}
+
+	dispatchAfterRetryDelay(service)
+	delivered, err := repo.FindDeliveryById(ctx, delivery.Id)
+	if err != nil {
+		t.Fatalf("failed to find delivery: %v", err)
+	}
+	if delivered.Status != models.WebhookDeliverySucceeded || delivered.Attempts != 2 || delivered.LastStatusCode != http.StatusNoContent {
+		t.Fatalf("expected the retry to succeed, got %+v", delivered)
+	}
+
+	requests := receiver.received()
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+	for _, request := range requests {
+		if request.header.Get("X-Webhook-Id") != delivery.Id || request.header.Get("X-Webhook-Event") != models.WebhookEventPing {
+			t.Errorf("unexpected webhook headers: %v", request.header)
+		}
+
+		timestamp, err := strconv.ParseInt(request.header.Get("X-Webhook-Timestamp"), 10, 64)
+		if err != nil {
+			t.Fatalf("invalid timestamp header: %v", err)
+		}
+		mac := hmac.New(sha256.New, []byte(subscription.Secret))
+		mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+		mac.Write(request.body)
+		expected := "t=" + strconv.FormatInt(timestamp, 10) + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+
+		if signature := request.header.Get("X-Webhook-Signature"); signature != expected || signature != SignWebhook(subscription.Secret, timestamp, request.body) {
+			t.Errorf("signature %q does not match %q", signature, expected)
+		}
+	}
+
+	updated, err := repo.FindSubscriptionById(ctx, subscription.Id)
+	if err != nil {
+		t.Fatalf("failed to find subscription: %v", err)
+	}
+	if !updated.Active || updated.ConsecutiveFailures != 0 {
+		t.Errorf("expected a healthy subscription after a successful retry, got %+v", updated)
+	}
+}
+
+func TestWebhookSubscriptionDisabledAfterFailures(t *testing.T) {
+	receiver := &webhookReceiver{statuses: []int{http.StatusInternalServerError}}
+	service, repo, subscription := newTestWebhookService(t, receiver, 2)
+	ctx := context.Background()
+
+	delivery, err := service.Ping(ctx, subscription)
+	if err != nil {
+		t.Fatalf("failed to queue ping: %v", err)
+	}
+
+	service.dispatch(ctx)
+	updated, err := repo.FindSubscriptionById(ctx, subscription.Id)
+	if err != nil {
+		t.Fatalf("failed to find subscription: %v", err)
+	}
+	if !updated.Active || updated.ConsecutiveFailures != 1 {
+		t.Fatalf("expected the subscription to stay active after one failure, got %+v", updated)
+	}
+
+	dispatchAfterRetryDelay(service)
+	updated, err = repo.FindSubscriptionById(ctx, subscription.Id)
+	if err != nil {
+		t.Fatalf("failed to find subscription: %v", err)
+	}
+	if updated.Active || updated.DisabledAt.IsZero() || updated.DisabledReason == "" {
+		t.Fatalf("expected the subscription to be disabled after 2 failures, got %+v", updated)
+	}
+
+	dispatchAfterRetryDelay(service)
+	abandoned, err := repo.FindDeliveryById(ctx, delivery.Id)
+	if err != nil {
+		t.Fatalf("failed to find delivery: %v", err)
+	}
+	if abandoned.Status != models.WebhookDeliveryFailed || abandoned.LastError != "subscription disabled" || abandoned.Attempts != 2 {
+		t.Errorf("expected the delivery to be abandoned, got %+v", abandoned)
+	}
+	if requests := receiver.received(); len(requests) != 2 {
+		t.Errorf("expected no requests after disabling, got %d", len(requests))
+	}
+}
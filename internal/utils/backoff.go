@@ -0,0 +1,17 @@
+package utils
+
+import (
+	mathrand "math/rand/v2"
+	"time"
+)
+
+func RetryDelay(attempts int, baseDelay time.Duration, maxDelay time.Duration) time.Duration {
+	delay := maxDelay
+	if shift := attempts - 1; shift < 32 {
+		delay = min(baseDelay<<shift, maxDelay)
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay + mathrand.N(delay/5+1)
+}
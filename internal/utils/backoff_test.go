@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{attempts: 1, expected: time.Second},
+		{attempts: 2, expected: 2 * time.Second},
+		{attempts: 4, expected: 8 * time.Second},
+		{attempts: 6, expected: 30 * time.Second},
+		{attempts: 100, expected: 30 * time.Second},
+	}
+
+	for _, test := range tests {
+		delay := RetryDelay(test.attempts, time.Second, 30*time.Second)
+		if delay < test.expected || delay > test.expected+test.expected/5 {
+			t.Errorf("attempt %d: expected %s plus up to 20%% jitter, got %s", test.attempts, test.expected, delay)
+		}
+	}
+
+	if delay := RetryDelay(3, 0, 0); delay != 0 {
+		t.Errorf("expected no delay without a base delay, got %s", delay)
+	}
+}
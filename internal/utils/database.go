@@ -2,40 +2,73 @@ package utils
 
 import (
 	"context"
-	"log"
-	"os"
+	"fmt"
+	"log/slog"
 
+	"github.com/hamid-nazari/tours-in-go/internal/logging"
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-func GetMongoClient() *mongo.Client {
-
-	ctx := context.Background()
-
-	dbUrl := os.Getenv("DB_URL")
-	if dbUrl == "" {
-		log.Fatalln("Failed to get DB_URL")
-	}
-
+func ConnectMongo(ctx context.Context, dbUrl string, monitors ...*event.CommandMonitor) (*mongo.Client, error) {
 	clientOptions := options.Client().ApplyURI(dbUrl)
+	if monitor := combineMonitors(monitors); monitor != nil {
+		clientOptions.SetMonitor(monitor)
+	}
 
 	mongoClient, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
-		log.Fatalln("Failed to connect to MongoDB:", err)
+		return nil, fmt.Errorf("failed to connect to MongoDB: %v", err)
 	}
 
 	err = mongoClient.Ping(ctx, nil)
 	if err != nil {
-		log.Fatalln("Failed to ping MongoDB:", err)
+		mongoClient.Disconnect(ctx)
+		return nil, fmt.Errorf("failed to ping MongoDB: %v", err)
 	}
 
-	log.Println("Connected to MongoDB")
+	logging.Package(slog.Default(), "utils").Info("connected to MongoDB")
 
-	return mongoClient
+	return mongoClient, nil
 }
 
-func GetCollection(client *mongo.Client, collectionName string) *mongo.Collection {
-	collection := client.Database("Tours").Collection(collectionName)
-	return collection
+func combineMonitors(monitors []*event.CommandMonitor) *event.CommandMonitor {
+	var active []*event.CommandMonitor
+	for _, monitor := range monitors {
+		if monitor != nil {
+			active = append(active, monitor)
+		}
+	}
+
+	switch len(active) {
+	case 0:
+		return nil
+	case 1:
+		return active[0]
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, started *event.CommandStartedEvent) {
+			for _, monitor := range active {
+				if monitor.Started != nil {
+					monitor.Started(ctx, started)
+				}
+			}
+		},
+		Succeeded: func(ctx context.Context, succeeded *event.CommandSucceededEvent) {
+			for _, monitor := range active {
+				if monitor.Succeeded != nil {
+					monitor.Succeeded(ctx, succeeded)
+				}
+			}
+		},
+		Failed: func(ctx context.Context, failed *event.CommandFailedEvent) {
+			for _, monitor := range active {
+				if monitor.Failed != nil {
+					monitor.Failed(ctx, failed)
+				}
+			}
+		},
+	}
 }
@@ -0,0 +1,57 @@
+package toursclient
+
+import "context"
+
+func (c *Client) Signup(ctx context.Context, signup SignupRequest) (*User, error) {
+	var payload authPayload
+	if err := c.call(ctx, request{endpoint: signupEndpoint, body: signup}, &payload); err != nil {
+		return nil, err
+	}
+	c.setToken(payload.Token)
+	return &payload.User, nil
+}
+
+func (c *Client) Login(ctx context.Context, email, password string) (*User, error) {
+	var payload authPayload
+	body := map[string]string{"email": email, "password": password}
+	if err := c.send(ctx, request{endpoint: loginEndpoint, body: body}, &payload); err != nil {
+		return nil, err
+	}
+	c.setToken(payload.Token)
+	return &payload.User, nil
+}
+
+func (c *Client) Logout(ctx context.Context) error {
+	if err := c.call(ctx, request{endpoint: logoutEndpoint}, nil); err != nil {
+		return err
+	}
+	c.setToken("")
+	return nil
+}
+
+func (c *Client) ForgotPassword(ctx context.Context, email string) (string, error) {
+	var link struct {
+		ResetURL string `json:"reset_url"`
+	}
+	if err := c.call(ctx, request{endpoint: forgotPasswordEndpoint, body: map[string]string{"email": email}}, &link); err != nil {
+		return "", err
+	}
+	return link.ResetURL, nil
+}
+
+func (c *Client) UpdatePassword(ctx context.Context, currentPassword, newPassword string) (*User, error) {
+	var payload authPayload
+	body := map[string]string{
+		"currentPassword":    currentPassword,
+		"newPassword":        newPassword,
+		"newPasswordConfirm": newPassword,
+	}
+	if err := c.call(ctx, request{endpoint: updatePasswordEndpoint, body: body, auth: true}, &payload); err != nil {
+		return nil, err
+	}
+	c.setToken(payload.Token)
+	if c.canLogin() {
+		c.password = newPassword
+	}
+	return &payload.User, nil
+}
@@ -0,0 +1,9 @@
+package toursclient
+
+import "context"
+
+func (c *Client) CreateCheckoutSession(ctx context.Context, tourId string) (CheckoutSession, error) {
+	var session CheckoutSession
+	err := c.call(ctx, request{endpoint: checkoutSessionEndpoint, params: map[string]string{"id": tourId}, auth: true}, &session)
+	return session, err
+}
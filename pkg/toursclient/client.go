@@ -0,0 +1,335 @@
+package toursclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultUserAgent = "toursclient-go"
+
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	userAgent  string
+	retry      RetryPolicy
+
+	mutex       sync.Mutex
+	token       string
+	tokenExpiry time.Time
+	email       string
+	password    string
+}
+
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+type Option func(*Client)
+
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.setToken(token)
+	}
+}
+
+func WithCredentials(email, password string) Option {
+	return func(c *Client) {
+		c.email, c.password = email, password
+	}
+}
+
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+func New(baseURL string, options ...Option) (*Client, error) {
+	parsed, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %v", err)
+	}
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q, expected scheme and host", baseURL)
+	}
+
+	client := &Client{
+		baseURL:    parsed,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		userAgent:  defaultUserAgent,
+		retry: RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   200 * time.Millisecond,
+			MaxDelay:    5 * time.Second,
+		},
+	}
+	for _, option := range options {
+		option(client)
+	}
+
+	return client, nil
+}
+
+func (c *Client) Token() string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.token
+}
+
+type request struct {
+	endpoint  endpoint
+	params    map[string]string
+	query     url.Values
+	body      any
+	multipart *multipartBody
+	auth      bool
+	optional  bool
+}
+
+func (c *Client) call(ctx context.Context, req request, out any) error {
+	if req.auth {
+		if err := c.ensureToken(ctx); err != nil {
+			return err
+		}
+	}
+
+	err := c.send(ctx, req, out)
+
+	var apiErr *Error
+	if req.auth && errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized && c.canLogin() {
+		if err := c.refresh(ctx); err != nil {
+			return err
+		}
+		return c.send(ctx, req, out)
+	}
+
+	return err
+}
+
+func (c *Client) send(ctx context.Context, req request, out any) error {
+	var payload []byte
+	var contentType string
+	switch {
+	case req.multipart != nil:
+		var err error
+		payload, contentType, err = req.multipart.encode()
+		if err != nil {
+			return err
+		}
+	case req.body != nil:
+		var err error
+		payload, err = json.Marshal(req.body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request body: %v", err)
+		}
+		contentType = "application/json"
+	}
+
+	attempts := 1
+	if req.endpoint.idempotent() && c.retry.MaxAttempts > 1 {
+		attempts = c.retry.MaxAttempts
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		response, err := c.do(ctx, req, payload, contentType)
+		if err == nil {
+			lastErr = decode(response, out)
+			response.Body.Close()
+			if !retryableStatus(response.StatusCode) {
+				return lastErr
+			}
+		} else {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			lastErr = fmt.Errorf("%s %s: %w", req.endpoint.method, req.endpoint.path, err)
+		}
+
+		if attempt == attempts {
+			break
+		}
+
+		delay := c.backoff(attempt)
+		if response != nil {
+			if retryAfter := parseRetryAfter(response.Header.Get("Retry-After")); retryAfter > 0 {
+				delay = min(retryAfter, c.retry.MaxDelay)
+			}
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	return lastErr
+}
+
+func (c *Client) do(ctx context.Context, req request, payload []byte, contentType string) (*http.Response, error) {
+	path, escapedPath := req.endpoint.expand(req.params)
+	target := *c.baseURL
+	target.RawPath = target.EscapedPath() + escapedPath
+	target.Path += path
+	target.RawQuery = req.query.Encode()
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, req.endpoint.method, target.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	httpRequest.Header.Set("Accept", "application/json")
+	httpRequest.Header.Set("User-Agent", c.userAgent)
+	if contentType != "" {
+		httpRequest.Header.Set("Content-Type", contentType)
+	}
+	if token := c.Token(); token != "" && (req.auth || req.optional) {
+		httpRequest.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return c.httpClient.Do(httpRequest)
+}
+
+func decode(response *http.Response, out any) error {
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %v", err)
+	}
+
+	if response.StatusCode >= http.StatusBadRequest {
+		return newError(response, content)
+	}
+
+	if out == nil || len(bytes.TrimSpace(content)) == 0 {
+		return nil
+	}
+
+	envelope := struct {
+		Data json.RawMessage `json:"data"`
+	}{}
+	if err := json.Unmarshal(content, &envelope); err != nil {
+		return fmt.Errorf("failed to decode response: %v", err)
+	}
+	if len(envelope.Data) == 0 || string(envelope.Data) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(envelope.Data, out); err != nil {
+		return fmt.Errorf("failed to decode response data: %v", err)
+	}
+	return nil
+}
+
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.retry.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > c.retry.MaxDelay {
+		delay = c.retry.MaxDelay
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at)
+	}
+	return 0
+}
+
+func (c *Client) setToken(token string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.token = token
+	c.tokenExpiry = tokenExpiry(token)
+}
+
+func (c *Client) canLogin() bool {
+	return c.email != "" && c.password != ""
+}
+
+func (c *Client) ensureToken(ctx context.Context) error {
+	c.mutex.Lock()
+	token, expiry := c.token, c.tokenExpiry
+	c.mutex.Unlock()
+
+	expiring := !expiry.IsZero() && time.Until(expiry) < time.Minute
+	if (token == "" || expiring) && c.canLogin() {
+		return c.refresh(ctx)
+	}
+	if token == "" {
+		return ErrNotAuthenticated
+	}
+	return nil
+}
+
+func (c *Client) refresh(ctx context.Context) error {
+	_, err := c.Login(ctx, c.email, c.password)
+	return err
+}
+
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+
+	var claims struct {
+		ExpiresAt int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.ExpiresAt == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.ExpiresAt, 0)
+}
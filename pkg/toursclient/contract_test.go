@@ -0,0 +1,279 @@
+package toursclient_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hamid-nazari/tours-in-go/internal/app"
+	"github.com/hamid-nazari/tours-in-go/internal/config"
+	"github.com/hamid-nazari/tours-in-go/internal/models"
+	"github.com/hamid-nazari/tours-in-go/internal/openapi"
+	"github.com/hamid-nazari/tours-in-go/internal/services"
+	"github.com/hamid-nazari/tours-in-go/pkg/toursclient"
+)
+
+const jwtSecret = "contract-secret"
+
+type testServer struct {
+	*httptest.Server
+	app *app.App
+}
+
+func newTestServer(t *testing.T, wrap func(http.Handler) http.Handler) *testServer {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg := config.Defaults()
+	cfg.Auth.JWTSecret = jwtSecret
+
+	application, err := app.New(cfg, app.MemoryRepositories())
+	if err != nil {
+		t.Fatalf("failed to build app: %v", err)
+	}
+
+	var handler http.Handler = application.Router
+	if wrap != nil {
+		handler = wrap(handler)
+	}
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return &testServer{Server: server, app: application}
+}
+
+func (s *testServer) createUser(t *testing.T, email, password, role string) *models.User {
+	t.Helper()
+
+	hashedPassword, err := services.HashPassword(password)
+	if err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+
+	user := models.NewUser()
+	user.Name = "Test " + role
+	user.Email = email
+	user.Role = role
+	user.Password = hashedPassword
+	user.PasswordChangedAt = time.Time{}
+	if err := s.app.Users.CreateUser(context.Background(), user); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	return user
+}
+
+func (s *testServer) createTour(t *testing.T, name string) *models.Tour {
+	t.Helper()
+
+	tour := models.NewTour()
+	tour.Name = name
+	tour.Duration = "5"
+	tour.Price = 497
+	tour.MaxGroupSize = 25
+	tour.Summary = "Breathtaking hike through the Canadian Banff National Park"
+	if err := s.app.Tours.GenerateTourSlug(context.Background(), tour); err != nil {
+		t.Fatalf("failed to generate slug: %v", err)
+	}
+	if err := s.app.Tours.CreateTour(context.Background(), tour); err != nil {
+		t.Fatalf("failed to create tour: %v", err)
+	}
+	return tour
+}
+
+func (s *testServer) client(t *testing.T, options ...toursclient.Option) *toursclient.Client {
+	t.Helper()
+
+	client, err := toursclient.New(s.URL, options...)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
+
+func TestClientEndpointsAreServed(t *testing.T) {
+	spec := openapi.Spec()
+	for _, endpoint := range toursclient.Endpoints() {
+		if !spec.Documents(endpoint.Method, endpoint.Path) {
+			t.Errorf("client calls %s %s, which the API does not serve", endpoint.Method, endpoint.Path)
+		}
+	}
+}
+
+func TestSignupAndLogin(t *testing.T) {
+	server := newTestServer(t, nil)
+	ctx := context.Background()
+	client := server.client(t)
+
+	signup := toursclient.SignupRequest{
+		Name:            "Jonas",
+		Email:           "jonas@example.com",
+		Password:        "pass1234",
+		PasswordConfirm: "pass1234",
+	}
+	user, err := client.Signup(ctx, signup)
+	if err != nil {
+		t.Fatalf("signup failed: %v", err)
+	}
+	if user.Email != signup.Email || user.Id == "" {
+		t.Errorf("unexpected user after signup: %+v", user)
+	}
+	if client.Token() == "" {
+		t.Error("signup did not store a token")
+	}
+
+	loggedIn := server.client(t)
+	user, err = loggedIn.Login(ctx, signup.Email, signup.Password)
+	if err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+	if user.Email != signup.Email {
+		t.Errorf("logged in as %q, want %q", user.Email, signup.Email)
+	}
+	if loggedIn.Token() == "" {
+		t.Error("login did not store a token")
+	}
+
+	if _, err := server.client(t).Login(ctx, signup.Email, "wrong-password"); !errors.Is(err, toursclient.ErrUnauthorized) {
+		t.Errorf("login with a wrong password returned %v, want ErrUnauthorized", err)
+	}
+
+	if _, err := server.client(t).Signup(ctx, signup); !errors.Is(err, toursclient.ErrConflict) {
+		t.Errorf("duplicate signup returned %v, want ErrConflict", err)
+	}
+}
+
+func TestTokenRefresh(t *testing.T) {
+	server := newTestServer(t, nil)
+	ctx := context.Background()
+	admin := server.createUser(t, "admin@example.com", "admin1234", "admin")
+
+	t.Run("rejected token", func(t *testing.T) {
+		client := server.client(t, toursclient.WithToken("not-a-jwt"), toursclient.WithCredentials(admin.Email, "admin1234"))
+
+		if _, err := client.ListUsers(ctx); err != nil {
+			t.Fatalf("list users failed: %v", err)
+		}
+		if client.Token() == "not-a-jwt" {
+			t.Error("client kept the rejected token")
+		}
+	})
+
+	t.Run("expiring token", func(t *testing.T) {
+		expiring, err := jwt.NewWithClaims(jwt.SigningMethodHS256, models.CustomClaims{
+			UserId: admin.Id,
+			RegisteredClaims: jwt.RegisteredClaims{
+				IssuedAt:  jwt.NewNumericDate(time.Now()),
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(30 * time.Second)),
+			},
+		}).SignedString([]byte(jwtSecret))
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+
+		client := server.client(t, toursclient.WithToken(expiring), toursclient.WithCredentials(admin.Email, "admin1234"))
+		if _, err := client.ListUsers(ctx); err != nil {
+			t.Fatalf("list users failed: %v", err)
+		}
+		if client.Token() == expiring {
+			t.Error("client did not refresh a token about to expire")
+		}
+	})
+
+	t.Run("no credentials", func(t *testing.T) {
+		client := server.client(t)
+		if _, err := client.ListUsers(ctx); !errors.Is(err, toursclient.ErrNotAuthenticated) {
+			t.Errorf("list users without a token returned %v, want ErrNotAuthenticated", err)
+		}
+	})
+}
+
+func TestRetryOn5xx(t *testing.T) {
+	var failures, requests atomic.Int32
+
+	server := newTestServer(t, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			if failures.Add(-1) >= 0 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+	ctx := context.Background()
+	server.createTour(t, "The Forest Hiker")
+	failures.Store(2)
+	requests.Store(0)
+	policy := toursclient.WithRetryPolicy(toursclient.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond})
+
+	if _, err := server.client(t, policy).ListTours(ctx, toursclient.ListToursOptions{}); err != nil {
+		t.Fatalf("list tours failed after retries: %v", err)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("list tours made %d requests, want 3", got)
+	}
+
+	failures.Store(5)
+	requests.Store(0)
+	_, err := server.client(t, policy).ListTours(ctx, toursclient.ListToursOptions{})
+	var apiErr *toursclient.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("list tours returned %v, want a 503 error once retries are exhausted", err)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("list tours made %d requests, want 3", got)
+	}
+
+	failures.Store(1)
+	requests.Store(0)
+	signup := toursclient.SignupRequest{Name: "Retry", Email: "retry@example.com", Password: "pass1234", PasswordConfirm: "pass1234"}
+	if _, err := server.client(t, policy).Signup(ctx, signup); err == nil {
+		t.Error("signup succeeded, want the 503 to be returned without a retry")
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("signup made %d requests, want 1 because POST is not retried", got)
+	}
+}
+
+func TestErrorDecoding(t *testing.T) {
+	server := newTestServer(t, nil)
+	ctx := context.Background()
+	client := server.client(t)
+
+	_, err := client.GetTourBySlug(ctx, "missing")
+	var apiErr *toursclient.Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("get missing tour returned %v, want *toursclient.Error", err)
+	}
+	if !errors.Is(err, toursclient.ErrNotFound) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("got code %q and status %d, want not_found and 404", apiErr.Code, apiErr.StatusCode)
+	}
+	if apiErr.Message == "" || apiErr.RequestID == "" {
+		t.Errorf("error is missing its message or request id: %+v", apiErr)
+	}
+
+	_, err = client.Signup(ctx, toursclient.SignupRequest{Name: "Short", Email: "not-an-email", Password: "short", PasswordConfirm: "short"})
+	if !errors.As(err, &apiErr) || !errors.Is(err, toursclient.ErrValidation) {
+		t.Fatalf("invalid signup returned %v, want ErrValidation", err)
+	}
+	fields := map[string]bool{}
+	for _, detail := range apiErr.Details {
+		fields[detail.Field] = true
+	}
+	if !fields["email"] || !fields["password"] {
+		t.Errorf("validation details %+v should name the email and password fields", apiErr.Details)
+	}
+
+	user := server.createUser(t, "user@example.com", "user12345", "user")
+	client = server.client(t, toursclient.WithCredentials(user.Email, "user12345"))
+	if _, err := client.ListUsers(ctx); !errors.Is(err, toursclient.ErrForbidden) {
+		t.Errorf("list users as a regular user returned %v, want ErrForbidden", err)
+	}
+}
@@ -0,0 +1,98 @@
+package toursclient
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type endpoint struct {
+	method string
+	path   string
+}
+
+type Endpoint struct {
+	Method string
+	Path   string
+}
+
+var (
+	signupEndpoint         = endpoint{http.MethodPost, "/api/v1/users/signup"}
+	loginEndpoint          = endpoint{http.MethodPost, "/api/v1/users/login"}
+	logoutEndpoint         = endpoint{http.MethodPost, "/api/v1/users/logout"}
+	forgotPasswordEndpoint = endpoint{http.MethodPost, "/api/v1/users/forgot-password"}
+	updatePasswordEndpoint = endpoint{http.MethodPatch, "/api/v1/users/update-password"}
+
+	listUsersEndpoint  = endpoint{http.MethodGet, "/api/v1/users/"}
+	createUserEndpoint = endpoint{http.MethodPost, "/api/v1/users/"}
+	getUserEndpoint    = endpoint{http.MethodGet, "/api/v1/users/:id"}
+	updateUserEndpoint = endpoint{http.MethodPatch, "/api/v1/users/:id"}
+	deleteUserEndpoint = endpoint{http.MethodDelete, "/api/v1/users/:id"}
+
+	listToursEndpoint       = endpoint{http.MethodGet, "/api/v1/tours/"}
+	topCheapToursEndpoint   = endpoint{http.MethodGet, "/api/v1/tours/top-5-cheap"}
+	guideToursEndpoint      = endpoint{http.MethodGet, "/api/v1/users/:id/tours"}
+	createTourEndpoint      = endpoint{http.MethodPost, "/api/v1/tours/"}
+	getTourEndpoint         = endpoint{http.MethodGet, "/api/v1/tours/:id"}
+	getTourBySlugEndpoint   = endpoint{http.MethodGet, "/api/v1/tours/slug/:slug"}
+	updateTourEndpoint      = endpoint{http.MethodPatch, "/api/v1/tours/:id"}
+	deleteTourEndpoint      = endpoint{http.MethodDelete, "/api/v1/tours/:id"}
+	createShareLinkEndpoint = endpoint{http.MethodPost, "/api/v1/tours/:id/share-link"}
+	revokeShareLinkEndpoint = endpoint{http.MethodDelete, "/api/v1/tours/:id/share-link"}
+	getSharedTourEndpoint   = endpoint{http.MethodGet, "/api/v1/tours/shared/:token"}
+
+	listReviewsEndpoint     = endpoint{http.MethodGet, "/api/v1/reviews/"}
+	getReviewEndpoint       = endpoint{http.MethodGet, "/api/v1/reviews/:id"}
+	createReviewEndpoint    = endpoint{http.MethodPost, "/api/v1/reviews/"}
+	updateReviewEndpoint    = endpoint{http.MethodPatch, "/api/v1/reviews/:id"}
+	deleteReviewEndpoint    = endpoint{http.MethodDelete, "/api/v1/reviews/:id"}
+	reportReviewEndpoint    = endpoint{http.MethodPost, "/api/v1/reviews/:id/report"}
+	voteReviewEndpoint      = endpoint{http.MethodPost, "/api/v1/reviews/:id/vote"}
+	removeVoteEndpoint      = endpoint{http.MethodDelete, "/api/v1/reviews/:id/vote"}
+	replyReviewEndpoint     = endpoint{http.MethodPut, "/api/v1/reviews/:id/reply"}
+	deleteReplyEndpoint     = endpoint{http.MethodDelete, "/api/v1/reviews/:id/reply"}
+	moderationQueueEndpoint = endpoint{http.MethodGet, "/api/v1/reviews/moderation"}
+	moderateReviewEndpoint  = endpoint{http.MethodPatch, "/api/v1/reviews/:id/moderate"}
+	checkoutSessionEndpoint = endpoint{http.MethodGet, "/api/v1/bookings/checkout-session/:id"}
+)
+
+var endpoints = []endpoint{
+	signupEndpoint, loginEndpoint, logoutEndpoint, forgotPasswordEndpoint, updatePasswordEndpoint,
+	listUsersEndpoint, createUserEndpoint, getUserEndpoint, updateUserEndpoint, deleteUserEndpoint,
+	listToursEndpoint, topCheapToursEndpoint, guideToursEndpoint, createTourEndpoint, getTourEndpoint,
+	getTourBySlugEndpoint, updateTourEndpoint, deleteTourEndpoint, createShareLinkEndpoint,
+	revokeShareLinkEndpoint, getSharedTourEndpoint,
+	listReviewsEndpoint, getReviewEndpoint, createReviewEndpoint, updateReviewEndpoint, deleteReviewEndpoint,
+	reportReviewEndpoint, voteReviewEndpoint, removeVoteEndpoint, replyReviewEndpoint, deleteReplyEndpoint,
+	moderationQueueEndpoint, moderateReviewEndpoint,
+	checkoutSessionEndpoint,
+}
+
+func Endpoints() []Endpoint {
+	exported := make([]Endpoint, len(endpoints))
+	for i, e := range endpoints {
+		exported[i] = Endpoint{Method: e.method, Path: e.path}
+	}
+	return exported
+}
+
+func (e endpoint) idempotent() bool {
+	switch e.method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func (e endpoint) expand(params map[string]string) (string, string) {
+	segments := strings.Split(e.path, "/")
+	escaped := make([]string, len(segments))
+	for i, segment := range segments {
+		escaped[i] = segment
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = params[segment[1:]]
+			escaped[i] = url.PathEscape(segments[i])
+		}
+	}
+	return strings.Join(segments, "/"), strings.Join(escaped, "/")
+}
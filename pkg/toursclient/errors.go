@@ -0,0 +1,104 @@
+package toursclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	CodeBadRequest   = "bad_request"
+	CodeValidation   = "validation_failed"
+	CodeUnauthorized = "unauthorized"
+	CodeForbidden    = "forbidden"
+	CodeNotFound     = "not_found"
+	CodeConflict     = "conflict"
+	CodeInternal     = "internal_error"
+)
+
+var (
+	ErrBadRequest   = &Error{Code: CodeBadRequest}
+	ErrValidation   = &Error{Code: CodeValidation}
+	ErrUnauthorized = &Error{Code: CodeUnauthorized}
+	ErrForbidden    = &Error{Code: CodeForbidden}
+	ErrNotFound     = &Error{Code: CodeNotFound}
+	ErrConflict     = &Error{Code: CodeConflict}
+	ErrInternal     = &Error{Code: CodeInternal}
+
+	ErrNotAuthenticated = errors.New("toursclient: no token, log in or configure credentials first")
+)
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+type Error struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Details    []FieldError
+	RequestID  string
+}
+
+func (e *Error) Error() string {
+	message := e.Message
+	if message == "" {
+		message = http.StatusText(e.StatusCode)
+	}
+	if len(e.Details) > 0 {
+		fields := make([]string, len(e.Details))
+		for i, detail := range e.Details {
+			fields[i] = detail.Field + " " + detail.Message
+		}
+		message += ": " + strings.Join(fields, ", ")
+	}
+	return fmt.Sprintf("toursclient: %s (%d %s)", message, e.StatusCode, e.Code)
+}
+
+func (e *Error) Is(target error) bool {
+	other, ok := target.(*Error)
+	return ok && other.Code != "" && other.Code == e.Code
+}
+
+func newError(response *http.Response, content []byte) *Error {
+	apiErr := &Error{
+		StatusCode: response.StatusCode,
+		RequestID:  response.Header.Get("X-Request-ID"),
+	}
+
+	var body struct {
+		Code    string          `json:"code"`
+		Message string          `json:"message"`
+		Details json.RawMessage `json:"details"`
+	}
+	if err := json.Unmarshal(content, &body); err == nil {
+		apiErr.Code, apiErr.Message = body.Code, body.Message
+		json.Unmarshal(body.Details, &apiErr.Details)
+	}
+
+	if apiErr.Code == "" {
+		apiErr.Code = codeForStatus(response.StatusCode)
+	}
+
+	return apiErr
+}
+
+func codeForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	}
+	return CodeInternal
+}
@@ -0,0 +1,81 @@
+package toursclient
+
+import (
+	"context"
+	"net/url"
+)
+
+func (c *Client) ListReviews(ctx context.Context, sort string) ([]Review, error) {
+	query := url.Values{}
+	if sort != "" {
+		query.Set("sort", sort)
+	}
+
+	var reviews []Review
+	err := c.call(ctx, request{endpoint: listReviewsEndpoint, query: query}, &reviews)
+	return reviews, err
+}
+
+func (c *Client) GetReview(ctx context.Context, id string) (*Review, error) {
+	return c.review(ctx, request{endpoint: getReviewEndpoint, params: map[string]string{"id": id}})
+}
+
+func (c *Client) CreateReview(ctx context.Context, create CreateReviewRequest) (*Review, error) {
+	body := map[string]any{
+		"review": create.Review,
+		"rating": create.Rating,
+		"tour":   map[string]string{"id": create.TourId},
+	}
+	return c.review(ctx, request{endpoint: createReviewEndpoint, body: body, auth: true})
+}
+
+func (c *Client) UpdateReview(ctx context.Context, id, review string, rating int) (*Review, error) {
+	body := map[string]any{"review": review, "rating": rating}
+	return c.review(ctx, request{endpoint: updateReviewEndpoint, params: map[string]string{"id": id}, body: body, auth: true})
+}
+
+func (c *Client) DeleteReview(ctx context.Context, id string) error {
+	return c.call(ctx, request{endpoint: deleteReviewEndpoint, params: map[string]string{"id": id}, auth: true}, nil)
+}
+
+func (c *Client) ReportReview(ctx context.Context, id, reason string) error {
+	body := map[string]string{"reason": reason}
+	return c.call(ctx, request{endpoint: reportReviewEndpoint, params: map[string]string{"id": id}, body: body, auth: true}, nil)
+}
+
+func (c *Client) VoteOnReview(ctx context.Context, id string, helpful bool) (*Review, error) {
+	body := map[string]bool{"helpful": helpful}
+	return c.review(ctx, request{endpoint: voteReviewEndpoint, params: map[string]string{"id": id}, body: body, auth: true})
+}
+
+func (c *Client) RemoveReviewVote(ctx context.Context, id string) (*Review, error) {
+	return c.review(ctx, request{endpoint: removeVoteEndpoint, params: map[string]string{"id": id}, auth: true})
+}
+
+func (c *Client) ReplyToReview(ctx context.Context, id, reply string) (*Review, error) {
+	body := map[string]string{"reply": reply}
+	return c.review(ctx, request{endpoint: replyReviewEndpoint, params: map[string]string{"id": id}, body: body, auth: true})
+}
+
+func (c *Client) DeleteReviewReply(ctx context.Context, id string) error {
+	return c.call(ctx, request{endpoint: deleteReplyEndpoint, params: map[string]string{"id": id}, auth: true}, nil)
+}
+
+func (c *Client) ModerationQueue(ctx context.Context) ([]Review, error) {
+	var reviews []Review
+	err := c.call(ctx, request{endpoint: moderationQueueEndpoint, auth: true}, &reviews)
+	return reviews, err
+}
+
+func (c *Client) ModerateReview(ctx context.Context, id, status, reason string) (*Review, error) {
+	body := map[string]string{"status": status, "reason": reason}
+	return c.review(ctx, request{endpoint: moderateReviewEndpoint, params: map[string]string{"id": id}, body: body, auth: true})
+}
+
+func (c *Client) review(ctx context.Context, req request) (*Review, error) {
+	var review Review
+	if err := c.call(ctx, req, &review); err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
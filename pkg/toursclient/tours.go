@@ -0,0 +1,75 @@
+package toursclient
+
+import (
+	"context"
+	"net/url"
+)
+
+func (c *Client) ListTours(ctx context.Context, options ListToursOptions) ([]Tour, error) {
+	query := url.Values{}
+	if options.IncludeSecret {
+		query.Set("includeSecret", "true")
+	}
+
+	var tours []Tour
+	err := c.call(ctx, request{endpoint: listToursEndpoint, query: query, optional: true}, &tours)
+	return tours, err
+}
+
+func (c *Client) TopCheapTours(ctx context.Context) ([]Tour, error) {
+	var tours []Tour
+	err := c.call(ctx, request{endpoint: topCheapToursEndpoint, optional: true}, &tours)
+	return tours, err
+}
+
+func (c *Client) ListGuideTours(ctx context.Context, guideId string) ([]Tour, error) {
+	var tours []Tour
+	err := c.call(ctx, request{endpoint: guideToursEndpoint, params: map[string]string{"id": guideId}, optional: true}, &tours)
+	return tours, err
+}
+
+func (c *Client) GetTour(ctx context.Context, id string) (*Tour, error) {
+	return c.tour(ctx, request{endpoint: getTourEndpoint, params: map[string]string{"id": id}, auth: true})
+}
+
+func (c *Client) GetTourBySlug(ctx context.Context, slug string) (*Tour, error) {
+	return c.tour(ctx, request{endpoint: getTourBySlugEndpoint, params: map[string]string{"slug": slug}, optional: true})
+}
+
+func (c *Client) GetSharedTour(ctx context.Context, token string) (*Tour, error) {
+	return c.tour(ctx, request{endpoint: getSharedTourEndpoint, params: map[string]string{"token": token}})
+}
+
+func (c *Client) CreateTour(ctx context.Context, tour Tour) (*Tour, error) {
+	return c.tour(ctx, request{endpoint: createTourEndpoint, body: tour, auth: true})
+}
+
+func (c *Client) UpdateTour(ctx context.Context, id string, tour Tour) (*Tour, error) {
+	return c.tour(ctx, request{endpoint: updateTourEndpoint, params: map[string]string{"id": id}, body: tour, auth: true})
+}
+
+func (c *Client) DeleteTour(ctx context.Context, id string) error {
+	return c.call(ctx, request{endpoint: deleteTourEndpoint, params: map[string]string{"id": id}, auth: true}, nil)
+}
+
+func (c *Client) CreateShareLink(ctx context.Context, id string) (string, error) {
+	var link struct {
+		ShareURL string `json:"shareUrl"`
+	}
+	if err := c.call(ctx, request{endpoint: createShareLinkEndpoint, params: map[string]string{"id": id}, auth: true}, &link); err != nil {
+		return "", err
+	}
+	return link.ShareURL, nil
+}
+
+func (c *Client) RevokeShareLink(ctx context.Context, id string) error {
+	return c.call(ctx, request{endpoint: revokeShareLinkEndpoint, params: map[string]string{"id": id}, auth: true}, nil)
+}
+
+func (c *Client) tour(ctx context.Context, req request) (*Tour, error) {
+	var tour Tour
+	if err := c.call(ctx, req, &tour); err != nil {
+		return nil, err
+	}
+	return &tour, nil
+}
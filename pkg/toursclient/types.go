@@ -0,0 +1,140 @@
+package toursclient
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"time"
+)
+
+type User struct {
+	Id                string    `json:"id"`
+	Name              string    `json:"name"`
+	Email             string    `json:"email"`
+	Photo             string    `json:"photo"`
+	Role              string    `json:"role"`
+	PasswordChangedAt time.Time `json:"passwordChangedAt,omitempty"`
+	Active            bool      `json:"active"`
+}
+
+type Guide struct {
+	Id    string `json:"id"`
+	Name  string `json:"name"`
+	Photo string `json:"photo"`
+	Role  string `json:"role"`
+}
+
+type Tour struct {
+	Id             string      `json:"id,omitempty"`
+	Name           string      `json:"name"`
+	Slug           string      `json:"slug,omitempty"`
+	PreviousSlugs  []string    `json:"previousSlugs,omitempty"`
+	Duration       string      `json:"duration"`
+	Difficulty     string      `json:"difficulty,omitempty"`
+	Price          float64     `json:"price"`
+	MaxGroupSize   int         `json:"maxGroupSize"`
+	RatingsAvg     float64     `json:"ratingAvg"`
+	RatingQuantity int         `json:"ratingQuantity"`
+	ImageCover     string      `json:"imageCover,omitempty"`
+	Images         []string    `json:"images,omitempty"`
+	CreatedAt      time.Time   `json:"createdAt,omitempty"`
+	StartDates     []time.Time `json:"startDates,omitempty"`
+	SecretTour     bool        `json:"secretTour"`
+	Summary        string      `json:"summary"`
+	Description    string      `json:"description,omitempty"`
+	StartLocation  string      `json:"startLocation,omitempty"`
+	Locations      []string    `json:"locations,omitempty"`
+	Guides         []string    `json:"guides,omitempty"`
+	GuideProfiles  []Guide     `json:"guideProfiles,omitempty"`
+}
+
+type Review struct {
+	Id               string       `json:"id"`
+	Review           string       `json:"review"`
+	Rating           int          `json:"rating"`
+	Tour             Tour         `json:"tour"`
+	User             User         `json:"user"`
+	Status           string       `json:"status"`
+	ModerationReason string       `json:"moderationReason,omitempty"`
+	ModeratedBy      string       `json:"moderatedBy,omitempty"`
+	ModeratedAt      time.Time    `json:"moderatedAt,omitempty"`
+	Reply            *ReviewReply `json:"reply,omitempty"`
+	HelpfulCount     int          `json:"helpfulCount"`
+	UnhelpfulCount   int          `json:"unhelpfulCount"`
+	CreatedAt        time.Time    `json:"createdAt"`
+}
+
+type ReviewReply struct {
+	GuideId   string    `json:"guideId"`
+	GuideName string    `json:"guideName"`
+	Reply     string    `json:"reply"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type CheckoutSession map[string]any
+
+type SignupRequest struct {
+	Name            string `json:"name"`
+	Email           string `json:"email"`
+	Photo           string `json:"photo,omitempty"`
+	Password        string `json:"password"`
+	PasswordConfirm string `json:"passwordConfirm"`
+}
+
+type CreateUserRequest struct {
+	Name            string `json:"name"`
+	Email           string `json:"email"`
+	Photo           string `json:"photo,omitempty"`
+	Role            string `json:"role,omitempty"`
+	Password        string `json:"password"`
+	PasswordConfirm string `json:"passwordConfirm"`
+}
+
+type CreateReviewRequest struct {
+	TourId string
+	Review string
+	Rating int
+}
+
+type ListToursOptions struct {
+	IncludeSecret bool
+}
+
+type authPayload struct {
+	Token string `json:"token"`
+	User  User   `json:"user"`
+}
+
+type multipartBody struct {
+	fields   map[string]string
+	fileKey  string
+	fileName string
+	file     io.Reader
+}
+
+func (b *multipartBody) encode() ([]byte, string, error) {
+	var buffer bytes.Buffer
+	writer := multipart.NewWriter(&buffer)
+
+	for key, value := range b.fields {
+		if err := writer.WriteField(key, value); err != nil {
+			return nil, "", fmt.Errorf("failed to encode form field %s: %v", key, err)
+		}
+	}
+	if b.file != nil {
+		part, err := writer.CreateFormFile(b.fileKey, b.fileName)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to encode form file: %v", err)
+		}
+		if _, err := io.Copy(part, b.file); err != nil {
+			return nil, "", fmt.Errorf("failed to encode form file: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to encode form: %v", err)
+	}
+
+	return buffer.Bytes(), writer.FormDataContentType(), nil
+}
@@ -0,0 +1,46 @@
+package toursclient
+
+import (
+	"context"
+	"io"
+)
+
+func (c *Client) ListUsers(ctx context.Context) ([]User, error) {
+	var users []User
+	err := c.call(ctx, request{endpoint: listUsersEndpoint, auth: true}, &users)
+	return users, err
+}
+
+func (c *Client) GetUser(ctx context.Context, id string) (*User, error) {
+	var user User
+	if err := c.call(ctx, request{endpoint: getUserEndpoint, params: map[string]string{"id": id}, auth: true}, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (c *Client) CreateUser(ctx context.Context, create CreateUserRequest) (*User, error) {
+	var user User
+	if err := c.call(ctx, request{endpoint: createUserEndpoint, body: create, auth: true}, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (c *Client) UpdateUser(ctx context.Context, id, name, photoName string, photo io.Reader) (*User, error) {
+	var user User
+	form := &multipartBody{
+		fields:   map[string]string{"name": name},
+		fileKey:  "photo",
+		fileName: photoName,
+		file:     photo,
+	}
+	if err := c.call(ctx, request{endpoint: updateUserEndpoint, params: map[string]string{"id": id}, multipart: form, auth: true}, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (c *Client) DeleteUser(ctx context.Context, id string) error {
+	return c.call(ctx, request{endpoint: deleteUserEndpoint, params: map[string]string{"id": id}, auth: true}, nil)
+}
@@ -0,0 +1,78 @@
+package toursclient
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DefaultWebhookTolerance = 5 * time.Minute
+
+var ErrInvalidSignature = errors.New("toursclient: invalid webhook signature")
+
+type WebhookEvent struct {
+	Id        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Data      json.RawMessage `json:"data"`
+}
+
+func VerifyWebhook(secret string, signature string, payload []byte, tolerance time.Duration) error {
+	var timestamp string
+	var digests []string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			digests = append(digests, value)
+		}
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(digests) == 0 {
+		return fmt.Errorf("%w: malformed header", ErrInvalidSignature)
+	}
+	if tolerance > 0 {
+		if age := time.Since(time.Unix(seconds, 0)); age > tolerance || age < -tolerance {
+			return fmt.Errorf("%w: timestamp outside the %s tolerance", ErrInvalidSignature, tolerance)
+		}
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%s.", timestamp)
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+
+	for _, digest := range digests {
+		if decoded, err := hex.DecodeString(digest); err == nil && hmac.Equal(decoded, expected) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+func ParseWebhook(r *http.Request, secret string) (*WebhookEvent, error) {
+	payload, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("toursclient: failed to read webhook body: %v", err)
+	}
+	if err := VerifyWebhook(secret, r.Header.Get("X-Webhook-Signature"), payload, DefaultWebhookTolerance); err != nil {
+		return nil, err
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("toursclient: failed to decode webhook: %v", err)
+	}
+	return &event, nil
+}